@@ -0,0 +1,341 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+const maxAPIPaginatePages = 1000
+
+func newAPICmd(state *appState) *cobra.Command {
+	var rawFields []string
+	var typedFields []string
+	var queryParams []string
+	var inputPath string
+	var paginate bool
+
+	cmd := &cobra.Command{
+		Use:   "api <method> <path>",
+		Short: "Send a raw OpenAPI request",
+		Long: `Send a raw OpenAPI request using the configured app, profile, and token.
+
+The path may be given with or without the /open-apis prefix and may include a
+query string. The access token follows --token-type (auto|tenant|user) and the
+base URL follows --platform/--base-url, exactly like wrapped commands.
+
+Fields:
+  -f key=value   string field
+  -F key=value   typed field (true/false/null, numbers, JSON arrays/objects;
+                 @path reads the value from a file)
+
+For GET and DELETE, fields are sent as query parameters; otherwise they form
+a JSON body. --input sends a JSON file (or - for stdin) as the body instead.
+
+--paginate follows data.page_token/has_more and merges data.items (or the
+single list field in data) across pages.`,
+		Example: `  lark api GET /open-apis/im/v1/chats -q page_size=50 --paginate
+  lark api POST im/v1/messages -q receive_id_type=chat_id -f receive_id=oc_xxx -f msg_type=text -f 'content={"text":"hi"}'
+  lark api PATCH /open-apis/docx/v1/documents/<DOC_ID>/blocks/batch_update --input body.json --token-type user`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			method := strings.ToUpper(strings.TrimSpace(args[0]))
+			switch method {
+			case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+			default:
+				return argsUsageError(cmd, fmt.Errorf("unsupported method %q (expected GET, POST, PUT, PATCH, or DELETE)", args[0]))
+			}
+			path, query, err := parseAPIPath(args[1])
+			if err != nil {
+				return argsUsageError(cmd, err)
+			}
+			for _, raw := range queryParams {
+				key, value, ok := strings.Cut(raw, "=")
+				if !ok || strings.TrimSpace(key) == "" {
+					return flagUsage(cmd, fmt.Sprintf("invalid query %q (expected key=value)", raw))
+				}
+				query.Add(strings.TrimSpace(key), value)
+			}
+			fields, err := parseAPIFields(rawFields, typedFields)
+			if err != nil {
+				return flagUsage(cmd, err.Error())
+			}
+			inputPath = strings.TrimSpace(inputPath)
+			if inputPath != "" && len(fields) > 0 {
+				return flagUsage(cmd, "--input cannot be combined with -f/-F fields")
+			}
+
+			var body any
+			queryMethod := method == http.MethodGet || method == http.MethodDelete
+			switch {
+			case inputPath != "":
+				data, err := readInputFile(inputPath)
+				if err != nil {
+					return fmt.Errorf("read input: %w", err)
+				}
+				if err := json.Unmarshal(data, &body); err != nil {
+					return fmt.Errorf("input is not valid JSON: %w", err)
+				}
+			case queryMethod:
+				for key, value := range fields {
+					query.Set(key, apiQueryValue(value))
+				}
+			case len(fields) > 0:
+				body = fields
+			}
+			if paginate && method != http.MethodGet && method != http.MethodPost {
+				return flagUsage(cmd, "--paginate requires GET or POST")
+			}
+
+			if _, err := requireSDK(state); err != nil {
+				return err
+			}
+			token, tokenTypeValue, err := resolveAccessToken(cmd.Context(), state, tokenTypesTenantOrUser, nil)
+			if err != nil {
+				return err
+			}
+			request := larksdk.RawRequest{Method: method, Path: path, Query: query, Body: body}
+			if paginate {
+				merged, err := paginateAPIRequest(cmd, state, token, larksdk.AccessTokenType(tokenTypeValue), request)
+				if err != nil {
+					return err
+				}
+				return printAPIJSON(state, merged)
+			}
+
+			resp, err := state.SDK.RawRequest(cmd.Context(), token, larksdk.AccessTokenType(tokenTypeValue), request)
+			if err != nil {
+				return err
+			}
+			var decoded any
+			if err := json.Unmarshal(resp.Body, &decoded); err != nil {
+				// Non-JSON responses (file downloads) are written as-is.
+				if _, err := state.Printer.Writer.Write(resp.Body); err != nil {
+					return err
+				}
+				if resp.StatusCode >= http.StatusBadRequest {
+					return fmt.Errorf("api request failed: HTTP %d", resp.StatusCode)
+				}
+				return nil
+			}
+			if err := printAPIJSON(state, decoded); err != nil {
+				return err
+			}
+			return apiResponseError(resp.StatusCode, decoded)
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&rawFields, "raw-field", "f", nil, "add a string field key=value (repeatable)")
+	cmd.Flags().StringArrayVarP(&typedFields, "field", "F", nil, "add a typed field key=value; @file reads the value from a file (repeatable)")
+	cmd.Flags().StringArrayVarP(&queryParams, "query", "q", nil, "add a query parameter key=value (repeatable)")
+	cmd.Flags().StringVar(&inputPath, "input", "", "JSON request body file (or - for stdin)")
+	cmd.Flags().BoolVar(&paginate, "paginate", false, "follow page_token/has_more and merge list items")
+	return cmd
+}
+
+func parseAPIPath(raw string) (string, url.Values, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return "", nil, errors.New("path is required")
+	}
+	if strings.Contains(trimmed, "://") {
+		parsed, err := url.Parse(trimmed)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid URL: %w", err)
+		}
+		trimmed = parsed.RequestURI()
+	}
+	pathPart, rawQuery, _ := strings.Cut(trimmed, "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid query string: %w", err)
+	}
+	pathPart = "/" + strings.TrimLeft(pathPart, "/")
+	if !strings.HasPrefix(pathPart, "/open-apis/") {
+		pathPart = "/open-apis" + pathPart
+	}
+	return pathPart, query, nil
+}
+
+func parseAPIFields(rawFields, typedFields []string) (map[string]any, error) {
+	fields := map[string]any{}
+	for _, raw := range rawFields {
+		key, value, ok := strings.Cut(raw, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid field %q (expected key=value)", raw)
+		}
+		fields[strings.TrimSpace(key)] = value
+	}
+	for _, raw := range typedFields {
+		key, value, ok := strings.Cut(raw, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid field %q (expected key=value)", raw)
+		}
+		if strings.HasPrefix(value, "@") {
+			data, err := readInputFile(strings.TrimPrefix(value, "@"))
+			if err != nil {
+				return nil, fmt.Errorf("read field %s: %w", key, err)
+			}
+			value = strings.TrimRight(string(data), "\r\n")
+		}
+		var typed any
+		if err := json.Unmarshal([]byte(value), &typed); err != nil {
+			typed = value
+		}
+		fields[strings.TrimSpace(key)] = typed
+	}
+	return fields, nil
+}
+
+func apiQueryValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// paginateAPIRequest follows page_token until has_more is false and merges the
+// list field of every page into the last response. After maxAPIPaginatePages
+// pages it stops with has_more and the next page_token left in the output.
+func paginateAPIRequest(cmd *cobra.Command, state *appState, token string, tokenType larksdk.AccessTokenType, request larksdk.RawRequest) (any, error) {
+	var merged map[string]any
+	var items []any
+	listKey := ""
+	pageToken := ""
+	more := false
+	for page := 0; page < maxAPIPaginatePages; page++ {
+		query := url.Values{}
+		for key, values := range request.Query {
+			query[key] = append([]string(nil), values...)
+		}
+		if pageToken != "" {
+			query.Set("page_token", pageToken)
+		}
+		pageRequest := request
+		pageRequest.Query = query
+		resp, err := state.SDK.RawRequest(cmd.Context(), token, tokenType, pageRequest)
+		if err != nil {
+			return nil, err
+		}
+		var decoded map[string]any
+		if err := json.Unmarshal(resp.Body, &decoded); err != nil {
+			return nil, fmt.Errorf("--paginate requires a JSON response (HTTP %d)", resp.StatusCode)
+		}
+		if err := apiResponseError(resp.StatusCode, decoded); err != nil {
+			if merged == nil {
+				_ = printAPIJSON(state, decoded)
+			}
+			return nil, err
+		}
+		data, _ := decoded["data"].(map[string]any)
+		if data == nil {
+			return decoded, nil
+		}
+		if merged == nil {
+			listKey = apiListKey(data)
+			if listKey == "" {
+				// Several arrays and no "items": nothing safe to merge, so return
+				// the page as the API sent it.
+				fmt.Fprintln(errWriter(state), "warning: --paginate found several list fields; returning the first page unmerged")
+				return decoded, nil
+			}
+		}
+		if list, ok := data[listKey].([]any); ok {
+			items = append(items, list...)
+		}
+		merged = decoded
+		hasMore, _ := data["has_more"].(bool)
+		next, _ := data["page_token"].(string)
+		if next == "" {
+			next, _ = data["next_page_token"].(string)
+		}
+		more = hasMore && next != "" && next != pageToken
+		if !more {
+			break
+		}
+		pageToken = next
+		if state.Verbose {
+			fmt.Fprintf(errWriter(state), "fetched page %d (%d items)\n", page+1, len(items))
+		}
+	}
+	if data, ok := merged["data"].(map[string]any); ok && listKey != "" {
+		if items == nil {
+			items = []any{}
+		}
+		data[listKey] = items
+		data["has_more"] = false
+		delete(data, "page_token")
+		delete(data, "next_page_token")
+		if more {
+			// Stopped at the page cap: keep the cursor so the caller can resume.
+			data["has_more"] = true
+			data["page_token"] = pageToken
+			fmt.Fprintf(errWriter(state), "warning: stopped after %d pages; more results remain (page_token %s)\n", maxAPIPaginatePages, pageToken)
+		}
+	}
+	return merged, nil
+}
+
+// apiListKey picks the field to merge: "items" when present, otherwise the
+// only array-valued field in data. It returns "" when several arrays make the
+// choice ambiguous.
+func apiListKey(data map[string]any) string {
+	if _, ok := data["items"]; ok {
+		return "items"
+	}
+	key := ""
+	for name, value := range data {
+		if _, ok := value.([]any); ok {
+			if key != "" {
+				return ""
+			}
+			key = name
+		}
+	}
+	if key == "" {
+		return "items"
+	}
+	return key
+}
+
+func apiResponseError(statusCode int, decoded any) error {
+	body, _ := decoded.(map[string]any)
+	code, _ := body["code"].(float64)
+	msg, _ := body["msg"].(string)
+	if code != 0 {
+		return fmt.Errorf("api request failed: code=%d msg=%s", int64(code), msg)
+	}
+	if statusCode >= http.StatusBadRequest {
+		return fmt.Errorf("api request failed: HTTP %d", statusCode)
+	}
+	return nil
+}
+
+func printAPIJSON(state *appState, value any) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	return state.Printer.Print(value, strings.TrimRight(buf.String(), "\n"))
+}
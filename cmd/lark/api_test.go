@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAPICommandGetWithQuery(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/open-apis/im/v1/chats" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Fatalf("missing authorization: %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Query().Get("page_size") != "10" || r.URL.Query().Get("user_id_type") != "open_id" {
+			t.Fatalf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"items": []any{map[string]any{"chat_id": "oc_1"}}}})
+	})
+	state, buf := newTestState(t, handler, false)
+
+	cmd := newAPICmd(state)
+	cmd.SetArgs([]string{"get", "im/v1/chats?user_id_type=open_id", "-q", "page_size=10"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("api error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"chat_id": "oc_1"`) {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestAPICommandPostFields(t *testing.T) {
+	var got map[string]any
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/open-apis/im/v1/messages" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"message_id": "om_1"}})
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newAPICmd(state)
+	cmd.SetArgs([]string{"POST", "/open-apis/im/v1/messages", "-f", "receive_id=oc_1", "-f", "count=3", "-F", "urgent=true", "-F", "size=3"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("api error: %v", err)
+	}
+	if got["receive_id"] != "oc_1" || got["count"] != "3" || got["urgent"] != true || got["size"] != float64(3) {
+		t.Fatalf("unexpected body: %v", got)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if payload["data"].(map[string]any)["message_id"] != "om_1" {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+}
+
+func TestAPICommandInputFile(t *testing.T) {
+	var got map[string]any
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch {
+			t.Fatalf("unexpected method: %s", r.Method)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{}})
+	})
+	state, _ := newTestState(t, handler, true)
+	path := filepath.Join(t.TempDir(), "body.json")
+	if err := os.WriteFile(path, []byte(`{"requests":[{"block_id":"b1"}]}`), 0o600); err != nil {
+		t.Fatalf("write input: %v", err)
+	}
+
+	cmd := newAPICmd(state)
+	cmd.SetArgs([]string{"PATCH", "docx/v1/documents/doc1/blocks/batch_update", "--input", path})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("api error: %v", err)
+	}
+	if requests, ok := got["requests"].([]any); !ok || len(requests) != 1 {
+		t.Fatalf("unexpected body: %v", got)
+	}
+}
+
+func TestAPICommandPaginateMergesItems(t *testing.T) {
+	calls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page_token") {
+		case "":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"items": []any{"a", "b"}, "has_more": true, "page_token": "p2",
+			}})
+		case "p2":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"items": []any{"c"}, "has_more": false,
+			}})
+		default:
+			t.Fatalf("unexpected page token: %s", r.URL.RawQuery)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newAPICmd(state)
+	cmd.SetArgs([]string{"GET", "/open-apis/im/v1/chats", "--paginate"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("api error: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+	var payload struct {
+		Data struct {
+			Items   []string `json:"items"`
+			HasMore bool     `json:"has_more"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if strings.Join(payload.Data.Items, ",") != "a,b,c" || payload.Data.HasMore {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestAPICommandPaginateKeepsCursorAtPageCap(t *testing.T) {
+	calls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+			"items": []any{calls}, "has_more": true, "page_token": fmt.Sprintf("p%d", calls+1),
+		}})
+	})
+	state, buf := newTestState(t, handler, true)
+	var stderr bytes.Buffer
+	state.ErrWriter = &stderr
+
+	cmd := newAPICmd(state)
+	cmd.SetArgs([]string{"GET", "/open-apis/im/v1/chats", "--paginate"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("api error: %v", err)
+	}
+	if calls != maxAPIPaginatePages {
+		t.Fatalf("expected %d calls, got %d", maxAPIPaginatePages, calls)
+	}
+	var payload struct {
+		Data struct {
+			Items     []int  `json:"items"`
+			HasMore   bool   `json:"has_more"`
+			PageToken string `json:"page_token"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	next := fmt.Sprintf("p%d", maxAPIPaginatePages+1)
+	if len(payload.Data.Items) != maxAPIPaginatePages || !payload.Data.HasMore || payload.Data.PageToken != next {
+		t.Fatalf("unexpected payload: has_more=%v page_token=%q items=%d", payload.Data.HasMore, payload.Data.PageToken, len(payload.Data.Items))
+	}
+	if !strings.Contains(stderr.String(), "more results remain") {
+		t.Fatalf("expected a warning, got %q", stderr.String())
+	}
+}
+
+func TestAPICommandPaginateLeavesAmbiguousListsUnmerged(t *testing.T) {
+	calls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+			"users": []any{"u1"}, "groups": []any{"g1"}, "has_more": true, "page_token": "p2",
+		}})
+	})
+	state, buf := newTestState(t, handler, true)
+	var stderr bytes.Buffer
+	state.ErrWriter = &stderr
+
+	cmd := newAPICmd(state)
+	cmd.SetArgs([]string{"GET", "/open-apis/contact/v3/scopes", "--paginate"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("api error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+	var payload struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if _, ok := payload.Data["items"]; ok {
+		t.Fatalf("unexpected items field: %+v", payload.Data)
+	}
+	if payload.Data["has_more"] != true || payload.Data["page_token"] != "p2" {
+		t.Fatalf("expected the page unchanged, got %+v", payload.Data)
+	}
+	if !strings.Contains(stderr.String(), "several list fields") {
+		t.Fatalf("expected a warning, got %q", stderr.String())
+	}
+}
+
+func TestAPICommandReturnsAPIError(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 99991663, "msg": "invalid param"})
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newAPICmd(state)
+	cmd.SetArgs([]string{"DELETE", "/open-apis/im/v1/messages/om_1"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "invalid param") {
+		t.Fatalf("expected api error, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "99991663") {
+		t.Fatalf("error body should be printed: %q", buf.String())
+	}
+}
+
+func TestParseAPIPath(t *testing.T) {
+	path, query, err := parseAPIPath("https://open.feishu.cn/open-apis/im/v1/chats?page_size=5")
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if path != "/open-apis/im/v1/chats" || query.Get("page_size") != "5" {
+		t.Fatalf("unexpected result: %s %v", path, query)
+	}
+}
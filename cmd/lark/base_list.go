@@ -48,8 +48,7 @@ func newBaseAppListCmd(state *appState) *cobra.Command {
 
 func newBaseDiscoverListCmd(state *appState, use, short string) *cobra.Command {
 	var query string
-	var limit int
-	var pages int
+	var paging listPaging
 
 	cmd := &cobra.Command{
 		Use:   fmt.Sprintf("%s <query>", use),
@@ -71,11 +70,8 @@ func newBaseDiscoverListCmd(state *appState, use, short string) *cobra.Command {
 			if _, err := requireSDK(state); err != nil {
 				return err
 			}
-			if limit <= 0 {
-				return errors.New("limit must be greater than 0")
-			}
-			if pages <= 0 {
-				return errors.New("pages must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			ctx := cmd.Context()
 			userToken, err := resolveDriveSearchToken(ctx, state)
@@ -83,10 +79,13 @@ func newBaseDiscoverListCmd(state *appState, use, short string) *cobra.Command {
 				return err
 			}
 
-			files, err := searchBitableFiles(ctx, state, userToken, query, limit, pages)
-			if err != nil {
+			// Drive results are mapped to bases after fetching, so this
+			// listing is buffered even with --output ndjson.
+			collected := &listCollector[larksdk.DriveFile]{state: state, limit: paging.itemLimit()}
+			if err := searchBitableFiles(ctx, state, userToken, query, collected, paging.pageLimit()); err != nil {
 				return err
 			}
+			files := collected.items
 
 			items := make([]baseListItem, 0, len(files))
 			lines := make([]string, 0, len(files))
@@ -106,62 +105,30 @@ func newBaseDiscoverListCmd(state *appState, use, short string) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().IntVar(&limit, "limit", 50, "max number of bases to return")
-	cmd.Flags().IntVar(&pages, "pages", 1, "max number of pages to fetch")
+	cmd.Flags().IntVar(&paging.limit, "limit", 50, "max number of bases to return")
+	cmd.Flags().IntVar(&paging.pages, "pages", 1, "max number of pages to fetch")
+	addPagingFlags(cmd, &paging)
 	return cmd
 }
 
-func searchBitableFiles(ctx context.Context, state *appState, userToken, query string, limit, pages int) ([]larksdk.DriveFile, error) {
+func searchBitableFiles(ctx context.Context, state *appState, userToken, query string, files *listCollector[larksdk.DriveFile], maxPages int) error {
 	if state == nil {
-		return nil, errors.New("state is required")
+		return errors.New("state is required")
 	}
 	if state.SDK == nil {
-		return nil, errors.New("sdk client is required")
-	}
-	if limit <= 0 {
-		return nil, errors.New("limit must be greater than 0")
+		return errors.New("sdk client is required")
 	}
-	if pages <= 0 {
-		return nil, errors.New("pages must be greater than 0")
-	}
-
-	files := make([]larksdk.DriveFile, 0, limit)
-	pageToken := ""
-	remaining := limit
-	pageCount := 0
-	for {
-		if pageCount >= pages {
-			break
-		}
-		pageCount++
-
-		pageSize := remaining
-		if pageSize > maxDrivePageSize {
-			pageSize = maxDrivePageSize
-		}
-
+	pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.DriveFile], error) {
 		result, err := state.SDK.SearchDriveFilesWithUserToken(ctx, userToken, larksdk.SearchDriveFilesRequest{
 			Query:     query,
 			FileTypes: []string{"bitable"},
-			PageSize:  pageSize,
+			PageSize:  files.pageSize(maxDrivePageSize),
 			PageToken: pageToken,
 		})
 		if err != nil {
-			return nil, withUserScopeHintForCommand(state, err)
-		}
-		files = append(files, result.Files...)
-		if len(files) >= limit || !result.HasMore {
-			break
-		}
-		remaining = limit - len(files)
-		pageToken = result.PageToken
-		if pageToken == "" {
-			break
+			return larksdk.Page[larksdk.DriveFile]{}, withUserScopeHintForCommand(state, err)
 		}
-	}
-
-	if len(files) > limit {
-		files = files[:limit]
-	}
-	return files, nil
+		return larksdk.Page[larksdk.DriveFile]{Items: result.Files, PageToken: result.PageToken, HasMore: result.HasMore}, nil
+	})
+	return drainPages(ctx, state, files, pager, maxPages)
 }
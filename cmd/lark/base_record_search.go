@@ -14,6 +14,8 @@ import (
 	"lark/internal/larksdk"
 )
 
+const maxBaseRecordSearchPageSize = 500
+
 func newBaseRecordSearchCmd(state *appState) *cobra.Command {
 	var appToken string
 	var tableID string
@@ -21,7 +23,7 @@ func newBaseRecordSearchCmd(state *appState) *cobra.Command {
 	var filterJSON string
 	var sortJSON string
 	var fieldsCSV string
-	var limit int
+	var paging listPaging
 
 	cmd := &cobra.Command{
 		Use:   "search <table-id>",
@@ -37,6 +39,9 @@ func newBaseRecordSearchCmd(state *appState) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := paging.validate(cmd); err != nil {
+				return err
+			}
 			if cmd.Flags().Changed("filter") && cmd.Flags().Changed("filter-json") {
 				return usageError(cmd, "filter and filter-json cannot both be set", "Use only one of --filter or --filter-json.")
 			}
@@ -53,7 +58,6 @@ func newBaseRecordSearchCmd(state *appState) *cobra.Command {
 				automaticFields := true
 				req := larksdk.SearchBaseRecordsRequest{
 					ViewID:          viewID,
-					PageSize:        limit,
 					AutomaticFields: &automaticFields,
 				}
 				fieldNames, err := parseBaseRecordSearchFieldNames(fieldsCSV)
@@ -70,11 +74,20 @@ func newBaseRecordSearchCmd(state *appState) *cobra.Command {
 					req.Sort = json.RawMessage(sortJSON)
 				}
 
-				result, err := sdk.SearchBaseRecords(ctx, token, appToken, tableID, req)
-				if err != nil {
+				collected := newListCollector[larksdk.BaseRecord](state, paging.itemLimit())
+				pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.BaseRecord], error) {
+					req.PageSize = collected.pageSize(maxBaseRecordSearchPageSize)
+					req.PageToken = pageToken
+					result, err := sdk.SearchBaseRecords(ctx, token, appToken, tableID, req)
+					return larksdk.Page[larksdk.BaseRecord]{Items: result.Items, PageToken: result.PageToken, HasMore: result.HasMore}, err
+				})
+				if err := drainPages(ctx, state, collected, pager, 0); err != nil {
 					return nil, "", err
 				}
-				records := result.Items
+				if collected.streaming {
+					return nil, "", nil
+				}
+				records := collected.items
 				payload := map[string]any{"records": records}
 				headers := buildBaseRecordSearchHeaders(fieldNames, records)
 				rows := make([][]string, 0, len(records))
@@ -100,7 +113,8 @@ func newBaseRecordSearchCmd(state *appState) *cobra.Command {
 	cmd.Flags().StringVar(&filterJSON, "filter-json", "", "Record filter JSON (raw)")
 	cmd.Flags().StringVar(&sortJSON, "sort", "", "Record sort JSON")
 	cmd.Flags().StringVar(&sortJSON, "sort-json", "", "Record sort JSON (raw)")
-	cmd.Flags().IntVar(&limit, "limit", 20, "max records to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 20, "max records to return")
+	addPagingFlags(cmd, &paging)
 	_ = cmd.MarkFlagRequired("app-token")
 	return cmd
 }
@@ -36,19 +36,24 @@ Canonical command name: calendars (alias: calendar).`,
 	return cmd
 }
 
+const (
+	minCalendarEventsPageSize = 50
+	maxCalendarEventsPageSize = 1000
+)
+
 func newCalendarListCmd(state *appState) *cobra.Command {
 	var start string
 	var end string
 	var calendarID string
-	var limit int
+	var paging listPaging
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List events",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				return flagUsage(cmd, "limit must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			var startTime time.Time
 			var endTime time.Time
@@ -81,49 +86,32 @@ func newCalendarListCmd(state *appState) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			events := make([]larksdk.CalendarEvent, 0, limit)
-			pageToken := ""
-			remaining := limit
-			for {
-				pageSize := remaining
-				if pageSize < 50 {
-					pageSize = 50
-				}
-				if pageSize > 1000 {
-					pageSize = 1000
-				}
+			events := newListCollector[larksdk.CalendarEvent](state, paging.itemLimit())
+			pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.CalendarEvent], error) {
 				req := larksdk.ListCalendarEventsRequest{
 					CalendarID: resolvedCalendarID,
-					PageSize:   pageSize,
+					PageSize:   max(events.pageSize(maxCalendarEventsPageSize), minCalendarEventsPageSize),
 					PageToken:  pageToken,
 				}
 				if start != "" {
 					req.StartTime = strconv.FormatInt(startTime.Unix(), 10)
 					req.EndTime = strconv.FormatInt(endTime.Unix(), 10)
 				}
-				result, err := state.SDK.ListCalendarEvents(cmd.Context(), token, larksdk.AccessTokenType(tokenType), req)
-				if err != nil {
-					return err
-				}
-				events = append(events, result.Items...)
-				if len(events) >= limit || !result.HasMore {
-					break
-				}
-				remaining = limit - len(events)
-				if remaining <= 0 || result.PageToken == "" {
-					break
-				}
-				pageToken = result.PageToken
+				result, err := state.SDK.ListCalendarEvents(ctx, token, larksdk.AccessTokenType(tokenType), req)
+				return larksdk.Page[larksdk.CalendarEvent]{Items: result.Items, PageToken: result.PageToken, HasMore: result.HasMore}, err
+			})
+			if err := drainPages(cmd.Context(), state, events, pager, 0); err != nil {
+				return err
 			}
-			if len(events) > limit {
-				events = events[:limit]
+			if events.streaming {
+				return nil
 			}
 			payload := map[string]any{
 				"calendar_id": resolvedCalendarID,
-				"events":      events,
+				"events":      events.items,
 			}
-			lines := make([]string, 0, len(events))
-			for _, event := range events {
+			lines := make([]string, 0, len(events.items))
+			for _, event := range events.items {
 				lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s\t%s", event.EventID, formatEventTime(event.StartTime), formatEventTime(event.EndTime), event.Summary, event.Status))
 			}
 			text := tableText([]string{"event_id", "start_time", "end_time", "summary", "status"}, lines, "no events found")
@@ -134,7 +122,8 @@ func newCalendarListCmd(state *appState) *cobra.Command {
 	cmd.Flags().StringVar(&start, "start", "", "start time (RFC3339)")
 	cmd.Flags().StringVar(&end, "end", "", "end time (RFC3339)")
 	cmd.Flags().StringVar(&calendarID, "calendar-id", "", "calendar ID (default: primary)")
-	cmd.Flags().IntVar(&limit, "limit", 50, "max number of events to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 50, "max number of events to return")
+	addPagingFlags(cmd, &paging)
 
 	return cmd
 }
@@ -343,7 +332,7 @@ func newCalendarSearchCmd(state *appState) *cobra.Command {
 	var start string
 	var end string
 	var calendarID string
-	var limit int
+	var paging listPaging
 	var userIDs []string
 	var roomIDs []string
 	var chatIDs []string
@@ -362,8 +351,8 @@ func newCalendarSearchCmd(state *appState) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				return flagUsage(cmd, "limit must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			var startTime time.Time
 			var endTime time.Time
@@ -397,56 +386,38 @@ func newCalendarSearchCmd(state *appState) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			events := make([]larksdk.CalendarEvent, 0, limit)
-			pageToken := ""
-			remaining := limit
-			for {
-				pageSize := remaining
-				if pageSize < 50 {
-					pageSize = 50
-				}
-				if pageSize > 1000 {
-					pageSize = 1000
-				}
+			events := newListCollector[larksdk.CalendarEvent](state, paging.itemLimit())
+			pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.CalendarEvent], error) {
 				req := larksdk.SearchCalendarEventsRequest{
 					CalendarID: resolvedCalendarID,
 					Query:      query,
 					UserIDs:    userIDs,
 					RoomIDs:    roomIDs,
 					ChatIDs:    chatIDs,
-					PageSize:   pageSize,
+					PageSize:   max(events.pageSize(maxCalendarEventsPageSize), minCalendarEventsPageSize),
 					PageToken:  pageToken,
 				}
 				if start != "" {
 					req.StartTime = strconv.FormatInt(startTime.Unix(), 10)
 					req.EndTime = strconv.FormatInt(endTime.Unix(), 10)
 				}
-				result, err := state.SDK.SearchCalendarEvents(cmd.Context(), token, larksdk.AccessTokenType(tokenType), req)
-				if err != nil {
-					return err
-				}
-				events = append(events, result.Items...)
-				if len(events) >= limit || result.PageToken == "" || result.PageToken == pageToken {
-					pageToken = result.PageToken
-					break
-				}
-				remaining = limit - len(events)
-				if remaining <= 0 {
-					pageToken = result.PageToken
-					break
-				}
-				pageToken = result.PageToken
+				result, err := state.SDK.SearchCalendarEvents(ctx, token, larksdk.AccessTokenType(tokenType), req)
+				// Event search has no has_more flag; a page token means more.
+				return larksdk.Page[larksdk.CalendarEvent]{Items: result.Items, PageToken: result.PageToken, HasMore: result.PageToken != ""}, err
+			})
+			if err := drainPages(cmd.Context(), state, events, pager, 0); err != nil {
+				return err
 			}
-			if len(events) > limit {
-				events = events[:limit]
+			if events.streaming {
+				return nil
 			}
 			payload := map[string]any{
 				"calendar_id": resolvedCalendarID,
-				"events":      events,
-				"page_token":  pageToken,
+				"events":      events.items,
+				"page_token":  pager.PageToken(),
 			}
-			lines := make([]string, 0, len(events))
-			for _, event := range events {
+			lines := make([]string, 0, len(events.items))
+			for _, event := range events.items {
 				lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s\t%s", event.EventID, formatEventTime(event.StartTime), formatEventTime(event.EndTime), event.Summary, event.Status))
 			}
 			text := tableText([]string{"event_id", "start_time", "end_time", "summary", "status"}, lines, "no events found")
@@ -457,7 +428,8 @@ func newCalendarSearchCmd(state *appState) *cobra.Command {
 	cmd.Flags().StringVar(&start, "start", "", "start time (RFC3339)")
 	cmd.Flags().StringVar(&end, "end", "", "end time (RFC3339)")
 	cmd.Flags().StringVar(&calendarID, "calendar-id", "", "calendar ID (default: primary)")
-	cmd.Flags().IntVar(&limit, "limit", 20, "max number of events to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 20, "max number of events to return")
+	addPagingFlags(cmd, &paging)
 	cmd.Flags().StringArrayVar(&userIDs, "user-id", nil, "filter by attendee user id (repeatable)")
 	cmd.Flags().StringArrayVar(&roomIDs, "room-id", nil, "filter by room id (repeatable)")
 	cmd.Flags().StringArrayVar(&chatIDs, "chat-id", nil, "filter by chat id (repeatable)")
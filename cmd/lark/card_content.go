@@ -0,0 +1,450 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// cardHeaderTemplates are the header colors accepted by interactive cards.
+var cardHeaderTemplates = []string{"blue", "wathet", "turquoise", "green", "yellow", "orange", "red", "carmine", "violet", "purple", "indigo", "grey", "default"}
+
+// cardElementTags lists the component tags accepted in card elements (card
+// JSON 1.0 and 2.0).
+var cardElementTags = map[string]bool{
+	"action": true, "button": true, "chart": true, "checker": true, "collapsible_panel": true,
+	"column": true, "column_set": true, "date_picker": true, "div": true, "form": true,
+	"hr": true, "img": true, "img_combination": true, "input": true, "interactive_container": true, "lark_md": true,
+	"markdown": true, "multi_select_person": true, "multi_select_static": true, "note": true,
+	"overflow": true, "person": true, "person_list": true, "picker_datetime": true, "picker_time": true,
+	"plain_text": true, "select_img": true, "select_person": true, "select_static": true,
+	"standard_icon": true, "table": true, "text_tag": true,
+}
+
+// cardContentOptions selects the card for cards send and messages update:
+// either a card file or a card-builder template with variables.
+type cardContentOptions struct {
+	Card            string
+	TemplateID      string
+	TemplateVersion string
+	Vars            []string
+}
+
+func addCardContentFlags(cmd *cobra.Command, opts *cardContentOptions) {
+	cmd.Flags().StringVar(&opts.Card, "card", "", "card JSON or YAML shorthand file (or - for stdin)")
+	cmd.Flags().StringVar(&opts.TemplateID, "template-id", "", "card-builder template ID")
+	cmd.Flags().StringVar(&opts.TemplateVersion, "template-version", "", "card-builder template version (default: latest published)")
+	cmd.Flags().StringArrayVar(&opts.Vars, "var", nil, "template variable <name>=<string> or <name>:=<json> (repeatable)")
+	cmd.MarkFlagsMutuallyExclusive("card", "template-id")
+}
+
+// resolveCardContent returns the interactive message content for opts.
+func resolveCardContent(opts cardContentOptions) (string, error) {
+	templateID := strings.TrimSpace(opts.TemplateID)
+	cardPath := strings.TrimSpace(opts.Card)
+	switch {
+	case templateID != "":
+		vars, err := parseCardVars(opts.Vars)
+		if err != nil {
+			return "", err
+		}
+		data := map[string]any{"template_id": templateID}
+		if version := strings.TrimSpace(opts.TemplateVersion); version != "" {
+			data["template_version_name"] = version
+		}
+		if len(vars) > 0 {
+			data["template_variable"] = vars
+		}
+		raw, err := json.Marshal(map[string]any{"type": "template", "data": data})
+		return string(raw), err
+	case cardPath != "":
+		if len(opts.Vars) > 0 || strings.TrimSpace(opts.TemplateVersion) != "" {
+			return "", errors.New("--var and --template-version require --template-id")
+		}
+		card, err := loadCard(cardPath)
+		if err != nil {
+			return "", err
+		}
+		raw, err := json.Marshal(card)
+		return string(raw), err
+	default:
+		return "", errors.New("card content is required (use --card or --template-id)")
+	}
+}
+
+// parseCardVars parses --var assignments; := values are decoded as JSON so
+// list and object variables can be passed.
+func parseCardVars(entries []string) (map[string]any, error) {
+	vars := map[string]any{}
+	for _, entry := range entries {
+		key, raw, isJSON, err := parseAssignmentToken(entry)
+		if err != nil || key == "" {
+			return nil, fmt.Errorf("invalid --var %q (expected name=value or name:=json)", entry)
+		}
+		value, err := parseAssignmentValue(raw, isJSON)
+		if err != nil {
+			return nil, fmt.Errorf("--var %s: %w", key, err)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// loadCard reads a card file (or - for stdin), expands the YAML shorthand if
+// used, and validates the result.
+func loadCard(path string) (map[string]any, error) {
+	data, err := readInputFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read card: %w", err)
+	}
+	card, err := parseCard(data)
+	if err != nil {
+		return nil, err
+	}
+	if problems := validateCard(card); len(problems) > 0 {
+		return nil, fmt.Errorf("invalid card:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return card, nil
+}
+
+// parseCard decodes card JSON, or YAML which may use the shorthand form
+// (see expandCardShorthand).
+func parseCard(data []byte) (map[string]any, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errors.New("card is empty")
+	}
+	var card map[string]any
+	if trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &card); err != nil {
+			return nil, fmt.Errorf("invalid card JSON: %w", err)
+		}
+		return card, nil
+	}
+	var decoded any
+	if err := yaml.Unmarshal(trimmed, &decoded); err != nil {
+		return nil, fmt.Errorf("invalid card YAML: %w", err)
+	}
+	card, ok := decoded.(map[string]any)
+	if !ok {
+		return nil, errors.New("card must be an object")
+	}
+	if isCardShorthand(card) {
+		return expandCardShorthand(card)
+	}
+	return card, nil
+}
+
+// isCardShorthand reports whether a YAML card uses shorthand keys instead of
+// the card JSON layout.
+func isCardShorthand(card map[string]any) bool {
+	for _, key := range []string{"header", "body", "schema", "type", "i18n_elements"} {
+		if _, ok := card[key]; ok {
+			return false
+		}
+	}
+	for _, key := range []string{"title", "color", "wide", "update"} {
+		if _, ok := card[key]; ok {
+			return true
+		}
+	}
+	elements, _ := card["elements"].([]any)
+	for _, element := range elements {
+		object, ok := element.(map[string]any)
+		if !ok {
+			return true
+		}
+		if _, ok := object["tag"]; !ok {
+			return true
+		}
+	}
+	return false
+}
+
+// expandCardShorthand turns the YAML shorthand into card JSON 1.0:
+//
+//	title: "Deploy #42"        # header title
+//	color: green               # header template
+//	wide: true                 # config.wide_screen_mode
+//	update: true               # config.update_multi (default true)
+//	elements:
+//	  - "**Status**: running"  # markdown
+//	  - hr
+//	  - fields: ["**Env**\nprod", "**By**\nada"]
+//	  - note: started 10:00
+//	  - image: img_xxx
+//	  - button: Open logs
+//	    url: https://ci.example.com/42
+//	    type: primary
+//
+// Elements that already carry a tag are passed through unchanged.
+func expandCardShorthand(short map[string]any) (map[string]any, error) {
+	for key := range short {
+		switch key {
+		case "title", "color", "wide", "update", "elements":
+		default:
+			return nil, fmt.Errorf("card shorthand: unknown key %q", key)
+		}
+	}
+	config := map[string]any{"wide_screen_mode": true, "update_multi": true}
+	for key, field := range map[string]string{"wide": "wide_screen_mode", "update": "update_multi"} {
+		if value, ok := short[key]; ok {
+			flag, ok := value.(bool)
+			if !ok {
+				return nil, fmt.Errorf("card shorthand: %s must be true or false", key)
+			}
+			config[field] = flag
+		}
+	}
+	card := map[string]any{"config": config}
+	if title, ok := short["title"]; ok {
+		header := map[string]any{"title": map[string]any{"tag": "plain_text", "content": fmt.Sprint(title)}}
+		if color, ok := short["color"]; ok {
+			header["template"] = fmt.Sprint(color)
+		}
+		card["header"] = header
+	}
+	raw, _ := short["elements"].([]any)
+	if _, ok := short["elements"]; ok && raw == nil {
+		return nil, errors.New("card shorthand: elements must be a list")
+	}
+	elements := make([]any, 0, len(raw))
+	for i, item := range raw {
+		element, err := expandCardShorthandElement(item)
+		if err != nil {
+			return nil, fmt.Errorf("card shorthand: elements[%d]: %w", i, err)
+		}
+		elements = append(elements, element)
+	}
+	card["elements"] = elements
+	return card, nil
+}
+
+func expandCardShorthandElement(item any) (any, error) {
+	if text, ok := item.(string); ok {
+		if text == "hr" || text == "---" {
+			return map[string]any{"tag": "hr"}, nil
+		}
+		return map[string]any{"tag": "markdown", "content": text}, nil
+	}
+	object, ok := item.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unsupported element %v", item)
+	}
+	if _, ok := object["tag"]; ok {
+		return object, nil
+	}
+	switch {
+	case object["markdown"] != nil:
+		return map[string]any{"tag": "markdown", "content": fmt.Sprint(object["markdown"])}, nil
+	case object["note"] != nil:
+		return map[string]any{"tag": "note", "elements": []any{map[string]any{"tag": "lark_md", "content": fmt.Sprint(object["note"])}}}, nil
+	case object["image"] != nil:
+		alt := ""
+		if object["alt"] != nil {
+			alt = fmt.Sprint(object["alt"])
+		}
+		return map[string]any{"tag": "img", "img_key": fmt.Sprint(object["image"]), "alt": map[string]any{"tag": "plain_text", "content": alt}}, nil
+	case object["fields"] != nil:
+		list, ok := object["fields"].([]any)
+		if !ok {
+			return nil, errors.New("fields must be a list")
+		}
+		fields := make([]any, 0, len(list))
+		for _, field := range list {
+			fields = append(fields, map[string]any{"is_short": true, "text": map[string]any{"tag": "lark_md", "content": fmt.Sprint(field)}})
+		}
+		return map[string]any{"tag": "div", "fields": fields}, nil
+	case object["button"] != nil:
+		return map[string]any{"tag": "action", "actions": []any{shorthandButton(object, fmt.Sprint(object["button"]))}}, nil
+	case object["buttons"] != nil:
+		list, ok := object["buttons"].([]any)
+		if !ok {
+			return nil, errors.New("buttons must be a list")
+		}
+		actions := make([]any, 0, len(list))
+		for j, entry := range list {
+			button, ok := entry.(map[string]any)
+			if !ok || button["text"] == nil {
+				return nil, fmt.Errorf("buttons[%d] needs text", j)
+			}
+			actions = append(actions, shorthandButton(button, fmt.Sprint(button["text"])))
+		}
+		return map[string]any{"tag": "action", "actions": actions}, nil
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return nil, fmt.Errorf("unknown shorthand element with keys %s (expected markdown, note, image, fields, button, buttons, or a tag)", strings.Join(keys, ", "))
+}
+
+func shorthandButton(spec map[string]any, text string) map[string]any {
+	button := map[string]any{"tag": "button", "text": map[string]any{"tag": "plain_text", "content": text}, "type": "default"}
+	if spec["type"] != nil {
+		button["type"] = fmt.Sprint(spec["type"])
+	}
+	if spec["url"] != nil {
+		button["url"] = fmt.Sprint(spec["url"])
+	}
+	if spec["value"] != nil {
+		button["value"] = spec["value"]
+	}
+	return button
+}
+
+// validateCard checks the card structure the API rejects most often: the
+// header title, element tags, and required fields of common components.
+// It returns one problem per line, prefixed with the JSON path.
+func validateCard(card map[string]any) []string {
+	var problems []string
+	add := func(path, format string, args ...any) {
+		problems = append(problems, path+": "+fmt.Sprintf(format, args...))
+	}
+	if card["type"] == "template" {
+		data, _ := card["data"].(map[string]any)
+		if id, _ := data["template_id"].(string); strings.TrimSpace(id) == "" {
+			add("data.template_id", "is required for template cards")
+		}
+		return problems
+	}
+	if header, ok := card["header"]; ok {
+		validateCardHeader(header, add)
+	}
+	if card["schema"] == "2.0" {
+		body, ok := card["body"].(map[string]any)
+		if !ok {
+			add("body", "is required for schema 2.0")
+			return problems
+		}
+		validateCardElements("body.elements", body["elements"], true, add)
+		return problems
+	}
+	if i18n, ok := card["i18n_elements"].(map[string]any); ok {
+		for _, lang := range sortedKeys(i18n) {
+			validateCardElements("i18n_elements."+lang, i18n[lang], true, add)
+		}
+		return problems
+	}
+	validateCardElements("elements", card["elements"], true, add)
+	return problems
+}
+
+func validateCardHeader(value any, add func(string, string, ...any)) {
+	header, ok := value.(map[string]any)
+	if !ok {
+		add("header", "must be an object")
+		return
+	}
+	title, ok := header["title"].(map[string]any)
+	if !ok {
+		add("header.title", "is required")
+	} else {
+		validateCardText("header.title", title, add)
+	}
+	if template, ok := header["template"]; ok {
+		name, _ := template.(string)
+		found := false
+		for _, allowed := range cardHeaderTemplates {
+			if name == allowed {
+				found = true
+			}
+		}
+		if !found {
+			add("header.template", "%v is not one of %s", template, strings.Join(cardHeaderTemplates, ", "))
+		}
+	}
+}
+
+func validateCardText(path string, text map[string]any, add func(string, string, ...any)) {
+	if tag, _ := text["tag"].(string); tag != "plain_text" && tag != "lark_md" {
+		add(path+".tag", "must be plain_text or lark_md")
+	}
+	if _, ok := text["content"].(string); !ok {
+		if _, i18n := text["i18n"]; !i18n {
+			add(path+".content", "is required")
+		}
+	}
+}
+
+func validateCardElements(path string, value any, required bool, add func(string, string, ...any)) {
+	if value == nil {
+		if required {
+			add(path, "is required")
+		}
+		return
+	}
+	elements, ok := value.([]any)
+	if !ok {
+		add(path, "must be a list")
+		return
+	}
+	for i, item := range elements {
+		validateCardElement(fmt.Sprintf("%s[%d]", path, i), item, add)
+	}
+}
+
+func validateCardElement(path string, value any, add func(string, string, ...any)) {
+	element, ok := value.(map[string]any)
+	if !ok {
+		add(path, "must be an object")
+		return
+	}
+	tag, _ := element["tag"].(string)
+	if tag == "" {
+		add(path+".tag", "is required")
+		return
+	}
+	if !cardElementTags[tag] {
+		add(path+".tag", "unknown tag %q", tag)
+		return
+	}
+	switch tag {
+	case "markdown":
+		if _, ok := element["content"].(string); !ok {
+			add(path+".content", "is required")
+		}
+	case "div":
+		if element["text"] == nil && element["fields"] == nil {
+			add(path, "div needs text or fields")
+		}
+		if text, ok := element["text"].(map[string]any); ok {
+			validateCardText(path+".text", text, add)
+		}
+	case "img":
+		if key, _ := element["img_key"].(string); key == "" {
+			add(path+".img_key", "is required")
+		}
+	case "button":
+		if text, ok := element["text"].(map[string]any); ok {
+			validateCardText(path+".text", text, add)
+		} else {
+			add(path+".text", "is required")
+		}
+	case "action":
+		validateCardElements(path+".actions", element["actions"], true, add)
+	case "note", "form", "collapsible_panel", "interactive_container", "column":
+		validateCardElements(path+".elements", element["elements"], tag == "note", add)
+	case "column_set":
+		columns, _ := element["columns"].([]any)
+		for i, column := range columns {
+			validateCardElement(fmt.Sprintf("%s.columns[%d]", path, i), column, add)
+		}
+	}
+}
+
+func sortedKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
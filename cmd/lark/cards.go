@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+func newCardsCmd(state *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cards",
+		Short: "Build and send interactive cards",
+		Long: `Cards are interactive messages (msg_type=interactive).
+
+- A card is card JSON, or a YAML shorthand that expands to card JSON.
+- Card-builder templates are sent by template ID with variables.
+- Update a sent card in place with messages update --card.`,
+	}
+	annotateAuthServices(cmd, "im")
+	cmd.AddCommand(newCardsRenderCmd(state))
+	cmd.AddCommand(newCardsSendCmd(state))
+	return cmd
+}
+
+func newCardsRenderCmd(state *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render <file|->",
+		Short: "Validate a card and print its JSON",
+		Long: `Validate card JSON or the YAML shorthand and print the card JSON that would be sent.
+
+YAML shorthand:
+
+  title: "Deploy #42"
+  color: green
+  elements:
+    - "**Status**: running"
+    - hr
+    - fields: ["**Env**\nprod", "**By**\nada"]
+    - note: started 10:00
+    - button: Open logs
+      url: https://ci.example.com/42`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			card, err := loadCard(strings.TrimSpace(args[0]))
+			if err != nil {
+				return err
+			}
+			raw, err := json.MarshalIndent(card, "", "  ")
+			if err != nil {
+				return err
+			}
+			return state.Printer.Print(card, string(raw))
+		},
+	}
+	return cmd
+}
+
+func newCardsSendCmd(state *appState) *cobra.Command {
+	var receiveID string
+	var receiveIDType string
+	var uuid string
+	var cardOpts cardContentOptions
+
+	cmd := &cobra.Command{
+		Use:   "send <receive-id>",
+		Short: "Send a card or card template",
+		Example: `  lark cards send <CHAT_ID> --card ./status.yaml
+  lark cards send <CHAT_ID> --template-id AAqk1234 --var env=prod --var steps:='["build","test"]'`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			receiveID = strings.TrimSpace(args[0])
+			if receiveID == "" {
+				return argsUsageError(cmd, errors.New("receive-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			normalizedType, ok := normalizeReceiveIDType(receiveIDType)
+			if !ok {
+				return flagUsage(cmd, "receive-id-type must be one of chat_id, open_id, user_id, email")
+			}
+			content, err := resolveCardContent(cardOpts)
+			if err != nil {
+				return err
+			}
+			if _, err := requireSDK(state); err != nil {
+				return err
+			}
+			token, err := tokenFor(cmd.Context(), state, tokenTypesTenantOrUser)
+			if err != nil {
+				return err
+			}
+			messageID, err := state.SDK.SendMessage(cmd.Context(), token, larksdk.MessageRequest{
+				ReceiveID:     receiveID,
+				ReceiveIDType: normalizedType,
+				MsgType:       "interactive",
+				Content:       content,
+				UUID:          strings.TrimSpace(uuid),
+			})
+			if err != nil {
+				return err
+			}
+			payload := map[string]any{"message_id": messageID}
+			return state.Printer.Print(payload, fmt.Sprintf("message_id: %s", messageID))
+		},
+	}
+
+	cmd.Flags().StringVar(&receiveIDType, "receive-id-type", "chat_id", "receive ID type (chat_id, open_id, user_id, email)")
+	cmd.Flags().StringVar(&uuid, "uuid", "", "request UUID for idempotency")
+	addCardContentFlags(cmd, &cardOpts)
+	registerEnumCompletion(cmd, "receive-id-type", receiveIDTypeValues)
+	return cmd
+}
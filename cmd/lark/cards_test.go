@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseCardShorthand(t *testing.T) {
+	src := `title: "Deploy #42"
+color: green
+elements:
+  - "**Stage**: build"
+  - hr
+  - fields: ["**Env**\nprod"]
+  - note: started
+  - button: Open
+    url: https://ci.example.com/42
+    type: primary
+`
+	card, err := parseCard([]byte(src))
+	if err != nil {
+		t.Fatalf("parseCard error: %v", err)
+	}
+	if problems := validateCard(card); len(problems) > 0 {
+		t.Fatalf("unexpected problems: %v", problems)
+	}
+	raw, _ := json.Marshal(card)
+	want := `{"config":{"update_multi":true,"wide_screen_mode":true},"elements":[` +
+		`{"content":"**Stage**: build","tag":"markdown"},{"tag":"hr"},` +
+		`{"fields":[{"is_short":true,"text":{"content":"**Env**\nprod","tag":"lark_md"}}],"tag":"div"},` +
+		`{"elements":[{"content":"started","tag":"lark_md"}],"tag":"note"},` +
+		`{"actions":[{"tag":"button","text":{"content":"Open","tag":"plain_text"},"type":"primary","url":"https://ci.example.com/42"}],"tag":"action"}],` +
+		`"header":{"template":"green","title":{"content":"Deploy #42","tag":"plain_text"}}}`
+	if string(raw) != want {
+		t.Fatalf("unexpected card:\n%s", raw)
+	}
+}
+
+func TestValidateCardReportsPaths(t *testing.T) {
+	card, err := parseCard([]byte(`{"header":{"title":{"tag":"plain_text","content":"x"},"template":"pink"},"elements":[{"tag":"markdown"},{"tag":"bogus"},{"tag":"action","actions":[{"tag":"button"}]}]}`))
+	if err != nil {
+		t.Fatalf("parseCard error: %v", err)
+	}
+	got := strings.Join(validateCard(card), "\n")
+	for _, want := range []string{
+		"header.template: pink is not one of",
+		"elements[0].content: is required",
+		`elements[1].tag: unknown tag "bogus"`,
+		"elements[2].actions[0].text: is required",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestCardsSendTemplateWithVars(t *testing.T) {
+	var sent map[string]string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/open-apis/im/v1/messages" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Fatalf("decode message: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"message_id": "om_card"}})
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newCardsCmd(state)
+	cmd.SetArgs([]string{"send", "oc_1", "--template-id", "AAqk", "--var", "env=prod", "--var", "steps:=[\"build\",\"test\"]"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if sent["msg_type"] != "interactive" {
+		t.Fatalf("unexpected msg_type: %q", sent["msg_type"])
+	}
+	want := `{"data":{"template_id":"AAqk","template_variable":{"env":"prod","steps":["build","test"]}},"type":"template"}`
+	if sent["content"] != want {
+		t.Fatalf("unexpected content: %s", sent["content"])
+	}
+	if !strings.Contains(buf.String(), "om_card") {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestMsgUpdatePatchesCard(t *testing.T) {
+	cardPath := filepath.Join(t.TempDir(), "status.yaml")
+	if err := os.WriteFile(cardPath, []byte("title: Deploy\nelements:\n  - done\n"), 0o600); err != nil {
+		t.Fatalf("write card: %v", err)
+	}
+	var body string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/open-apis/im/v1/messages/om_1" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		raw, _ := io.ReadAll(r.Body)
+		body = string(raw)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0})
+	})
+	state, _ := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"update", "om_1", "--card", cardPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("update error: %v", err)
+	}
+	if !strings.Contains(body, `\"tag\":\"markdown\"`) || !strings.Contains(body, `\"update_multi\":true`) {
+		t.Fatalf("unexpected patch body: %s", body)
+	}
+}
+
+func TestMsgUpdateRejectsInvalidCard(t *testing.T) {
+	cardPath := filepath.Join(t.TempDir(), "card.json")
+	if err := os.WriteFile(cardPath, []byte(`{"elements":[{"tag":"img"}]}`), 0o600); err != nil {
+		t.Fatalf("write card: %v", err)
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s", r.URL.Path)
+	})
+	state, _ := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"update", "om_1", "--card", cardPath})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "elements[0].img_key") {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+// replayAppCredential stands in for app_id/app_secret when a cassette is
+// replayed on a machine without a configured app.
+const replayAppCredential = "replay"
+
+// configureCassettes installs the HTTP transport used by the SDK client for
+// --record (or LARK_RECORD) and --replay. Replay takes precedence over the
+// environment so recorded sessions can be replayed from the same shell.
+func configureCassettes(cmd *cobra.Command, state *appState) error {
+	replay := strings.TrimSpace(state.Replay)
+	record := strings.TrimSpace(state.Record)
+	if replay != "" && record != "" {
+		return usageError(cmd, "record and replay cannot both be set", "Use --record to capture a session and --replay to play it back.")
+	}
+	if replay != "" {
+		transport, err := larksdk.NewReplayTransport(replay)
+		if err != nil {
+			return err
+		}
+		state.Replay = replay
+		state.httpClient = &http.Client{Transport: transport}
+		if strings.TrimSpace(state.Config.AppID) == "" {
+			state.Config.AppID = replayAppCredential
+		}
+		if strings.TrimSpace(state.Config.AppSecret) == "" {
+			state.Config.AppSecret = replayAppCredential
+		}
+		debugf(state, "replaying HTTP cassettes from %s\n", replay)
+		return nil
+	}
+	if record == "" {
+		record = strings.TrimSpace(os.Getenv("LARK_RECORD"))
+	}
+	if record == "" {
+		return nil
+	}
+	transport, err := larksdk.NewRecordingTransport(nil, record)
+	if err != nil {
+		return err
+	}
+	state.Record = record
+	state.httpClient = &http.Client{Transport: transport}
+	debugf(state, "recording HTTP cassettes to %s\n", record)
+	return nil
+}
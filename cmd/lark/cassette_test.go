@@ -0,0 +1,92 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeCassetteTestConfig(t *testing.T, baseURL string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	data, err := json.Marshal(map[string]any{
+		"app_id":                         "cli_app",
+		"app_secret":                     "app-secret-value",
+		"base_url":                       baseURL,
+		"tenant_access_token":            "t-cached-value",
+		"tenant_access_token_expires_at": time.Now().Add(2 * time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func runRootForTest(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := newRootCmd()
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return stdout.String(), err
+}
+
+func TestRecordThenReplayChatsList(t *testing.T) {
+	t.Setenv("LARK_NO_UPDATE_CHECK", "1")
+	t.Setenv("LARK_RECORD", "")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/open-apis/im/v1/chats" {
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+			"items":    []map[string]any{{"chat_id": "oc_1", "name": "Ops"}},
+			"has_more": false,
+		}})
+	}))
+	cassettes := filepath.Join(t.TempDir(), "cassettes")
+	configPath := writeCassetteTestConfig(t, server.URL)
+
+	recorded, err := runRootForTest(t, "--config", configPath, "--record", cassettes, "--json", "chats", "list", "--limit", "5")
+	server.Close()
+	if err != nil {
+		t.Fatalf("record run: %v", err)
+	}
+	names, _ := filepath.Glob(filepath.Join(cassettes, "*.json"))
+	if len(names) != 1 {
+		t.Fatalf("expected one cassette, got %v", names)
+	}
+	data, _ := os.ReadFile(names[0])
+	if strings.Contains(string(data), "t-cached-value") {
+		t.Fatalf("cassette leaks the access token:\n%s", data)
+	}
+
+	replayed, err := runRootForTest(t, "--config", configPath, "--replay", cassettes, "--json", "chats", "list", "--limit", "5")
+	if err != nil {
+		t.Fatalf("replay run: %v", err)
+	}
+	if replayed != recorded || !strings.Contains(replayed, "oc_1") {
+		t.Fatalf("replayed output differs:\nrecorded: %s\nreplayed: %s", recorded, replayed)
+	}
+}
+
+func TestRecordAndReplayAreExclusive(t *testing.T) {
+	t.Setenv("LARK_NO_UPDATE_CHECK", "1")
+	configPath := writeCassetteTestConfig(t, "http://lark.test")
+	dir := t.TempDir()
+	_, err := runRootForTest(t, "--config", configPath, "--record", dir, "--replay", dir, "chats", "list")
+	if err == nil || !strings.Contains(err.Error(), "record and replay") {
+		t.Fatalf("expected exclusivity error, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -26,19 +27,23 @@ func newChatsCmd(state *appState) *cobra.Command {
 	cmd.AddCommand(newChatsGetCmd(state))
 	cmd.AddCommand(newChatsUpdateCmd(state))
 	cmd.AddCommand(newChatsAnnouncementCmd(state))
+	cmd.AddCommand(newChatsMembersCmd(state))
+	cmd.AddCommand(newChatsManagersCmd(state))
+	cmd.AddCommand(newChatsLeaveCmd(state))
+	cmd.AddCommand(newChatsDisbandCmd(state))
 	return cmd
 }
 
 func newChatsListCmd(state *appState) *cobra.Command {
-	var limit int
+	var paging listPaging
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List recent chats",
 		Args:  cobra.NoArgs,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				return flagUsage(cmd, "limit must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			if _, err := requireSDK(state); err != nil {
 				return err
@@ -50,35 +55,21 @@ func newChatsListCmd(state *appState) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			chats := make([]larksdk.Chat, 0, limit)
-			pageToken := ""
-			remaining := limit
-			listChats := state.SDK.ListChats
-			for {
-				pageSize := remaining
-				if pageSize > maxChatsPageSize {
-					pageSize = maxChatsPageSize
-				}
-				result, err := listChats(cmd.Context(), token, larksdk.ListChatsRequest{
-					PageSize:  pageSize,
+			collected := newListCollector[larksdk.Chat](state, paging.itemLimit())
+			pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.Chat], error) {
+				result, err := state.SDK.ListChats(ctx, token, larksdk.ListChatsRequest{
+					PageSize:  collected.pageSize(maxChatsPageSize),
 					PageToken: pageToken,
 				})
-				if err != nil {
-					return err
-				}
-				chats = append(chats, result.Items...)
-				if len(chats) >= limit || !result.HasMore {
-					break
-				}
-				remaining = limit - len(chats)
-				pageToken = result.PageToken
-				if pageToken == "" {
-					break
-				}
+				return larksdk.Page[larksdk.Chat]{Items: result.Items, PageToken: result.PageToken, HasMore: result.HasMore}, err
+			})
+			if err := drainPages(cmd.Context(), state, collected, pager, 0); err != nil {
+				return err
 			}
-			if len(chats) > limit {
-				chats = chats[:limit]
+			if collected.streaming {
+				return nil
 			}
+			chats := collected.items
 			payload := map[string]any{"chats": chats}
 			lines := make([]string, 0, len(chats))
 			for _, chat := range chats {
@@ -89,6 +80,7 @@ func newChatsListCmd(state *appState) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().IntVar(&limit, "limit", 20, "max number of chats to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 20, "max number of chats to return")
+	addPagingFlags(cmd, &paging)
 	return cmd
 }
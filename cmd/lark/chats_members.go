@@ -0,0 +1,394 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+type chatMemberAction string
+
+const (
+	chatMembersAdd     chatMemberAction = "members add"
+	chatMembersRemove  chatMemberAction = "members remove"
+	chatManagersAdd    chatMemberAction = "managers add"
+	chatManagersRemove chatMemberAction = "managers remove"
+)
+
+// chatMemberTargets are the members named by --user-id, --bot-id and --email.
+type chatMemberTargets struct {
+	UserIDs    []string
+	UserIDType string
+	BotIDs     []string
+	Emails     []string
+}
+
+func addChatMemberTargetFlags(cmd *cobra.Command, targets *chatMemberTargets) {
+	cmd.Flags().StringSliceVar(&targets.UserIDs, "user-id", nil, "user IDs (repeatable or comma-separated)")
+	cmd.Flags().StringVar(&targets.UserIDType, "user-id-type", "open_id", "user ID type for --user-id (open_id, union_id, user_id)")
+	cmd.Flags().StringSliceVar(&targets.BotIDs, "bot-id", nil, "bot app IDs (cli_...; repeatable or comma-separated)")
+	cmd.Flags().StringSliceVar(&targets.Emails, "email", nil, "user emails, resolved to open_id (repeatable or comma-separated)")
+}
+
+func (t chatMemberTargets) empty() bool {
+	return len(t.UserIDs) == 0 && len(t.BotIDs) == 0 && len(t.Emails) == 0
+}
+
+// chatMemberChange is the outcome for one member in one chat.
+type chatMemberChange struct {
+	ChatID       string `json:"chat_id"`
+	Member       string `json:"member"`
+	MemberID     string `json:"member_id,omitempty"`
+	MemberIDType string `json:"member_id_type"`
+	Status       string `json:"status"`
+	Error        string `json:"error,omitempty"`
+}
+
+// chatMembershipResult is the API result for one chat.
+type chatMembershipResult struct {
+	ChatID string `json:"chat_id"`
+	larksdk.ChatMembersResult
+	larksdk.ChatManagersResult
+	Error string `json:"error,omitempty"`
+}
+
+// chatMemberGroup is a set of member IDs sharing one member_id_type; each
+// group is one API call per chat.
+type chatMemberGroup struct {
+	idType  string
+	ids     []string
+	display map[string]string
+}
+
+func newChatsMembersCmd(state *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "members",
+		Short: "Add or remove chat members",
+	}
+	cmd.AddCommand(newChatMemberChangeCmd(state, chatMembersAdd, "add <chat-id>...", "Add users or bots to chats"))
+	cmd.AddCommand(newChatMemberChangeCmd(state, chatMembersRemove, "remove <chat-id>...", "Remove users or bots from chats"))
+	return cmd
+}
+
+func newChatsManagersCmd(state *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "managers",
+		Short: "Add or remove chat managers",
+	}
+	cmd.AddCommand(newChatMemberChangeCmd(state, chatManagersAdd, "add <chat-id>...", "Promote chat members to managers"))
+	cmd.AddCommand(newChatMemberChangeCmd(state, chatManagersRemove, "remove <chat-id>...", "Demote chat managers"))
+	return cmd
+}
+
+func newChatMemberChangeCmd(state *appState, action chatMemberAction, use, short string) *cobra.Command {
+	var chatIDs []string
+	var targets chatMemberTargets
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Example: fmt.Sprintf(`  lark chats %s oc_a oc_b --email new.hire@example.com
+  lark chats %s oc_a --user-id ou_1,ou_2 --bot-id cli_123`, action, action),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.MinimumNArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			chatIDs = chatIDs[:0]
+			for _, arg := range args {
+				if chatID := strings.TrimSpace(arg); chatID != "" {
+					chatIDs = append(chatIDs, chatID)
+				}
+			}
+			if len(chatIDs) == 0 {
+				return argsUsageError(cmd, errors.New("chat-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if targets.empty() {
+				return flagUsage(cmd, "at least one of --user-id, --bot-id, or --email is required")
+			}
+			if _, err := requireSDK(state); err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			token, tokenType, err := resolveAccessToken(ctx, state, tokenTypesTenantOrUser, nil)
+			if err != nil {
+				return err
+			}
+			groups, unresolved, err := resolveChatMemberTargets(ctx, state, token, tokenType, targets)
+			if err != nil {
+				return err
+			}
+
+			results := make([]chatMembershipResult, 0, len(chatIDs))
+			changes := make([]chatMemberChange, 0)
+			for _, chatID := range chatIDs {
+				result := chatMembershipResult{ChatID: chatID}
+				for _, email := range unresolved {
+					changes = append(changes, chatMemberChange{ChatID: chatID, Member: email, MemberIDType: "email", Status: "email_not_found"})
+				}
+				for _, group := range groups {
+					groupChanges, err := applyChatMemberChange(ctx, state, token, larksdk.AccessTokenType(tokenType), action, chatID, group, &result)
+					if err != nil {
+						if tokenType == tokenTypeUser {
+							err = withUserScopeHintForCommand(state, err)
+						}
+						result.Error = err.Error()
+					}
+					changes = append(changes, groupChanges...)
+				}
+				results = append(results, result)
+			}
+			return printChatMemberChanges(state, results, changes)
+		},
+	}
+
+	addChatMemberTargetFlags(cmd, &targets)
+	return cmd
+}
+
+// resolveChatMemberTargets groups targets by member_id_type, resolving emails
+// to open_ids. Emails with no matching user are returned separately so they
+// can be reported without failing the other members.
+func resolveChatMemberTargets(ctx context.Context, state *appState, token string, tokenType tokenType, targets chatMemberTargets) ([]chatMemberGroup, []string, error) {
+	userIDType := strings.TrimSpace(targets.UserIDType)
+	switch userIDType {
+	case "open_id", "union_id", "user_id":
+	default:
+		return nil, nil, fmt.Errorf("user-id-type must be one of open_id, union_id, user_id")
+	}
+	byType := map[string]*chatMemberGroup{}
+	order := make([]string, 0, 3)
+	add := func(idType, id, display string) {
+		group, ok := byType[idType]
+		if !ok {
+			group = &chatMemberGroup{idType: idType, display: map[string]string{}}
+			byType[idType] = group
+			order = append(order, idType)
+		}
+		if _, seen := group.display[id]; seen {
+			return
+		}
+		group.ids = append(group.ids, id)
+		group.display[id] = display
+	}
+	for _, id := range targets.UserIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			add(userIDType, id, id)
+		}
+	}
+	var unresolved []string
+	if len(targets.Emails) > 0 {
+		lookupToken := token
+		if tokenType == tokenTypeUser {
+			// batch_get_id only accepts a tenant token.
+			tenantToken, err := ensureTenantToken(ctx, state)
+			if err != nil {
+				return nil, nil, err
+			}
+			lookupToken = tenantToken
+		}
+		openIDs, missing, err := resolveEmailOpenIDs(ctx, state, lookupToken, targets.Emails)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, email := range targets.Emails {
+			email = strings.TrimSpace(email)
+			if openID, ok := openIDs[email]; ok {
+				add("open_id", openID, email)
+			}
+		}
+		unresolved = missing
+	}
+	for _, id := range targets.BotIDs {
+		if id = strings.TrimSpace(id); id != "" {
+			add("app_id", id, id)
+		}
+	}
+	groups := make([]chatMemberGroup, 0, len(order))
+	for _, idType := range order {
+		groups = append(groups, *byType[idType])
+	}
+	return groups, unresolved, nil
+}
+
+// applyChatMemberChange runs one API call for a group of members and maps the
+// response lists to per-member statuses.
+func applyChatMemberChange(ctx context.Context, state *appState, token string, tokenType larksdk.AccessTokenType, action chatMemberAction, chatID string, group chatMemberGroup, result *chatMembershipResult) ([]chatMemberChange, error) {
+	req := larksdk.ChatMembersRequest{ChatID: chatID, MemberIDType: group.idType, IDs: group.ids, SucceedType: 1}
+	status := map[string]string{}
+	var err error
+	okStatus := "added"
+	switch action {
+	case chatMembersAdd:
+		var res larksdk.ChatMembersResult
+		res, err = state.SDK.AddChatMembers(ctx, token, tokenType, req)
+		mergeChatMembersResult(&result.ChatMembersResult, res)
+		for _, id := range res.InvalidIDList {
+			status[id] = "invalid"
+		}
+		for _, id := range res.NotExistedIDList {
+			status[id] = "not_existed"
+		}
+		for _, id := range res.PendingApprovalIDList {
+			status[id] = "pending_approval"
+		}
+	case chatMembersRemove:
+		okStatus = "removed"
+		var res larksdk.ChatMembersResult
+		res, err = state.SDK.RemoveChatMembers(ctx, token, tokenType, req)
+		mergeChatMembersResult(&result.ChatMembersResult, res)
+		for _, id := range res.InvalidIDList {
+			status[id] = "invalid"
+		}
+	case chatManagersAdd, chatManagersRemove:
+		var res larksdk.ChatManagersResult
+		if action == chatManagersAdd {
+			res, err = state.SDK.AddChatManagers(ctx, token, tokenType, req)
+		} else {
+			okStatus = "removed"
+			res, err = state.SDK.RemoveChatManagers(ctx, token, tokenType, req)
+		}
+		if err == nil {
+			result.ChatManagersResult = res
+		}
+	}
+	changes := make([]chatMemberChange, 0, len(group.ids))
+	for _, id := range group.ids {
+		change := chatMemberChange{ChatID: chatID, Member: group.display[id], MemberID: id, MemberIDType: group.idType, Status: okStatus}
+		if err != nil {
+			change.Status = "error"
+			change.Error = err.Error()
+		} else if s, ok := status[id]; ok {
+			change.Status = s
+		}
+		changes = append(changes, change)
+	}
+	return changes, err
+}
+
+func mergeChatMembersResult(dst *larksdk.ChatMembersResult, src larksdk.ChatMembersResult) {
+	dst.InvalidIDList = append(dst.InvalidIDList, src.InvalidIDList...)
+	dst.NotExistedIDList = append(dst.NotExistedIDList, src.NotExistedIDList...)
+	dst.PendingApprovalIDList = append(dst.PendingApprovalIDList, src.PendingApprovalIDList...)
+}
+
+// printChatMemberChanges prints one row per member and chat, then fails if any
+// member could not be changed so scripts can detect partial failures.
+func printChatMemberChanges(state *appState, results []chatMembershipResult, changes []chatMemberChange) error {
+	failed := 0
+	lines := make([]string, 0, len(changes))
+	for _, change := range changes {
+		switch change.Status {
+		case "added", "removed", "pending_approval":
+		default:
+			failed++
+		}
+		detail := change.Status
+		if change.Error != "" {
+			detail = fmt.Sprintf("%s: %s", change.Status, change.Error)
+		}
+		lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s", change.ChatID, change.Member, change.MemberID, detail))
+	}
+	payload := map[string]any{"results": results, "members": changes}
+	if err := state.Printer.Print(payload, tableText([]string{"chat_id", "member", "member_id", "status"}, lines, "no members changed")); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d member changes failed", failed, len(changes))
+	}
+	return nil
+}
+
+func newChatsLeaveCmd(state *appState) *cobra.Command {
+	var chatID string
+
+	cmd := &cobra.Command{
+		Use:   "leave <chat-id>",
+		Short: "Leave a chat (the bot with a tenant token, yourself with a user token)",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			chatID = strings.TrimSpace(args[0])
+			if chatID == "" {
+				return argsUsageError(cmd, errors.New("chat-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := confirmDestructive(cmd, state, fmt.Sprintf("leave chat %s", chatID)); err != nil {
+				return err
+			}
+			return runWithToken(cmd, state, tokenTypesTenantOrUser, nil, func(ctx context.Context, sdk *larksdk.Client, token string, tokenType tokenType) (any, string, error) {
+				req := larksdk.ChatMembersRequest{ChatID: chatID}
+				if tokenType == tokenTypeUser {
+					info, err := sdk.UserInfo(ctx, token)
+					if err != nil {
+						return nil, "", err
+					}
+					if info.OpenID == "" {
+						return nil, "", errors.New("current user open_id not available")
+					}
+					req.MemberIDType = "open_id"
+					req.IDs = []string{info.OpenID}
+				} else {
+					appID := strings.TrimSpace(state.Config.AppID)
+					if appID == "" {
+						return nil, "", errors.New("app_id is required to remove the bot")
+					}
+					req.MemberIDType = "app_id"
+					req.IDs = []string{appID}
+				}
+				result, err := sdk.RemoveChatMembers(ctx, token, larksdk.AccessTokenType(tokenType), req)
+				if err != nil {
+					return nil, "", err
+				}
+				if len(result.InvalidIDList) > 0 {
+					return nil, "", fmt.Errorf("could not leave chat %s: %s is not a removable member", chatID, req.IDs[0])
+				}
+				payload := map[string]any{"chat_id": chatID, "member_id": req.IDs[0], "left": true}
+				return payload, fmt.Sprintf("left %s", chatID), nil
+			})
+		},
+	}
+	return cmd
+}
+
+func newChatsDisbandCmd(state *appState) *cobra.Command {
+	var chatID string
+
+	cmd := &cobra.Command{
+		Use:   "disband <chat-id>",
+		Short: "Disband (delete) a chat",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			chatID = strings.TrimSpace(args[0])
+			if chatID == "" {
+				return argsUsageError(cmd, errors.New("chat-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := confirmDestructive(cmd, state, fmt.Sprintf("disband chat %s for all members", chatID)); err != nil {
+				return err
+			}
+			return runWithToken(cmd, state, tokenTypesTenantOrUser, nil, func(ctx context.Context, sdk *larksdk.Client, token string, tokenType tokenType) (any, string, error) {
+				if err := sdk.DisbandChat(ctx, token, larksdk.AccessTokenType(tokenType), chatID); err != nil {
+					return nil, "", err
+				}
+				payload := map[string]any{"chat_id": chatID, "disbanded": true}
+				return payload, fmt.Sprintf("disbanded %s", chatID), nil
+			})
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestChatsMembersAddReportsPartialFailures(t *testing.T) {
+	var calls []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/open-apis/contact/v3/users/batch_get_id":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"user_list": []map[string]any{{"user_id": "ou_new", "email": "new@example.com"}, {"email": "gone@example.com"}},
+			}})
+		case strings.HasSuffix(r.URL.Path, "/members") && r.Method == http.MethodPost:
+			var body struct {
+				IDList []string `json:"id_list"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if r.URL.Query().Get("succeed_type") != "1" {
+				t.Fatalf("unexpected succeed_type: %q", r.URL.Query().Get("succeed_type"))
+			}
+			calls = append(calls, r.URL.Path+" "+r.URL.Query().Get("member_id_type")+" "+strings.Join(body.IDList, ","))
+			data := map[string]any{}
+			if r.URL.Path == "/open-apis/im/v1/chats/oc_b/members" && r.URL.Query().Get("member_id_type") == "app_id" {
+				data["not_existed_id_list"] = []string{"cli_bot"}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": data})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newChatsCmd(state)
+	cmd.SetArgs([]string{"members", "add", "oc_a", "oc_b", "--email", "new@example.com,gone@example.com", "--bot-id", "cli_bot"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "3 of 6 member changes failed") {
+		t.Fatalf("expected partial failure error, got %v", err)
+	}
+	want := []string{
+		"/open-apis/im/v1/chats/oc_a/members open_id ou_new",
+		"/open-apis/im/v1/chats/oc_a/members app_id cli_bot",
+		"/open-apis/im/v1/chats/oc_b/members open_id ou_new",
+		"/open-apis/im/v1/chats/oc_b/members app_id cli_bot",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected calls:\n%s", strings.Join(calls, "\n"))
+	}
+	var payload struct {
+		Results []chatMembershipResult `json:"results"`
+		Members []chatMemberChange     `json:"members"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, buf.String())
+	}
+	if len(payload.Results) != 2 || strings.Join(payload.Results[1].NotExistedIDList, ",") != "cli_bot" {
+		t.Fatalf("unexpected results: %+v", payload.Results)
+	}
+	statuses := make([]string, 0, len(payload.Members))
+	for _, member := range payload.Members {
+		statuses = append(statuses, member.ChatID+":"+member.Member+":"+member.Status)
+	}
+	wantStatuses := "oc_a:gone@example.com:email_not_found,oc_a:new@example.com:added,oc_a:cli_bot:added," +
+		"oc_b:gone@example.com:email_not_found,oc_b:new@example.com:added,oc_b:cli_bot:not_existed"
+	if strings.Join(statuses, ",") != wantStatuses {
+		t.Fatalf("unexpected statuses: %s", strings.Join(statuses, ","))
+	}
+}
+
+func TestChatsManagersRemove(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/open-apis/im/v1/chats/oc_a/managers/delete_managers" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"chat_managers": []string{"ou_keep"}}})
+	})
+	state, buf := newTestState(t, handler, false)
+
+	cmd := newChatsCmd(state)
+	cmd.SetArgs([]string{"managers", "remove", "oc_a", "--user-id", "ou_1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("managers remove error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "oc_a") || !strings.Contains(buf.String(), "removed") {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestChatsLeaveAndDisband(t *testing.T) {
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.URL.Query().Get("member_id_type"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{}})
+	})
+	state, _ := newTestState(t, handler, true)
+	state.Force = true
+
+	for _, args := range [][]string{{"leave", "oc_a"}, {"disband", "oc_b"}} {
+		cmd := newChatsCmd(state)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%s error: %v", args[0], err)
+		}
+	}
+	want := "DELETE /open-apis/im/v1/chats/oc_a/members app_id,DELETE /open-apis/im/v1/chats/oc_b "
+	if strings.Join(requests, ",") != want {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"lark/internal/config"
+	"lark/internal/larksdk"
 )
 
 func newConfigCmd(state *appState) *cobra.Command {
@@ -48,6 +49,7 @@ func newConfigSetCmd(state *appState) *cobra.Command {
 	var appSecret string
 	var storeSecretInKeyring bool
 	var storeSecretInConfig bool
+	var maxRetries int
 
 	cmd := &cobra.Command{
 		Use:   "set",
@@ -66,6 +68,7 @@ func newConfigSetCmd(state *appState) *cobra.Command {
 			useAppSecret := cmd.Flags().Changed("app-secret")
 			useStoreSecretInKeyring := cmd.Flags().Changed("store-secret-in-keyring")
 			useStoreSecretInConfig := cmd.Flags().Changed("store-secret-in-config")
+			useMaxRetries := cmd.Flags().Changed("max-retries")
 
 			usedBaseURLGroup := useBaseURL || usePlatform
 			usedMailboxGroup := useDefaultMailboxID
@@ -89,11 +92,14 @@ func newConfigSetCmd(state *appState) *cobra.Command {
 			if usedAppCredsGroup {
 				groupsUsed++
 			}
+			if useMaxRetries {
+				groupsUsed++
+			}
 			if groupsUsed == 0 {
-				return errors.New("one of --base-url, --platform, --default-mailbox-id, --default-token-type, --default-user-account, --app-id, or --app-secret is required")
+				return errors.New("one of --base-url, --platform, --default-mailbox-id, --default-token-type, --default-user-account, --max-retries, --app-id, or --app-secret is required")
 			}
 			if groupsUsed > 1 {
-				return errors.New("flags are mutually exclusive; choose one of: (--base-url|--platform), --default-mailbox-id, --default-token-type, --default-user-account, or (--app-id/--app-secret)")
+				return errors.New("flags are mutually exclusive; choose one of: (--base-url|--platform), --default-mailbox-id, --default-token-type, --default-user-account, --max-retries, or (--app-id/--app-secret)")
 			}
 
 			if usedAppCredsGroup {
@@ -181,6 +187,20 @@ func newConfigSetCmd(state *appState) *cobra.Command {
 				}
 				return state.Printer.Print(payload, fmt.Sprintf("saved default_user_account to %s", state.ConfigPath))
 			}
+			if useMaxRetries {
+				if maxRetries < 0 {
+					return errors.New("max-retries must be >= 0")
+				}
+				state.Config.MaxRetries = &maxRetries
+				if err := state.saveConfig(); err != nil {
+					return err
+				}
+				payload := map[string]any{
+					"config_path": state.ConfigPath,
+					"max_retries": maxRetries,
+				}
+				return state.Printer.Print(payload, fmt.Sprintf("saved max_retries to %s", state.ConfigPath))
+			}
 
 			var normalized string
 			if usePlatform {
@@ -224,12 +244,13 @@ func newConfigSetCmd(state *appState) *cobra.Command {
 	cmd.Flags().StringVar(&defaultMailboxID, "default-mailbox-id", "", "default mailbox id to persist (or 'me')")
 	cmd.Flags().StringVar(&defaultTokenType, "default-token-type", "", "default token type to persist (tenant or user)")
 	cmd.Flags().StringVar(&defaultUserAccount, "default-user-account", "", "default user account label to persist")
+	cmd.Flags().IntVar(&maxRetries, "max-retries", 0, "max retries for rate-limited or failed API requests (0 disables)")
 	cmd.Flags().StringVar(&appID, "app-id", "", "app ID to persist")
 	cmd.Flags().StringVar(&appSecret, "app-secret", "", "app secret to persist (stored in plain text unless stored in keychain)")
 	cmd.Flags().BoolVar(&storeSecretInKeyring, "store-secret-in-keyring", false, "store app secret in keychain instead of config")
 	cmd.Flags().BoolVar(&storeSecretInConfig, "store-secret-in-config", false, "store app secret in config (disables keychain storage)")
-	cmd.MarkFlagsMutuallyExclusive("base-url", "platform", "default-mailbox-id", "default-token-type", "default-user-account")
-	cmd.MarkFlagsOneRequired("base-url", "platform", "default-mailbox-id", "default-token-type", "default-user-account", "app-id", "app-secret")
+	cmd.MarkFlagsMutuallyExclusive("base-url", "platform", "default-mailbox-id", "default-token-type", "default-user-account", "max-retries")
+	cmd.MarkFlagsOneRequired("base-url", "platform", "default-mailbox-id", "default-token-type", "default-user-account", "max-retries", "app-id", "app-secret")
 
 	return cmd
 }
@@ -240,6 +261,7 @@ func newConfigUnsetCmd(state *appState) *cobra.Command {
 	var unsetDefaultTokenType bool
 	var unsetDefaultUserAccount bool
 	var unsetUserTokens bool
+	var unsetMaxRetries bool
 
 	cmd := &cobra.Command{
 		Use:   "unset",
@@ -254,8 +276,9 @@ func newConfigUnsetCmd(state *appState) *cobra.Command {
 			useDefaultTokenType := cmd.Flags().Changed("default-token-type")
 			useDefaultUserAccount := cmd.Flags().Changed("default-user-account")
 			useUserTokens := cmd.Flags().Changed("user-tokens")
-			if !useBaseURL && !useDefaultMailboxID && !useDefaultTokenType && !useDefaultUserAccount && !useUserTokens {
-				return errors.New("one of --base-url, --default-mailbox-id, --default-token-type, --default-user-account, or --user-tokens is required")
+			useMaxRetries := cmd.Flags().Changed("max-retries")
+			if !useBaseURL && !useDefaultMailboxID && !useDefaultTokenType && !useDefaultUserAccount && !useUserTokens && !useMaxRetries {
+				return errors.New("one of --base-url, --default-mailbox-id, --default-token-type, --default-user-account, --max-retries, or --user-tokens is required")
 			}
 
 			if useBaseURL {
@@ -317,6 +340,20 @@ func newConfigUnsetCmd(state *appState) *cobra.Command {
 				}
 				return state.Printer.Print(payload, fmt.Sprintf("cleared default_user_account in %s", state.ConfigPath))
 			}
+			if useMaxRetries {
+				if !unsetMaxRetries {
+					return errors.New("--max-retries must be true")
+				}
+				state.Config.MaxRetries = nil
+				if err := state.saveConfig(); err != nil {
+					return err
+				}
+				payload := map[string]any{
+					"config_path": state.ConfigPath,
+					"max_retries": nil,
+				}
+				return state.Printer.Print(payload, fmt.Sprintf("cleared max_retries in %s", state.ConfigPath))
+			}
 
 			if !unsetUserTokens {
 				return errors.New("--user-tokens must be true")
@@ -359,8 +396,9 @@ func newConfigUnsetCmd(state *appState) *cobra.Command {
 	cmd.Flags().BoolVar(&unsetDefaultTokenType, "default-token-type", false, "clear the persisted default token type")
 	cmd.Flags().BoolVar(&unsetDefaultUserAccount, "default-user-account", false, "clear the persisted default user account")
 	cmd.Flags().BoolVar(&unsetUserTokens, "user-tokens", false, "clear persisted user access tokens")
-	cmd.MarkFlagsMutuallyExclusive("base-url", "default-mailbox-id", "default-token-type", "default-user-account", "user-tokens")
-	cmd.MarkFlagsOneRequired("base-url", "default-mailbox-id", "default-token-type", "default-user-account", "user-tokens")
+	cmd.Flags().BoolVar(&unsetMaxRetries, "max-retries", false, "clear the persisted retry limit (use the default)")
+	cmd.MarkFlagsMutuallyExclusive("base-url", "default-mailbox-id", "default-token-type", "default-user-account", "user-tokens", "max-retries")
+	cmd.MarkFlagsOneRequired("base-url", "default-mailbox-id", "default-token-type", "default-user-account", "user-tokens", "max-retries")
 
 	return cmd
 }
@@ -388,6 +426,10 @@ func configKeys() []configKeyInfo {
 			Key:         "default-user-account",
 			Description: "Default user OAuth account label to persist (config set) or clear (config unset)",
 		},
+		{
+			Key:         "max-retries",
+			Description: "Retry limit for rate-limited or failed API requests to persist (config set) or clear (config unset)",
+		},
 		{
 			Key:         "user-tokens",
 			Description: "Clear persisted user access/refresh tokens (config unset)",
@@ -436,6 +478,13 @@ func formatConfigKeysHuman(keys []configKeyInfo) string {
 	return strings.Join(lines, "\n")
 }
 
+func formatMaxRetries(value *int) string {
+	if value == nil {
+		return fmt.Sprintf("default (%d)", larksdk.DefaultMaxRetries)
+	}
+	return fmt.Sprintf("%d", *value)
+}
+
 func formatConfigHuman(cfg *config.Config) string {
 	lines := []string{
 		fmt.Sprintf("app_id: %s", cfg.AppID),
@@ -445,6 +494,7 @@ func formatConfigHuman(cfg *config.Config) string {
 		fmt.Sprintf("default_token_type: %s", cfg.DefaultTokenType),
 		fmt.Sprintf("default_user_account: %s", cfg.DefaultUserAccount),
 		fmt.Sprintf("keyring_backend: %s", cfg.KeyringBackend),
+		fmt.Sprintf("max_retries: %s", formatMaxRetries(cfg.MaxRetries)),
 		fmt.Sprintf("user_accounts: %s", strings.Join(listUserAccountNames(cfg), " ")),
 		fmt.Sprintf("user_scopes: %s", strings.Join(cfg.UserScopes, " ")),
 		fmt.Sprintf("tenant_access_token_expires_at: %d", cfg.TenantAccessTokenExpiresAt),
@@ -269,3 +269,52 @@ func TestConfigSetAppCredentialsMutuallyExclusiveWithBaseURL(t *testing.T) {
 		t.Fatalf("expected error")
 	}
 }
+
+func TestConfigSetAndUnsetMaxRetries(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	state := &appState{
+		ConfigPath: configPath,
+		Config:     config.Default(),
+		Printer:    output.Printer{Writer: io.Discard},
+	}
+
+	cmd := newConfigCmd(state)
+	cmd.SetArgs([]string{"set", "--max-retries", "0"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("config set error: %v", err)
+	}
+	saved, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if saved.MaxRetries == nil || *saved.MaxRetries != 0 {
+		t.Fatalf("expected max_retries 0, got %v", saved.MaxRetries)
+	}
+
+	cmd = newConfigCmd(state)
+	cmd.SetArgs([]string{"unset", "--max-retries"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("config unset error: %v", err)
+	}
+	saved, err = config.Load(configPath)
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if saved.MaxRetries != nil {
+		t.Fatalf("expected max_retries cleared, got %d", *saved.MaxRetries)
+	}
+}
+
+func TestConfigSetMaxRetriesRejectsNegative(t *testing.T) {
+	state := &appState{
+		ConfigPath: filepath.Join(t.TempDir(), "config.json"),
+		Config:     config.Default(),
+		Printer:    output.Printer{Writer: io.Discard},
+	}
+
+	cmd := newConfigCmd(state)
+	cmd.SetArgs([]string{"set", "--max-retries", "-1"})
+	if err := cmd.Execute(); err == nil || err.Error() != "max-retries must be >= 0" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -43,15 +44,36 @@ func newDocsCmd(state *appState) *cobra.Command {
 	cmd.AddCommand(newDocsBlocksCmd(state))
 	cmd.AddCommand(newDocsConvertCmd(state))
 	cmd.AddCommand(newDocsOverwriteCmd(state))
+	cmd.AddCommand(newDocsApplyCmd(state))
+	cmd.AddCommand(newDocsCommentsCmd(state))
 	return cmd
 }
 
 func newDocsCreateCmd(state *appState) *cobra.Command {
 	var folderID string
+	var wikiNode string
+	var fromTemplate string
+	var vars []string
 
 	cmd := &cobra.Command{
 		Use:   "create <title>",
 		Short: "Create a Docs (docx) document",
+		Long: `Create a Docs (docx) document in a Drive folder or under a Wiki node.
+
+--from-template starts from a template:
+- a docx document ID or URL is copied with the Drive copy API (into
+  --folder-id, or next to the template), then {{key}} placeholders in its
+  text are replaced with --var values. With --wiki-node, the copy is moved
+  under that node; a wiki page URL is copied there with the Wiki copy API.
+- a local .md file is rendered as a Go template ({{.key}} or {{key}}) and
+  written into the new document; local images are uploaded.
+
+title and date (YYYY-MM-DD) are always available as variables. Placeholders
+in a copied document must sit within one text run; ones without a value are
+left as is and listed in the output.`,
+		Example: `  lark docs create "Design: search" --folder-id <FOLDER_TOKEN>
+  lark docs create "Incident 42 review" --from-template <DOCUMENT_ID> --var owner=Ada --var severity=P1
+  lark docs create "Design: search" --from-template ./design.md --wiki-node <NODE_TOKEN> --var team=search`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
 				return argsUsageError(cmd, err)
@@ -62,34 +84,89 @@ func newDocsCreateCmd(state *appState) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			title := args[0]
+			folderID = strings.TrimSpace(folderID)
+			wikiNode = strings.TrimSpace(wikiNode)
+			fromTemplate = strings.TrimSpace(fromTemplate)
+			if folderID != "" && wikiNode != "" {
+				return usageError(cmd, "--folder-id and --wiki-node are mutually exclusive", "")
+			}
+			if len(vars) > 0 && fromTemplate == "" {
+				return usageError(cmd, "--var requires --from-template", "")
+			}
+			templateVars, err := parseDocsTemplateVars(vars, title)
+			if err != nil {
+				return usageError(cmd, err.Error(), "")
+			}
+			local := fromTemplate != "" && isLocalDocsTemplate(fromTemplate)
+			markdown := ""
+			if local {
+				if markdown, err = renderDocsTemplate(fromTemplate, templateVars); err != nil {
+					return err
+				}
+			}
 			if _, err := requireSDK(state); err != nil {
 				return err
 			}
-			title := args[0]
-			token, tokenType, err := resolveAccessToken(cmd.Context(), state, tokenTypesTenantOrUser, nil)
+			ctx := cmd.Context()
+			token, tokenType, err := resolveAccessToken(ctx, state, tokenTypesTenantOrUser, nil)
 			if err != nil {
 				return err
 			}
-			doc, err := state.SDK.CreateDocxDocument(cmd.Context(), token, larksdk.AccessTokenType(tokenType), larksdk.CreateDocxDocumentRequest{
-				Title:       title,
-				FolderToken: folderID,
-			})
+
+			var doc larksdk.DocxDocument
+			nodeToken := ""
+			switch {
+			case fromTemplate != "" && !local:
+				doc, nodeToken, err = copyDocsTemplate(ctx, state, token, tokenType, fromTemplate, title, folderID, wikiNode)
+			case wikiNode != "":
+				doc, nodeToken, err = createDocsWikiNode(ctx, state, token, tokenType, title, wikiNode)
+			default:
+				doc, err = state.SDK.CreateDocxDocument(ctx, token, larksdk.AccessTokenType(tokenType), larksdk.CreateDocxDocumentRequest{
+					Title:       title,
+					FolderToken: folderID,
+				})
+			}
 			if err != nil {
 				return err
 			}
+
+			payload := map[string]any{"document": doc}
+			var result *docsTemplateResult
+			if fromTemplate != "" {
+				result = &docsTemplateResult{Template: fromTemplate, Source: "document", NodeToken: nodeToken}
+				if local {
+					result.Source = "markdown"
+					err = replaceDocxMarkdown(ctx, state, token, tokenType, doc.DocumentID, markdown, fromTemplate)
+				} else {
+					result.Replaced, result.Unresolved, err = substituteDocxPlaceholders(ctx, state.SDK, token, larksdk.AccessTokenType(tokenType), doc.DocumentID, templateVars)
+				}
+				if err != nil {
+					return fmt.Errorf("document %s created from template, but filling it failed: %w", doc.DocumentID, err)
+				}
+				payload["template"] = result
+			} else if nodeToken != "" {
+				payload["node_token"] = nodeToken
+			}
 			if doc.URL == "" && doc.DocumentID != "" {
-				doc.URL = docxDriveURL(cmd.Context(), state, tokenType, token, doc.DocumentID)
+				doc.URL = docxDriveURL(ctx, state, tokenType, token, doc.DocumentID)
+				payload["document"] = doc
 			}
-			payload := map[string]any{"document": doc}
 			text := tableTextRow(
 				[]string{"document_id", "title", "url"},
 				[]string{doc.DocumentID, doc.Title, doc.URL},
 			)
+			if result != nil && len(result.Unresolved) > 0 {
+				fmt.Fprintf(errWriter(state), "warning: placeholders without a value: %s\n", strings.Join(result.Unresolved, ", "))
+			}
 			return state.Printer.Print(payload, text)
 		},
 	}
 
 	cmd.Flags().StringVar(&folderID, "folder-id", "", "Drive folder token (default: root)")
+	cmd.Flags().StringVar(&wikiNode, "wiki-node", "", "create under this Wiki node instead of a folder")
+	cmd.Flags().StringVar(&fromTemplate, "from-template", "", "template docx document (ID or URL), wiki page URL, or local .md file")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "template variable as key=value (repeatable)")
 	return cmd
 }
 
@@ -141,10 +218,21 @@ func newDocsInfoCmd(state *appState) *cobra.Command {
 func newDocsExportCmd(state *appState) *cobra.Command {
 	var format string
 	var outPath string
+	var assetsDir string
 
 	cmd := &cobra.Command{
-		Use:   "export <document-id> --format pdf --out <path>",
+		Use:   "export <document-id> --format pdf|md --out <path>",
 		Short: "Export a Docs (docx) document",
+		Long: `Export a Docs (docx) document.
+
+--format pdf (and other server-side formats) runs a Drive export task.
+--format md renders the document blocks locally: images and file attachments
+are downloaded into --assets-dir (default: "assets" next to --out) and linked
+by relative path, callouts become admonitions, mentions become @name, and
+equations are written as $...$ so the result can be re-imported with
+docs overwrite.`,
+		Example: `  lark docs export <DOCUMENT_ID> --format pdf --out ./doc.pdf
+  lark docs export <DOCUMENT_ID> --format md --out ./notes/doc.md --assets-dir ./notes/img`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
 				return argsUsageError(cmd, err)
@@ -167,6 +255,13 @@ func newDocsExportCmd(state *appState) *cobra.Command {
 				}
 			}
 			format = strings.ToLower(format)
+			if format == "markdown" {
+				format = "md"
+			}
+			assetsDir = strings.TrimSpace(assetsDir)
+			if assetsDir != "" && format != "md" {
+				return flagUsage(cmd, "--assets-dir requires --format md")
+			}
 			if _, err := requireSDK(state); err != nil {
 				return err
 			}
@@ -179,6 +274,39 @@ func newDocsExportCmd(state *appState) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if format == "md" {
+				linkBase := "."
+				if !writeStdout {
+					linkBase = filepath.Dir(outPath)
+				}
+				if assetsDir == "" {
+					assetsDir = filepath.Join(linkBase, defaultDocsAssetsDirName)
+				}
+				export, err := exportDocxMarkdown(cmd.Context(), state, token, larksdk.AccessTokenType(tokenTypeValue), documentID, assetsDir, linkBase)
+				if err != nil {
+					return err
+				}
+				if writeStdout {
+					_, err = io.WriteString(cmd.OutOrStdout(), export.Content)
+					return err
+				}
+				if err := os.WriteFile(outPath, []byte(export.Content), 0o644); err != nil {
+					return err
+				}
+				payload := map[string]any{
+					"document_id":   documentID,
+					"format":        format,
+					"output_path":   outPath,
+					"bytes_written": len(export.Content),
+					"assets_dir":    assetsDir,
+					"assets":        export.Assets,
+				}
+				text := tableTextRow(
+					[]string{"document_id", "output_path", "bytes_written", "assets"},
+					[]string{documentID, outPath, fmt.Sprintf("%d", len(export.Content)), fmt.Sprintf("%d", len(export.Assets))},
+				)
+				return state.Printer.Print(payload, text)
+			}
 			ticket, err := state.SDK.CreateExportTask(cmd.Context(), token, larksdk.AccessTokenType(tokenTypeValue), larksdk.CreateExportTaskRequest{
 				Token:         documentID,
 				Type:          "docx",
@@ -236,10 +364,11 @@ func newDocsExportCmd(state *appState) *cobra.Command {
 			return state.Printer.Print(payload, text)
 		},
 	}
-	annotateAuthServices(cmd, "drive-export")
+	annotateAuthServices(cmd, "drive-export", "docs")
 
-	cmd.Flags().StringVar(&format, "format", "", "export format (pdf)")
+	cmd.Flags().StringVar(&format, "format", "", "export format (pdf|md)")
 	cmd.Flags().StringVar(&outPath, "out", "", "output file path (or - for stdout)")
+	cmd.Flags().StringVar(&assetsDir, "assets-dir", "", "directory for downloaded images and files (md only; default: assets next to --out)")
 	_ = cmd.MarkFlagRequired("format")
 	_ = cmd.MarkFlagRequired("out")
 	return cmd
@@ -0,0 +1,563 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+const (
+	docxPatchUpdate = "update"
+	docxPatchInsert = "insert"
+	docxPatchDelete = "delete"
+
+	docxBatchUpdateMaxRequests = 200
+	docxPatchPreviewLength     = 60
+
+	// Per-request limits of the create children and create descendant APIs.
+	docxCreateChildrenMaxBlocks   = 50
+	docxCreateDescendantMaxBlocks = 1000
+)
+
+// docxPatchOp is one planned change to the document's top-level children.
+// Index refers to the position in the current document.
+type docxPatchOp struct {
+	Op      string `json:"op"`
+	Index   int    `json:"index"`
+	BlockID string `json:"block_id,omitempty"`
+	Preview string `json:"preview,omitempty"`
+}
+
+// docxPatchHunk is a run of differing top-level blocks between two matched
+// anchors. The first Paired blocks of each side are updated in place; the rest
+// are deleted from the current document or inserted from the new content.
+type docxPatchHunk struct {
+	OldStart, OldEnd int
+	NewStart, NewEnd int
+	Paired           int
+}
+
+type docxPatchPlan struct {
+	Old       []*larkdocx.Block
+	New       []*larkdocx.Block
+	NewBlocks map[string]*larkdocx.Block
+	Hunks     []docxPatchHunk
+	Unchanged int
+}
+
+func newDocsApplyCmd(state *appState) *cobra.Command {
+	var contentType string
+	var content string
+	var contentFile string
+	var uploadImages bool
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "apply <document-id>",
+		Short: "Apply Markdown/HTML to a Docx document as a minimal block diff",
+		Long: `Apply Markdown/HTML to a Docx document as a minimal block diff.
+
+Unlike overwrite, apply keeps unchanged blocks (with their IDs and comments)
+in place: the content is converted to blocks, diffed against the current
+top-level blocks, and only changed blocks are updated, inserted, or deleted.
+Text blocks whose type and style are unchanged are updated in place.
+
+Image content is not compared; use overwrite to replace images.`,
+		Example: `  lark docs apply <DOCUMENT_ID> --content-file doc.md --dry-run
+  lark docs apply <DOCUMENT_ID> --content-file doc.md`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			if strings.TrimSpace(args[0]) == "" {
+				return argsUsageError(cmd, errors.New("document-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := requireSDK(state); err != nil {
+				return err
+			}
+			refToken, _, err := parseResourceRef(args[0])
+			if err != nil {
+				return err
+			}
+			documentID := strings.TrimSpace(refToken)
+			raw, err := readDocxContent(content, contentFile)
+			if err != nil {
+				return err
+			}
+			normalized, err := normalizeDocxContentType(contentType)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			accessToken, accessTokenType, err := resolveAccessToken(ctx, state, tokenTypesTenantOrUser, nil)
+			if err != nil {
+				return err
+			}
+			tokenType := larksdk.AccessTokenType(accessTokenType)
+			convertResp, err := state.SDK.ConvertDocxContent(ctx, accessToken, tokenType, normalized, raw)
+			if err != nil {
+				return err
+			}
+			if convertResp == nil {
+				return errors.New("convert returned empty response")
+			}
+			scrubDocxTableMergeInfo(convertResp.Blocks)
+			current, err := listDocxBlocks(ctx, state.SDK, accessToken, tokenType, documentID)
+			if err != nil {
+				return err
+			}
+
+			plan := planDocxPatch(documentID, current, convertResp)
+			ops := plan.operations()
+
+			var imageSummary docxImageUploadSummary
+			if !dryRun && len(ops) > 0 {
+				if uploadImages {
+					inserted := plan.insertedImageURLs(convertResp.BlockIdToImageUrls)
+					if len(inserted) > 0 {
+						scoped := *convertResp
+						scoped.BlockIdToImageUrls = inserted
+						imageSummary, err = uploadDocxImageBlocks(ctx, state.SDK, accessToken, documentID, contentFile, &scoped)
+						if err != nil {
+							return err
+						}
+					}
+				}
+				if err := applyDocxPatch(ctx, state.SDK, accessToken, tokenType, documentID, plan); err != nil {
+					return err
+				}
+			}
+
+			summary := map[string]int{"unchanged": plan.Unchanged}
+			for _, op := range ops {
+				summary[op.Op]++
+			}
+			payload := map[string]any{
+				"document_id": documentID,
+				"dry_run":     dryRun,
+				"operations":  ops,
+				"summary":     summary,
+			}
+			if len(imageSummary.Records) > 0 {
+				payload["image_block_uploads"] = imageSummary.Records
+			}
+			lines := make([]string, 0, len(ops))
+			for _, op := range ops {
+				lines = append(lines, fmt.Sprintf("%s\t%d\t%s\t%s", op.Op, op.Index, op.BlockID, op.Preview))
+			}
+			text := tableText([]string{"op", "index", "block_id", "preview"}, lines, "document already up to date")
+			if len(ops) > 0 {
+				text = fmt.Sprintf("%s\nupdated: %d, inserted: %d, deleted: %d, unchanged: %d", text, summary[docxPatchUpdate], summary[docxPatchInsert], summary[docxPatchDelete], plan.Unchanged)
+			}
+			return state.Printer.Print(payload, text)
+		},
+	}
+
+	cmd.Flags().StringVar(&contentType, "content-type", "markdown", "content type (markdown|html)")
+	cmd.Flags().StringVar(&content, "content", "", "raw markdown/html content")
+	cmd.Flags().StringVar(&contentFile, "content-file", "", "path to file containing markdown/html content (or - for stdin)")
+	cmd.Flags().BoolVar(&uploadImages, "upload-images", true, "upload and replace inserted image blocks (best-effort)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the planned diff without changing the document")
+	return cmd
+}
+
+func planDocxPatch(documentID string, current []*larkdocx.Block, converted *larkdocx.ConvertDocumentRespData) docxPatchPlan {
+	oldIdx := newDocxBlockIndex(current)
+	plan := docxPatchPlan{NewBlocks: map[string]*larkdocx.Block{}}
+	if root := oldIdx.blocks[documentID]; root != nil {
+		for _, id := range root.Children {
+			if block := oldIdx.blocks[id]; block != nil {
+				plan.Old = append(plan.Old, block)
+			}
+		}
+	}
+	for _, block := range converted.Blocks {
+		if id := docxBlockID(block); id != "" {
+			plan.NewBlocks[id] = block
+		}
+	}
+	for _, id := range converted.FirstLevelBlockIds {
+		if block := plan.NewBlocks[id]; block != nil {
+			plan.New = append(plan.New, block)
+		}
+	}
+
+	oldSigs := make([]string, len(plan.Old))
+	for i, block := range plan.Old {
+		oldSigs[i] = docxBlockSignature(oldIdx.blocks, block)
+	}
+	newSigs := make([]string, len(plan.New))
+	for i, block := range plan.New {
+		newSigs[i] = docxBlockSignature(plan.NewBlocks, block)
+	}
+
+	// Longest common subsequence of top-level signatures; the unmatched runs
+	// between matches become hunks.
+	n, m := len(oldSigs), len(newSigs)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if oldSigs[i] == newSigs[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	i, j := 0, 0
+	hunk := docxPatchHunk{}
+	flush := func() {
+		if hunk.OldEnd > hunk.OldStart || hunk.NewEnd > hunk.NewStart {
+			for hunk.Paired < min(hunk.OldEnd-hunk.OldStart, hunk.NewEnd-hunk.NewStart) &&
+				docxBlocksUpdatable(plan.Old[hunk.OldStart+hunk.Paired], plan.New[hunk.NewStart+hunk.Paired]) {
+				hunk.Paired++
+			}
+			plan.Hunks = append(plan.Hunks, hunk)
+		}
+		hunk = docxPatchHunk{OldStart: i, OldEnd: i, NewStart: j, NewEnd: j}
+	}
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && oldSigs[i] == newSigs[j]:
+			flush()
+			plan.Unchanged++
+			i++
+			j++
+			hunk = docxPatchHunk{OldStart: i, OldEnd: i, NewStart: j, NewEnd: j}
+		case j < m && (i == n || lcs[i][j+1] >= lcs[i+1][j]):
+			j++
+			hunk.NewEnd = j
+		default:
+			i++
+			hunk.OldEnd = i
+		}
+	}
+	flush()
+	return plan
+}
+
+func (p docxPatchPlan) operations() []docxPatchOp {
+	ops := make([]docxPatchOp, 0)
+	for _, hunk := range p.Hunks {
+		for k := 0; k < hunk.Paired; k++ {
+			newBlock := p.New[hunk.NewStart+k]
+			ops = append(ops, docxPatchOp{
+				Op:      docxPatchUpdate,
+				Index:   hunk.OldStart + k,
+				BlockID: docxBlockID(p.Old[hunk.OldStart+k]),
+				Preview: docxPatchPreview(newBlock),
+			})
+		}
+		for k := hunk.OldStart + hunk.Paired; k < hunk.OldEnd; k++ {
+			ops = append(ops, docxPatchOp{
+				Op:      docxPatchDelete,
+				Index:   k,
+				BlockID: docxBlockID(p.Old[k]),
+				Preview: docxPatchPreview(p.Old[k]),
+			})
+		}
+		for k := hunk.NewStart + hunk.Paired; k < hunk.NewEnd; k++ {
+			ops = append(ops, docxPatchOp{
+				Op:      docxPatchInsert,
+				Index:   hunk.OldStart + hunk.Paired,
+				Preview: docxPatchPreview(p.New[k]),
+			})
+		}
+	}
+	return ops
+}
+
+// insertedImageURLs narrows the converter's image map to blocks that will be
+// inserted, so unchanged images are not uploaded again.
+func (p docxPatchPlan) insertedImageURLs(all []*larkdocx.BlockIdToImageUrl) []*larkdocx.BlockIdToImageUrl {
+	inserted := map[string]bool{}
+	var mark func(id string)
+	mark = func(id string) {
+		if inserted[id] {
+			return
+		}
+		inserted[id] = true
+		if block := p.NewBlocks[id]; block != nil {
+			for _, child := range block.Children {
+				mark(child)
+			}
+		}
+	}
+	for _, hunk := range p.Hunks {
+		for k := hunk.NewStart + hunk.Paired; k < hunk.NewEnd; k++ {
+			mark(docxBlockID(p.New[k]))
+		}
+	}
+	result := make([]*larkdocx.BlockIdToImageUrl, 0)
+	for _, entry := range all {
+		if entry != nil && entry.BlockId != nil && inserted[*entry.BlockId] {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// applyDocxPatch executes the plan. Text updates are batched; deletions and
+// insertions run from the bottom of the document up so earlier indexes stay
+// valid. A failure reports what was already applied, since nothing is rolled
+// back.
+func applyDocxPatch(ctx context.Context, sdk *larksdk.Client, token string, tokenType larksdk.AccessTokenType, documentID string, plan docxPatchPlan) error {
+	updates := make([]*larkdocx.UpdateBlockRequest, 0)
+	for _, hunk := range plan.Hunks {
+		for k := 0; k < hunk.Paired; k++ {
+			blockID := docxBlockID(plan.Old[hunk.OldStart+k])
+			text := docxBlockTextFields(plan.New[hunk.NewStart+k])[0]
+			updates = append(updates, &larkdocx.UpdateBlockRequest{
+				BlockId:            &blockID,
+				UpdateTextElements: &larkdocx.UpdateTextElementsRequest{Elements: text.Elements},
+			})
+		}
+	}
+	for start := 0; start < len(updates); start += docxBatchUpdateMaxRequests {
+		end := min(start+docxBatchUpdateMaxRequests, len(updates))
+		if _, err := sdk.BatchUpdateDocxBlocks(ctx, token, tokenType, documentID, updates[start:end], -1, "", ""); err != nil {
+			return fmt.Errorf("update blocks: %w (%d of %d text updates applied, no hunks applied)", err, start, len(updates))
+		}
+	}
+
+	for h := len(plan.Hunks) - 1; h >= 0; h-- {
+		hunk := plan.Hunks[h]
+		at := hunk.OldStart + hunk.Paired
+		if at < hunk.OldEnd {
+			if _, err := sdk.BatchDeleteDocxBlockChildren(ctx, token, tokenType, documentID, documentID, at, hunk.OldEnd, -1, ""); err != nil {
+				return docxHunkError(err, plan, h)
+			}
+		}
+		inserted := plan.New[hunk.NewStart+hunk.Paired : hunk.NewEnd]
+		if len(inserted) == 0 {
+			continue
+		}
+		if err := insertDocxBlocks(ctx, sdk, token, tokenType, documentID, at, inserted, plan.NewBlocks); err != nil {
+			return docxHunkError(err, plan, h)
+		}
+	}
+	return nil
+}
+
+// docxHunkError wraps the failure of hunk h. Hunks are applied from the last
+// one up, so every later hunk is already in the document.
+func docxHunkError(err error, plan docxPatchPlan, h int) error {
+	total := len(plan.Hunks)
+	applied := "no other hunks applied"
+	switch {
+	case h == total-2:
+		applied = fmt.Sprintf("hunk %d already applied", total)
+	case h < total-2:
+		applied = fmt.Sprintf("hunks %d-%d already applied", h+2, total)
+	}
+	return fmt.Errorf("apply hunk %d of %d at block %d: %w (text updates and %s; re-run to finish)", h+1, total, plan.Hunks[h].OldStart, err, applied)
+}
+
+// insertDocxBlocks inserts top-level blocks at index, splitting them into
+// requests the API accepts.
+func insertDocxBlocks(ctx context.Context, sdk *larksdk.Client, token string, tokenType larksdk.AccessTokenType, documentID string, index int, blocks []*larkdocx.Block, all map[string]*larkdocx.Block) error {
+	leaves := true
+	for _, block := range blocks {
+		if len(block.Children) > 0 {
+			leaves = false
+			break
+		}
+	}
+	if leaves {
+		for start := 0; start < len(blocks); start += docxCreateChildrenMaxBlocks {
+			end := min(start+docxCreateChildrenMaxBlocks, len(blocks))
+			children := make([]*larkdocx.Block, 0, end-start)
+			for _, block := range blocks[start:end] {
+				child := *block
+				child.BlockId = nil
+				child.ParentId = nil
+				children = append(children, &child)
+			}
+			at := index + start
+			if _, err := sdk.CreateDocxBlockChildren(ctx, token, tokenType, documentID, documentID, &larkdocx.CreateDocumentBlockChildrenReqBody{
+				Children: children,
+				Index:    &at,
+			}, -1, "", ""); err != nil {
+				return fmt.Errorf("%w (%d of %d blocks inserted)", err, start, len(blocks))
+			}
+		}
+		return nil
+	}
+
+	seen := map[string]bool{}
+	var collect func(id string, into []*larkdocx.Block) []*larkdocx.Block
+	collect = func(id string, into []*larkdocx.Block) []*larkdocx.Block {
+		block := all[id]
+		if block == nil || seen[id] {
+			return into
+		}
+		seen[id] = true
+		into = append(into, block)
+		for _, child := range block.Children {
+			into = collect(child, into)
+		}
+		return into
+	}
+	// Group whole top-level subtrees so no request exceeds the descendant
+	// limit; a single subtree larger than the limit is sent on its own.
+	ids := make([]string, 0, len(blocks))
+	descendants := make([]*larkdocx.Block, 0, len(blocks))
+	done := 0
+	flush := func() error {
+		if len(ids) == 0 {
+			return nil
+		}
+		at := index + done
+		if _, err := sdk.CreateDocxBlockDescendant(ctx, token, tokenType, documentID, documentID, &larkdocx.CreateDocumentBlockDescendantReqBody{
+			ChildrenId:  ids,
+			Index:       &at,
+			Descendants: descendants,
+		}, -1, "", ""); err != nil {
+			return fmt.Errorf("%w (%d of %d blocks inserted)", err, done, len(blocks))
+		}
+		done += len(ids)
+		ids, descendants = nil, nil
+		return nil
+	}
+	for _, block := range blocks {
+		id := docxBlockID(block)
+		subtree := collect(id, nil)
+		if len(ids) >= docxCreateChildrenMaxBlocks || len(descendants)+len(subtree) > docxCreateDescendantMaxBlocks {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		ids = append(ids, id)
+		descendants = append(descendants, subtree...)
+	}
+	return flush()
+}
+
+// docxBlocksUpdatable reports whether oldBlock can be turned into newBlock by
+// replacing its text elements: same type, same text style, and no children.
+func docxBlocksUpdatable(oldBlock, newBlock *larkdocx.Block) bool {
+	if oldBlock == nil || newBlock == nil || len(oldBlock.Children) > 0 || len(newBlock.Children) > 0 {
+		return false
+	}
+	if oldBlock.BlockType == nil || newBlock.BlockType == nil || *oldBlock.BlockType != *newBlock.BlockType {
+		return false
+	}
+	oldTexts := docxBlockTextFields(oldBlock)
+	newTexts := docxBlockTextFields(newBlock)
+	if len(oldTexts) != 1 || len(newTexts) != 1 || oldBlock.Page != nil {
+		return false
+	}
+	return docxCanonicalJSON(oldTexts[0].Style) == docxCanonicalJSON(newTexts[0].Style)
+}
+
+// docxBlockSignature identifies a block by content rather than ID: the block
+// and its subtree are serialized without IDs, comment references, image
+// tokens, and default-valued fields, so a block read from the document and
+// the same block produced by the converter compare equal.
+func docxBlockSignature(blocks map[string]*larkdocx.Block, block *larkdocx.Block) string {
+	if block == nil {
+		return ""
+	}
+	children := make([]string, 0, len(block.Children))
+	for _, id := range block.Children {
+		children = append(children, docxBlockSignature(blocks, blocks[id]))
+	}
+	return docxCanonicalJSON(block) + "[" + strings.Join(children, ",") + "]"
+}
+
+var docxSignatureIgnoredKeys = map[string]bool{
+	"block_id":     true,
+	"parent_id":    true,
+	"children":     true,
+	"comment_ids":  true,
+	"cells":        true,
+	"merge_info":   true,
+	"column_width": true,
+	"token":        true,
+	"width":        true,
+	"height":       true,
+}
+
+func docxCanonicalJSON(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return string(data)
+	}
+	data, _ = json.Marshal(pruneDocxSignatureValue(decoded))
+	return string(data)
+}
+
+func pruneDocxSignatureValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			if docxSignatureIgnoredKeys[key] {
+				continue
+			}
+			// align=1 (left) is the server default and is omitted by the converter.
+			if key == "align" && item == float64(1) {
+				continue
+			}
+			if pruned := pruneDocxSignatureValue(item); pruned != nil {
+				out[key] = pruned
+			}
+		}
+		if len(out) == 0 {
+			return nil
+		}
+		return out
+	case []any:
+		if len(v) == 0 {
+			return nil
+		}
+		out := make([]any, 0, len(v))
+		for _, item := range v {
+			out = append(out, pruneDocxSignatureValue(item))
+		}
+		return out
+	case bool:
+		if !v {
+			return nil
+		}
+		return v
+	case string:
+		if v == "" {
+			return nil
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func docxPatchPreview(block *larkdocx.Block) string {
+	text := strings.TrimSpace(docxBlockText(block))
+	text = strings.Join(strings.Fields(text), " ")
+	if text == "" && block != nil && block.BlockType != nil {
+		text = fmt.Sprintf("[block_type %d]", *block.BlockType)
+	}
+	runes := []rune(text)
+	if len(runes) > docxPatchPreviewLength {
+		text = string(runes[:docxPatchPreviewLength-3]) + "..."
+	}
+	return text
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDocsApplyDryRunShowsMinimalDiff(t *testing.T) {
+	text := func(content string) map[string]any {
+		return map[string]any{
+			"elements": []map[string]any{{"text_run": map[string]any{
+				"content":            content,
+				"text_element_style": map[string]any{"bold": false, "comment_ids": []string{"c1"}},
+			}}},
+			"style": map[string]any{"align": 1},
+		}
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/docx/v1/documents/blocks/convert":
+			plain := func(content string) map[string]any {
+				return map[string]any{"elements": []map[string]any{{"text_run": map[string]any{"content": content}}}}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"code": 0,
+				"data": map[string]any{
+					"first_level_block_ids": []string{"n1", "n2", "n3", "n4"},
+					"blocks": []map[string]any{
+						{"block_id": "n1", "block_type": 3, "heading1": plain("Title")},
+						{"block_id": "n2", "block_type": 2, "text": plain("New paragraph")},
+						{"block_id": "n3", "block_type": 2, "text": plain("Keep me")},
+						{"block_id": "n4", "block_type": 12, "bullet": plain("Added item")},
+					},
+				},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/docx/v1/documents/doc1/blocks":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"code": 0,
+				"data": map[string]any{
+					"items": []map[string]any{
+						{"block_id": "doc1", "block_type": 1, "page": text("Doc"), "children": []string{"b1", "b2", "b3", "b4"}},
+						{"block_id": "b1", "parent_id": "doc1", "block_type": 3, "heading1": text("Title")},
+						{"block_id": "b2", "parent_id": "doc1", "block_type": 2, "text": text("Old paragraph")},
+						{"block_id": "b3", "parent_id": "doc1", "block_type": 2, "text": text("Keep me")},
+						{"block_id": "b4", "parent_id": "doc1", "block_type": 22, "divider": map[string]any{}},
+					},
+					"has_more": false,
+				},
+			})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newDocsCmd(state)
+	cmd.SetArgs([]string{"apply", "doc1", "--content", "# Title", "--dry-run"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("docs apply error: %v", err)
+	}
+	var payload struct {
+		Operations []docxPatchOp  `json:"operations"`
+		Summary    map[string]int `json:"summary"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, buf.String())
+	}
+	got := make([]string, 0, len(payload.Operations))
+	for _, op := range payload.Operations {
+		got = append(got, op.Op+":"+op.BlockID+":"+op.Preview)
+	}
+	want := "update:b2:New paragraph delete:b4:[block_type 22] insert::Added item"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("unexpected operations: %v", got)
+	}
+	if payload.Summary["unchanged"] != 2 {
+		t.Fatalf("unexpected summary: %v", payload.Summary)
+	}
+}
+
+func TestDocsApplyEmitsBlockOperations(t *testing.T) {
+	text := func(content string) map[string]any {
+		return map[string]any{
+			"elements": []map[string]any{{"text_run": map[string]any{
+				"content":            content,
+				"text_element_style": map[string]any{"bold": false, "comment_ids": []string{"c1"}},
+			}}},
+			"style": map[string]any{"align": 1},
+		}
+	}
+	var updates, deleted, created []map[string]any
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		decode := func() map[string]any {
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			return body
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/docx/v1/documents/blocks/convert":
+			plain := func(content string) map[string]any {
+				return map[string]any{"elements": []map[string]any{{"text_run": map[string]any{"content": content}}}}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"code": 0,
+				"data": map[string]any{
+					"first_level_block_ids": []string{"n1", "n2", "n3", "n4"},
+					"blocks": []map[string]any{
+						{"block_id": "n1", "block_type": 3, "heading1": plain("Title")},
+						{"block_id": "n2", "block_type": 2, "text": plain("New paragraph")},
+						{"block_id": "n3", "block_type": 2, "text": plain("Keep me")},
+						{"block_id": "n4", "block_type": 12, "bullet": plain("Added item")},
+					},
+				},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/docx/v1/documents/doc1/blocks":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"code": 0,
+				"data": map[string]any{
+					"items": []map[string]any{
+						{"block_id": "doc1", "block_type": 1, "page": text("Doc"), "children": []string{"b1", "b2", "b3", "b4"}},
+						{"block_id": "b1", "parent_id": "doc1", "block_type": 3, "heading1": text("Title")},
+						{"block_id": "b2", "parent_id": "doc1", "block_type": 2, "text": text("Old paragraph")},
+						{"block_id": "b3", "parent_id": "doc1", "block_type": 2, "text": text("Keep me")},
+						{"block_id": "b4", "parent_id": "doc1", "block_type": 22, "divider": map[string]any{}},
+					},
+					"has_more": false,
+				},
+			})
+		case r.Method == http.MethodPatch && r.URL.Path == "/open-apis/docx/v1/documents/doc1/blocks/batch_update":
+			for _, req := range decode()["requests"].([]any) {
+				updates = append(updates, req.(map[string]any))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{}})
+		case r.Method == http.MethodDelete && r.URL.Path == "/open-apis/docx/v1/documents/doc1/blocks/doc1/children/batch_delete":
+			deleted = append(deleted, decode())
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{}})
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/docx/v1/documents/doc1/blocks/doc1/children":
+			created = append(created, decode())
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, false)
+
+	cmd := newDocsCmd(state)
+	cmd.SetArgs([]string{"apply", "doc1", "--content", "# Title"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("docs apply error: %v", err)
+	}
+
+	if len(updates) != 1 || updates[0]["block_id"] != "b2" {
+		t.Fatalf("unexpected updates: %v", updates)
+	}
+	if len(deleted) != 1 || deleted[0]["start_index"] != float64(3) || deleted[0]["end_index"] != float64(4) {
+		t.Fatalf("unexpected deletes: %v", deleted)
+	}
+	if len(created) != 1 || created[0]["index"] != float64(3) {
+		t.Fatalf("unexpected inserts: %v", created)
+	}
+	children := created[0]["children"].([]any)
+	if len(children) != 1 || children[0].(map[string]any)["block_id"] != nil {
+		t.Fatalf("inserted blocks should not carry converter ids: %v", children)
+	}
+	if !strings.Contains(buf.String(), "updated: 1, inserted: 1, deleted: 1, unchanged: 2") {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestDocsApplyInsertsInChunks(t *testing.T) {
+	text := func(content string) map[string]any {
+		return map[string]any{"elements": []map[string]any{{"text_run": map[string]any{"content": content}}}}
+	}
+	blocks := make([]map[string]any, 0, 120)
+	ids := make([]string, 0, 120)
+	for i := range 120 {
+		id := fmt.Sprintf("n%d", i)
+		ids = append(ids, id)
+		blocks = append(blocks, map[string]any{"block_id": id, "block_type": 2, "text": text(fmt.Sprintf("Line %d", i))})
+	}
+	var indexes []float64
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/docx/v1/documents/blocks/convert":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"code": 0,
+				"data": map[string]any{"first_level_block_ids": ids, "blocks": blocks},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/docx/v1/documents/doc1/blocks":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"code": 0,
+				"data": map[string]any{
+					"items":    []map[string]any{{"block_id": "doc1", "block_type": 1, "page": text("Doc")}},
+					"has_more": false,
+				},
+			})
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/docx/v1/documents/doc1/blocks/doc1/children":
+			var body struct {
+				Index    float64 `json:"index"`
+				Children []any   `json:"children"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if len(body.Children) > docxCreateChildrenMaxBlocks {
+				t.Fatalf("too many children in one request: %d", len(body.Children))
+			}
+			indexes = append(indexes, body.Index)
+			if len(indexes) == 3 {
+				_ = json.NewEncoder(w).Encode(map[string]any{"code": 1770001, "msg": "invalid param"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, _ := newTestState(t, handler, false)
+
+	cmd := newDocsCmd(state)
+	cmd.SetArgs([]string{"apply", "doc1", "--content", "lines"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "apply hunk 1 of 1") || !strings.Contains(err.Error(), "100 of 120 blocks inserted") {
+		t.Fatalf("expected partial insert error, got %v", err)
+	}
+	if len(indexes) != 3 || indexes[0] != 0 || indexes[1] != 50 || indexes[2] != 100 {
+		t.Fatalf("unexpected insert indexes: %v", indexes)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -80,7 +81,7 @@ func newDocsBlocksGetCmd(state *appState) *cobra.Command {
 }
 
 func newDocsBlocksListCmd(state *appState) *cobra.Command {
-	var limit int
+	var paging listPaging
 	var pageSize int
 	var revisionID int
 	var userIDType string
@@ -98,8 +99,8 @@ func newDocsBlocksListCmd(state *appState) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				return flagUsage(cmd, "limit must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			if _, err := requireSDK(state); err != nil {
 				return err
@@ -110,50 +111,40 @@ func newDocsBlocksListCmd(state *appState) *cobra.Command {
 				return err
 			}
 
-			if pageSize <= 0 {
-				if limit < docxBlocksMaxPageSize {
-					pageSize = limit
-				} else {
-					pageSize = docxBlocksMaxPageSize
-				}
+			size := docxBlocksMaxPageSize
+			if pageSize > 0 {
+				size = min(pageSize, docxBlocksMaxPageSize)
 			}
-
-			blocks := make([]*larkdocx.Block, 0, limit)
-			pageToken := ""
-			for {
+			collected := newListCollector[*larkdocx.Block](state, paging.itemLimit())
+			pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[*larkdocx.Block], error) {
 				items, nextToken, hasMore, err := state.SDK.ListDocxBlocks(
-					cmd.Context(),
+					ctx,
 					token,
 					larksdk.AccessTokenType(tokenTypeValue),
 					documentID,
-					pageSize,
+					collected.pageSize(size),
 					pageToken,
 					revisionID,
 					userIDType,
 				)
-				if err != nil {
-					return err
-				}
-				blocks = append(blocks, items...)
-				if len(blocks) >= limit {
-					break
-				}
-				if !hasMore || nextToken == "" {
-					break
-				}
-				pageToken = nextToken
-			}
-			if len(blocks) > limit {
-				blocks = blocks[:limit]
+				return larksdk.Page[*larkdocx.Block]{Items: items, PageToken: nextToken, HasMore: hasMore}, err
+			})
+			if err := drainPages(cmd.Context(), state, collected, pager, 0); err != nil {
+				return err
 			}
+			if collected.streaming {
+				return nil
+			}
+			blocks := collected.items
 			payload := map[string]any{"blocks": blocks}
 			text := docxBlocksTable(blocks, "no blocks found")
 			return state.Printer.Print(payload, text)
 		},
 	}
 
-	cmd.Flags().IntVar(&limit, "limit", 200, "max number of blocks to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 200, "max number of blocks to return")
 	cmd.Flags().IntVar(&pageSize, "page-size", 0, "page size for list requests")
+	addPagingFlags(cmd, &paging)
 	cmd.Flags().IntVar(&revisionID, "revision-id", -1, "document revision id (-1 for latest)")
 	cmd.Flags().StringVar(&userIDType, "user-id-type", "", "user id type (open_id|union_id|user_id)")
 	return cmd
@@ -327,7 +318,7 @@ func newDocsBlocksChildrenCmd(state *appState) *cobra.Command {
 }
 
 func newDocsBlocksChildrenListCmd(state *appState) *cobra.Command {
-	var limit int
+	var paging listPaging
 	var pageSize int
 	var revisionID int
 	var withDescendants bool
@@ -349,8 +340,8 @@ func newDocsBlocksChildrenListCmd(state *appState) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				return flagUsage(cmd, "limit must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			if _, err := requireSDK(state); err != nil {
 				return err
@@ -362,53 +353,42 @@ func newDocsBlocksChildrenListCmd(state *appState) *cobra.Command {
 				return err
 			}
 
-			if pageSize <= 0 {
-				if limit < docxBlocksMaxPageSize {
-					pageSize = limit
-				} else {
-					pageSize = docxBlocksMaxPageSize
-				}
+			size := docxBlocksMaxPageSize
+			if pageSize > 0 {
+				size = min(pageSize, docxBlocksMaxPageSize)
 			}
-
-			blocks := make([]*larkdocx.Block, 0, limit)
-			pageToken := ""
-			for {
+			collected := newListCollector[*larkdocx.Block](state, paging.itemLimit())
+			pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[*larkdocx.Block], error) {
 				items, nextToken, hasMore, err := state.SDK.GetDocxBlockChildren(
-					cmd.Context(),
+					ctx,
 					token,
 					larksdk.AccessTokenType(tokenTypeValue),
 					documentID,
 					blockID,
-					pageSize,
+					collected.pageSize(size),
 					pageToken,
 					revisionID,
 					withDescendants,
 					userIDType,
 				)
-				if err != nil {
-					return err
-				}
-				blocks = append(blocks, items...)
-				if len(blocks) >= limit {
-					break
-				}
-				if !hasMore || nextToken == "" {
-					break
-				}
-				pageToken = nextToken
-			}
-			if len(blocks) > limit {
-				blocks = blocks[:limit]
+				return larksdk.Page[*larkdocx.Block]{Items: items, PageToken: nextToken, HasMore: hasMore}, err
+			})
+			if err := drainPages(cmd.Context(), state, collected, pager, 0); err != nil {
+				return err
 			}
-
+			if collected.streaming {
+				return nil
+			}
+			blocks := collected.items
 			payload := map[string]any{"blocks": blocks}
 			text := docxBlocksTable(blocks, "no blocks found")
 			return state.Printer.Print(payload, text)
 		},
 	}
 
-	cmd.Flags().IntVar(&limit, "limit", 200, "max number of blocks to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 200, "max number of blocks to return")
 	cmd.Flags().IntVar(&pageSize, "page-size", 0, "page size for list requests")
+	addPagingFlags(cmd, &paging)
 	cmd.Flags().IntVar(&revisionID, "revision-id", -1, "document revision id (-1 for latest)")
 	cmd.Flags().BoolVar(&withDescendants, "with-descendants", false, "include descendant blocks")
 	cmd.Flags().StringVar(&userIDType, "user-id-type", "", "user id type (open_id|union_id|user_id)")
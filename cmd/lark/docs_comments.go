@@ -0,0 +1,471 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+const maxDocCommentsPageSize = 100
+
+var docCommentFileTypes = []string{"docx", "doc", "sheet", "file"}
+
+// docCommentThread is a comment with its full reply thread and the authors'
+// display names.
+type docCommentThread struct {
+	larksdk.DriveComment
+	UserName string            `json:"user_name,omitempty"`
+	Replies  []docCommentReply `json:"replies"`
+}
+
+type docCommentReply struct {
+	larksdk.DriveCommentReply
+	UserName string `json:"user_name,omitempty"`
+}
+
+func newDocsCommentsCmd(state *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "comments",
+		Short: "Review document comments",
+		Long: `List, add, reply to, and resolve comments on a document.
+
+Each command takes a document token or URL. The file type is taken from the
+URL when possible and otherwise defaults to docx; use --type for legacy docs,
+sheets, or Drive files. The first reply in a thread is the comment itself.`,
+	}
+	cmd.AddCommand(newDocsCommentsListCmd(state))
+	cmd.AddCommand(newDocsCommentsAddCmd(state))
+	cmd.AddCommand(newDocsCommentsReplyCmd(state))
+	cmd.AddCommand(newDocsCommentsSolveCmd(state, true))
+	cmd.AddCommand(newDocsCommentsSolveCmd(state, false))
+	return cmd
+}
+
+func newDocsCommentsListCmd(state *appState) *cobra.Command {
+	var fileToken string
+	var fileType string
+	var wikiNode bool
+	var unresolved bool
+
+	cmd := &cobra.Command{
+		Use:   "list <doc>",
+		Short: "List comment threads with their quoted text and replies",
+		Long: `List every comment thread on a document, with the quoted anchor text,
+the replies, and the authors' names.
+
+--unresolved shows only open threads; its "count" field makes a simple merge
+gate, for example:
+
+  lark docs comments list <DOC> --unresolved --json | jq -e '.count == 0'`,
+		Example: `  lark docs comments list <DOCUMENT_ID>
+  lark docs comments list https://example.feishu.cn/docx/<DOCUMENT_ID> --unresolved
+  lark docs comments list https://example.feishu.cn/wiki/<NODE_TOKEN>`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			var err error
+			fileToken, err = parseDocCommentArgs(cmd, args, 1, &fileType, &wikiNode)
+			return err
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOneOf(cmd, "type", fileType, docCommentFileTypes); err != nil {
+				return err
+			}
+			if _, err := requireSDK(state); err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			token, tokenType, err := resolveAccessToken(ctx, state, tokenTypesTenantOrUser, nil)
+			if err != nil {
+				return err
+			}
+			if wikiNode {
+				if err := resolveDocCommentWikiNode(ctx, state.SDK, token, tokenType, &fileToken, &fileType); err != nil {
+					return err
+				}
+			}
+			var solved *bool
+			if unresolved {
+				solved = new(bool)
+			}
+			comments, err := listDocComments(ctx, state, token, tokenType, fileToken, fileType, solved)
+			if err != nil {
+				return err
+			}
+			threads := docCommentThreads(ctx, state, token, tokenType, comments)
+			payload := map[string]any{
+				"file_token": fileToken,
+				"type":       fileType,
+				"count":      len(threads),
+				"comments":   threads,
+			}
+			empty := "no comments"
+			if unresolved {
+				empty = "no unresolved comments"
+			}
+			return state.Printer.Print(payload, formatDocCommentThreads(threads, empty))
+		},
+	}
+
+	cmd.Flags().StringVar(&fileType, "type", "docx", "file type (docx, doc, sheet, file)")
+	cmd.Flags().BoolVar(&unresolved, "unresolved", false, "only show comments that are not resolved")
+	registerEnumCompletion(cmd, "type", docCommentFileTypes)
+	return cmd
+}
+
+func newDocsCommentsAddCmd(state *appState) *cobra.Command {
+	var fileToken string
+	var fileType string
+	var wikiNode bool
+	var text string
+
+	cmd := &cobra.Command{
+		Use:   "add <doc>",
+		Short: "Add a comment on the whole document",
+		Long: `Add a comment on the whole document. The API does not support anchoring a
+new comment to a text selection.`,
+		Example: `  lark docs comments add <DOCUMENT_ID> --text "Please add a rollout plan"`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			var err error
+			fileToken, err = parseDocCommentArgs(cmd, args, 1, &fileType, &wikiNode)
+			return err
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOneOf(cmd, "type", fileType, docCommentFileTypes); err != nil {
+				return err
+			}
+			if strings.TrimSpace(text) == "" {
+				return usageError(cmd, "--text is required", "")
+			}
+			return runWithToken(cmd, state, tokenTypesTenantOrUser, nil, func(ctx context.Context, sdk *larksdk.Client, token string, tokenType tokenType) (any, string, error) {
+				if wikiNode {
+					if err := resolveDocCommentWikiNode(ctx, sdk, token, tokenType, &fileToken, &fileType); err != nil {
+						return nil, "", err
+					}
+				}
+				comment, err := sdk.CreateDriveComment(ctx, token, larksdk.AccessTokenType(tokenType), larksdk.CreateDriveCommentRequest{
+					FileToken:  fileToken,
+					FileType:   fileType,
+					Text:       text,
+					UserIDType: "open_id",
+				})
+				if err != nil {
+					return nil, "", err
+				}
+				payload := map[string]any{"file_token": fileToken, "comment": comment}
+				return payload, fmt.Sprintf("added comment %s", comment.CommentID), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&fileType, "type", "docx", "file type (docx, doc, sheet, file)")
+	cmd.Flags().StringVar(&text, "text", "", "comment text")
+	registerEnumCompletion(cmd, "type", docCommentFileTypes)
+	return cmd
+}
+
+func newDocsCommentsReplyCmd(state *appState) *cobra.Command {
+	var fileToken string
+	var commentID string
+	var fileType string
+	var wikiNode bool
+	var text string
+
+	cmd := &cobra.Command{
+		Use:     "reply <doc> <comment-id>",
+		Short:   "Reply to a comment thread",
+		Example: `  lark docs comments reply <DOCUMENT_ID> <COMMENT_ID> --text "Done, thanks"`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			var err error
+			fileToken, err = parseDocCommentArgs(cmd, args, 2, &fileType, &wikiNode)
+			if err != nil {
+				return err
+			}
+			commentID = strings.TrimSpace(args[1])
+			if commentID == "" {
+				return argsUsageError(cmd, errors.New("comment-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOneOf(cmd, "type", fileType, docCommentFileTypes); err != nil {
+				return err
+			}
+			if strings.TrimSpace(text) == "" {
+				return usageError(cmd, "--text is required", "")
+			}
+			return runWithToken(cmd, state, tokenTypesTenantOrUser, nil, func(ctx context.Context, sdk *larksdk.Client, token string, tokenType tokenType) (any, string, error) {
+				if wikiNode {
+					if err := resolveDocCommentWikiNode(ctx, sdk, token, tokenType, &fileToken, &fileType); err != nil {
+						return nil, "", err
+					}
+				}
+				if _, err := sdk.CreateDriveComment(ctx, token, larksdk.AccessTokenType(tokenType), larksdk.CreateDriveCommentRequest{
+					FileToken:  fileToken,
+					FileType:   fileType,
+					CommentID:  commentID,
+					Text:       text,
+					UserIDType: "open_id",
+				}); err != nil {
+					return nil, "", err
+				}
+				comment, err := sdk.GetDriveComment(ctx, token, larksdk.AccessTokenType(tokenType), fileToken, fileType, commentID, "open_id")
+				if err != nil {
+					return nil, "", err
+				}
+				if comment.HasMoreReplies {
+					if comment.Replies, err = listDocCommentReplies(ctx, state, token, tokenType, fileToken, fileType, commentID); err != nil {
+						return nil, "", err
+					}
+				}
+				threads := docCommentThreads(ctx, state, token, tokenType, []larksdk.DriveComment{comment})
+				payload := map[string]any{"file_token": fileToken, "comment": threads[0]}
+				return payload, formatDocCommentThreads(threads, ""), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&fileType, "type", "docx", "file type (docx, doc, sheet, file)")
+	cmd.Flags().StringVar(&text, "text", "", "reply text")
+	registerEnumCompletion(cmd, "type", docCommentFileTypes)
+	return cmd
+}
+
+// newDocsCommentsSolveCmd builds resolve (solved=true) and unresolve.
+func newDocsCommentsSolveCmd(state *appState, solved bool) *cobra.Command {
+	var fileToken string
+	var commentID string
+	var fileType string
+	var wikiNode bool
+
+	use, short, done := "resolve", "Mark a comment thread as resolved", "resolved"
+	if !solved {
+		use, short, done = "unresolve", "Reopen a resolved comment thread", "reopened"
+	}
+	cmd := &cobra.Command{
+		Use:   use + " <doc> <comment-id>",
+		Short: short,
+		Args: func(cmd *cobra.Command, args []string) error {
+			var err error
+			fileToken, err = parseDocCommentArgs(cmd, args, 2, &fileType, &wikiNode)
+			if err != nil {
+				return err
+			}
+			commentID = strings.TrimSpace(args[1])
+			if commentID == "" {
+				return argsUsageError(cmd, errors.New("comment-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOneOf(cmd, "type", fileType, docCommentFileTypes); err != nil {
+				return err
+			}
+			return runWithToken(cmd, state, tokenTypesTenantOrUser, nil, func(ctx context.Context, sdk *larksdk.Client, token string, tokenType tokenType) (any, string, error) {
+				if wikiNode {
+					if err := resolveDocCommentWikiNode(ctx, sdk, token, tokenType, &fileToken, &fileType); err != nil {
+						return nil, "", err
+					}
+				}
+				if err := sdk.SetDriveCommentSolved(ctx, token, larksdk.AccessTokenType(tokenType), fileToken, fileType, commentID, solved); err != nil {
+					return nil, "", err
+				}
+				payload := map[string]any{"file_token": fileToken, "comment_id": commentID, "is_solved": solved}
+				return payload, fmt.Sprintf("%s comment %s", done, commentID), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&fileType, "type", "docx", "file type (docx, doc, sheet, file)")
+	registerEnumCompletion(cmd, "type", docCommentFileTypes)
+	return cmd
+}
+
+// parseDocCommentArgs checks the argument count and resolves the document
+// argument. A wiki link sets *wikiNode; the node is swapped for the document
+// it holds by resolveDocCommentWikiNode once a token is available.
+func parseDocCommentArgs(cmd *cobra.Command, args []string, n int, fileType *string, wikiNode *bool) (string, error) {
+	if err := cobra.ExactArgs(n)(cmd, args); err != nil {
+		return "", argsUsageError(cmd, err)
+	}
+	token, kind, err := parseResourceRef(args[0])
+	if err != nil {
+		return "", err
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", argsUsageError(cmd, errors.New("doc is required"))
+	}
+	*wikiNode = kind == "wiki"
+	if kind != "" && containsString(docCommentFileTypes, kind) && !cmd.Flags().Changed("type") {
+		*fileType = kind
+	}
+	return token, nil
+}
+
+// resolveDocCommentWikiNode replaces a wiki node token with the token and
+// type of the document it holds.
+func resolveDocCommentWikiNode(ctx context.Context, sdk *larksdk.Client, token string, tokenType tokenType, fileToken, fileType *string) error {
+	node, err := getWikiNode(ctx, sdk, token, tokenType, *fileToken)
+	if err != nil {
+		return err
+	}
+	if !containsString(docCommentFileTypes, node.ObjType) {
+		return fmt.Errorf("wiki page is a %s; comments are supported on %s", node.ObjType, strings.Join(docCommentFileTypes, ", "))
+	}
+	*fileToken, *fileType = node.ObjToken, node.ObjType
+	return nil
+}
+
+// listDocComments lists every comment and fills in threads whose replies span
+// more than the first page.
+func listDocComments(ctx context.Context, state *appState, token string, tokenType tokenType, fileToken, fileType string, solved *bool) ([]larksdk.DriveComment, error) {
+	pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.DriveComment], error) {
+		result, err := state.SDK.ListDriveComments(ctx, token, larksdk.AccessTokenType(tokenType), larksdk.ListDriveCommentsRequest{
+			FileToken:  fileToken,
+			FileType:   fileType,
+			Solved:     solved,
+			UserIDType: "open_id",
+			PageSize:   maxDocCommentsPageSize,
+			PageToken:  pageToken,
+		})
+		return larksdk.Page[larksdk.DriveComment]{Items: result.Items, PageToken: result.PageToken, HasMore: result.HasMore}, err
+	})
+	var comments []larksdk.DriveComment
+	for !pager.Done() {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, page...)
+	}
+	for i := range comments {
+		if !comments[i].HasMoreReplies {
+			continue
+		}
+		replies, err := listDocCommentReplies(ctx, state, token, tokenType, fileToken, fileType, comments[i].CommentID)
+		if err != nil {
+			return nil, err
+		}
+		comments[i].Replies = replies
+	}
+	return comments, nil
+}
+
+func listDocCommentReplies(ctx context.Context, state *appState, token string, tokenType tokenType, fileToken, fileType, commentID string) ([]larksdk.DriveCommentReply, error) {
+	pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.DriveCommentReply], error) {
+		result, err := state.SDK.ListDriveCommentReplies(ctx, token, larksdk.AccessTokenType(tokenType), larksdk.ListDriveCommentRepliesRequest{
+			FileToken:  fileToken,
+			FileType:   fileType,
+			CommentID:  commentID,
+			UserIDType: "open_id",
+			PageSize:   maxDocCommentsPageSize,
+			PageToken:  pageToken,
+		})
+		return larksdk.Page[larksdk.DriveCommentReply]{Items: result.Items, PageToken: result.PageToken, HasMore: result.HasMore}, err
+	})
+	replies := []larksdk.DriveCommentReply{}
+	for !pager.Done() {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		replies = append(replies, page...)
+	}
+	return replies, nil
+}
+
+// docCommentThreads attaches author names. Names come from the contact API,
+// which needs a tenant token; lookups that fail leave the name empty.
+func docCommentThreads(ctx context.Context, state *appState, token string, tokenType tokenType, comments []larksdk.DriveComment) []docCommentThread {
+	names := map[string]string{}
+	lookup := func(openID string) {
+		if openID == "" {
+			return
+		}
+		if _, ok := names[openID]; ok {
+			return
+		}
+		names[openID] = ""
+	}
+	for _, comment := range comments {
+		lookup(comment.UserID)
+		for _, reply := range comment.Replies {
+			lookup(reply.UserID)
+		}
+	}
+	if len(names) > 0 {
+		tenantToken := token
+		if tokenType != tokenTypeTenant {
+			var err error
+			if tenantToken, err = tokenFor(ctx, state, tokenTypesTenant); err != nil {
+				debugf(state, "comment author names unavailable: %v", err)
+				tenantToken = ""
+			}
+		}
+		if tenantToken != "" {
+			for openID := range names {
+				user, err := state.SDK.GetContactUser(ctx, tenantToken, larksdk.GetContactUserRequest{
+					UserID:     openID,
+					UserIDType: "open_id",
+				})
+				if err != nil {
+					debugf(state, "comment author %s: %v", openID, err)
+					continue
+				}
+				names[openID] = strings.TrimSpace(user.Name)
+			}
+		}
+	}
+
+	threads := make([]docCommentThread, 0, len(comments))
+	for _, comment := range comments {
+		thread := docCommentThread{
+			DriveComment: comment,
+			UserName:     names[comment.UserID],
+			Replies:      make([]docCommentReply, 0, len(comment.Replies)),
+		}
+		for _, reply := range comment.Replies {
+			thread.Replies = append(thread.Replies, docCommentReply{DriveCommentReply: reply, UserName: names[reply.UserID]})
+		}
+		threads = append(threads, thread)
+	}
+	return threads
+}
+
+func formatDocCommentThreads(threads []docCommentThread, empty string) string {
+	if len(threads) == 0 {
+		return empty
+	}
+	blocks := make([]string, 0, len(threads))
+	for _, thread := range threads {
+		status := "open"
+		if thread.IsSolved {
+			status = "resolved"
+		}
+		quote := "(whole document)"
+		if !thread.IsWhole && thread.Quote != "" {
+			quote = fmt.Sprintf("> %s", strings.Join(strings.Fields(thread.Quote), " "))
+		}
+		lines := []string{fmt.Sprintf("%s [%s] %s", thread.CommentID, status, quote)}
+		for _, reply := range thread.Replies {
+			author := reply.UserName
+			if author == "" {
+				author = reply.UserID
+			}
+			header := author
+			if reply.CreateTime > 0 {
+				header += " · " + formatUnixTime(reply.CreateTime)
+			}
+			lines = append(lines, "  "+header)
+			for _, line := range strings.Split(strings.TrimRight(reply.Text, "\n"), "\n") {
+				lines = append(lines, "    "+line)
+			}
+		}
+		blocks = append(blocks, strings.Join(lines, "\n"))
+	}
+	return strings.Join(blocks, "\n\n")
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDocsCommentsListUnresolvedWithThreads(t *testing.T) {
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query()
+		if strings.HasPrefix(r.URL.Path, "/open-apis/contact/v3/users/") {
+			names := map[string]string{"ou_a": "Ada", "ou_b": "Bob"}
+			id := strings.TrimPrefix(r.URL.Path, "/open-apis/contact/v3/users/")
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"user": map[string]any{"open_id": id, "name": names[id]}}})
+			return
+		}
+		requests = append(requests, r.URL.Path+"?"+query.Get("page_token"))
+		switch r.URL.Path {
+		case "/open-apis/drive/v1/files/doxc1/comments":
+			if query.Get("file_type") != "docx" || query.Get("is_solved") != "false" {
+				t.Fatalf("unexpected query: %s", r.URL.RawQuery)
+			}
+			if query.Get("page_token") == "" {
+				_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": true, "page_token": "p2", "items": []map[string]any{
+					{
+						"comment_id": "c1", "user_id": "ou_a", "is_solved": false, "is_whole": false, "quote": "rollout plan",
+						"has_more": true, "page_token": "r2",
+						"reply_list": map[string]any{"replies": []map[string]any{
+							{"reply_id": "r1", "user_id": "ou_a", "create_time": 1700000000, "content": map[string]any{"elements": []map[string]any{
+								{"type": "text_run", "text_run": map[string]any{"text": "Needs dates"}},
+							}}},
+						}},
+					},
+				}}})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+				{"comment_id": "c2", "user_id": "ou_b", "is_solved": false, "is_whole": true, "reply_list": map[string]any{"replies": []map[string]any{
+					{"reply_id": "r9", "user_id": "ou_b", "content": map[string]any{"elements": []map[string]any{
+						{"type": "person", "person": map[string]any{"user_id": "ou_a"}},
+						{"type": "text_run", "text_run": map[string]any{"text": " ping"}},
+					}}},
+				}}},
+			}}})
+		case "/open-apis/drive/v1/files/doxc1/comments/c1/replies":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+				{"reply_id": "r1", "user_id": "ou_a", "content": map[string]any{"elements": []map[string]any{
+					{"type": "text_run", "text_run": map[string]any{"text": "Needs dates"}},
+				}}},
+				{"reply_id": "r2", "user_id": "ou_b", "content": map[string]any{"elements": []map[string]any{
+					{"type": "text_run", "text_run": map[string]any{"text": "Added"}},
+				}}},
+			}}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newDocsCmd(state)
+	cmd.SetArgs([]string{"comments", "list", "https://example.feishu.cn/docx/doxc1", "--unresolved"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("comments list error: %v", err)
+	}
+	want := "/open-apis/drive/v1/files/doxc1/comments? /open-apis/drive/v1/files/doxc1/comments?p2 /open-apis/drive/v1/files/doxc1/comments/c1/replies?"
+	if strings.Join(requests, " ") != want {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	var payload struct {
+		Count    int                `json:"count"`
+		Comments []docCommentThread `json:"comments"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if payload.Count != 2 || len(payload.Comments) != 2 {
+		t.Fatalf("unexpected payload: %s", buf.String())
+	}
+	first := payload.Comments[0]
+	if first.Quote != "rollout plan" || first.UserName != "Ada" || len(first.Replies) != 2 || first.Replies[1].UserName != "Bob" {
+		t.Fatalf("unexpected first thread: %+v", first)
+	}
+	if got := payload.Comments[1].Replies[0].Text; got != "@ou_a ping" {
+		t.Fatalf("unexpected mention text: %q", got)
+	}
+}
+
+func TestDocsCommentsListText(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/open-apis/drive/v1/files/doxc1/comments":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+				{"comment_id": "c1", "user_id": "ou_a", "is_solved": false, "quote": "rollout plan", "reply_list": map[string]any{"replies": []map[string]any{
+					{"reply_id": "r1", "user_id": "ou_a", "content": map[string]any{"elements": []map[string]any{{"type": "text_run", "text_run": map[string]any{"text": "Needs dates"}}}}},
+					{"reply_id": "r2", "user_id": "ou_b", "content": map[string]any{"elements": []map[string]any{{"type": "text_run", "text_run": map[string]any{"text": "Added"}}}}},
+				}}},
+				{"comment_id": "c2", "user_id": "ou_b", "is_solved": false, "is_whole": true},
+			}}})
+		case strings.HasPrefix(r.URL.Path, "/open-apis/contact/v3/users/"):
+			names := map[string]string{"ou_a": "Ada", "ou_b": "Bob"}
+			id := strings.TrimPrefix(r.URL.Path, "/open-apis/contact/v3/users/")
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"user": map[string]any{"open_id": id, "name": names[id]}}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, false)
+
+	cmd := newDocsCmd(state)
+	cmd.SetArgs([]string{"comments", "list", "doxc1", "--unresolved"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("comments list error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"c1 [open] > rollout plan", "  Bob\n    Added", "c2 [open] (whole document)"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestDocsCommentsReplyAndResolve(t *testing.T) {
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(r.URL.Path, "/open-apis/contact/v3/users/") {
+			id := strings.TrimPrefix(r.URL.Path, "/open-apis/contact/v3/users/")
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"user": map[string]any{"open_id": id, "name": id}}})
+			return
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.URL.Path == "/open-apis/drive/v1/files/doxc1/comments" && r.Method == http.MethodPost:
+			var body struct {
+				CommentID string `json:"comment_id"`
+				ReplyList struct {
+					Replies []struct {
+						Content struct {
+							Elements []struct {
+								TextRun struct {
+									Text string `json:"text"`
+								} `json:"text_run"`
+							} `json:"elements"`
+						} `json:"content"`
+					} `json:"replies"`
+				} `json:"reply_list"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body.CommentID != "c1" || body.ReplyList.Replies[0].Content.Elements[0].TextRun.Text != "Done" {
+				t.Fatalf("unexpected body: %+v", body)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"comment_id": "c1"}})
+		case r.URL.Path == "/open-apis/drive/v1/files/doxc1/comments/c1" && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"comment_id": "c1", "user_id": "ou_a", "quote": "rollout plan", "reply_list": map[string]any{"replies": []map[string]any{
+					{"reply_id": "r1", "user_id": "ou_a", "content": map[string]any{"elements": []map[string]any{{"type": "text_run", "text_run": map[string]any{"text": "Needs dates"}}}}},
+					{"reply_id": "r3", "user_id": "ou_b", "content": map[string]any{"elements": []map[string]any{{"type": "text_run", "text_run": map[string]any{"text": "Done"}}}}},
+				}},
+			}})
+		case r.URL.Path == "/open-apis/drive/v1/files/doxc1/comments/c1" && r.Method == http.MethodPatch:
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body["is_solved"] != true || r.URL.Query().Get("file_type") != "docx" {
+				t.Fatalf("unexpected patch: %v %s", body, r.URL.RawQuery)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newDocsCmd(state)
+	cmd.SetArgs([]string{"comments", "reply", "doxc1", "c1", "--text", "Done"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("comments reply error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"text": "Done"`) {
+		t.Fatalf("reply output should include the thread: %s", buf.String())
+	}
+
+	buf.Reset()
+	cmd = newDocsCmd(state)
+	cmd.SetArgs([]string{"comments", "resolve", "doxc1", "c1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("comments resolve error: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"is_solved": true`) {
+		t.Fatalf("unexpected resolve output: %s", buf.String())
+	}
+	want := "POST /open-apis/drive/v1/files/doxc1/comments,GET /open-apis/drive/v1/files/doxc1/comments/c1,PATCH /open-apis/drive/v1/files/doxc1/comments/c1"
+	if strings.Join(requests, ",") != want {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+}
+
+func TestDocsCommentsResolvesWikiLinks(t *testing.T) {
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.URL.Query().Get("file_type"))
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/open-apis/wiki/v2/spaces/get_node":
+			if r.URL.Query().Get("token") != "wikcn1" {
+				t.Fatalf("unexpected node query: %s", r.URL.RawQuery)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"node": map[string]any{
+				"node_token": "wikcn1", "obj_token": "shtcn1", "obj_type": "sheet", "title": "Budget",
+			}}})
+		case r.URL.Path == "/open-apis/drive/v1/files/shtcn1/comments":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{}}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newDocsCmd(state)
+	cmd.SetArgs([]string{"comments", "list", "https://example.feishu.cn/wiki/wikcn1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("comments list error: %v", err)
+	}
+	if strings.Join(requests, ",") != "GET /open-apis/wiki/v2/spaces/get_node ,GET /open-apis/drive/v1/files/shtcn1/comments sheet" {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	if !strings.Contains(buf.String(), `"file_token": "shtcn1"`) || !strings.Contains(buf.String(), `"type": "sheet"`) {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestDocsCommentsRejectsWikiNodesWithoutComments(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/open-apis/wiki/v2/spaces/get_node" {
+			t.Fatalf("unexpected request: %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"node": map[string]any{
+			"node_token": "wikcn1", "obj_token": "bascn1", "obj_type": "bitable",
+		}}})
+	})
+	state, _ := newTestState(t, handler, true)
+
+	cmd := newDocsCmd(state)
+	cmd.SetArgs([]string{"comments", "add", "https://example.feishu.cn/wiki/wikcn1", "--text", "hi"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "wiki page is a bitable") {
+		t.Fatalf("expected unsupported type error, got %v", err)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+
+	"lark/internal/larksdk"
+)
+
+const defaultDocsAssetsDirName = "assets"
+
+var docxAssetNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+type docxExportAsset struct {
+	Token string `json:"token"`
+	Kind  string `json:"kind"`
+	Path  string `json:"path,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+type docxMarkdownExport struct {
+	Content string
+	Assets  []docxExportAsset
+}
+
+// exportDocxMarkdown renders a document as archive-quality Markdown. Image and
+// file blocks are downloaded into assetsDir and linked relative to linkBase.
+func exportDocxMarkdown(ctx context.Context, state *appState, token string, tokenType larksdk.AccessTokenType, documentID, assetsDir, linkBase string) (docxMarkdownExport, error) {
+	blocks, err := listDocxBlocks(ctx, state.SDK, token, tokenType, documentID)
+	if err != nil {
+		return docxMarkdownExport{}, err
+	}
+	result := docxMarkdownExport{}
+	opts := docxMarkdownOptions{Rich: true, Assets: map[string]string{}, Mentions: map[string]string{}}
+
+	media := collectDocxMedia(blocks)
+	if len(media) > 0 {
+		if err := os.MkdirAll(assetsDir, 0o755); err != nil {
+			return docxMarkdownExport{}, err
+		}
+	}
+	for _, item := range media {
+		path, err := downloadDocxAsset(ctx, state, token, tokenType, item, assetsDir)
+		if err != nil {
+			item.Error = err.Error()
+			result.Assets = append(result.Assets, item)
+			fmt.Fprintf(errWriter(state), "warning: asset %s not downloaded: %v\n", item.Token, err)
+			continue
+		}
+		link, err := filepath.Rel(linkBase, path)
+		if err != nil {
+			link = path
+		}
+		item.Path = path
+		opts.Assets[item.Token] = filepath.ToSlash(link)
+		result.Assets = append(result.Assets, item)
+	}
+
+	for _, openID := range collectDocxMentions(blocks) {
+		user, err := state.SDK.GetContactUser(ctx, token, larksdk.GetContactUserRequest{
+			UserID:     openID,
+			UserIDType: "open_id",
+		})
+		if err != nil {
+			continue
+		}
+		if name := strings.TrimSpace(user.Name); name != "" {
+			opts.Mentions[openID] = name
+		}
+	}
+
+	result.Content = docxBlocksMarkdownWithOptions(documentID, blocks, opts)
+	if result.Content != "" && !strings.HasSuffix(result.Content, "\n") {
+		result.Content += "\n"
+	}
+	return result, nil
+}
+
+func collectDocxMedia(blocks []*larkdocx.Block) []docxExportAsset {
+	seen := map[string]bool{}
+	items := make([]docxExportAsset, 0)
+	add := func(token, kind string) {
+		token = strings.TrimSpace(token)
+		if token == "" || seen[token] {
+			return
+		}
+		seen[token] = true
+		items = append(items, docxExportAsset{Token: token, Kind: kind})
+	}
+	for _, block := range blocks {
+		if block == nil {
+			continue
+		}
+		if block.Image != nil {
+			add(valueOrEmpty(block.Image.Token), "image")
+		}
+		if block.File != nil {
+			add(valueOrEmpty(block.File.Token), "file")
+		}
+		for _, text := range docxBlockTextFields(block) {
+			for _, el := range text.Elements {
+				if el != nil && el.File != nil {
+					add(valueOrEmpty(el.File.FileToken), "file")
+				}
+			}
+		}
+	}
+	return items
+}
+
+func collectDocxMentions(blocks []*larkdocx.Block) []string {
+	seen := map[string]bool{}
+	ids := make([]string, 0)
+	for _, block := range blocks {
+		for _, text := range docxBlockTextFields(block) {
+			for _, el := range text.Elements {
+				if el == nil || el.MentionUser == nil {
+					continue
+				}
+				id := strings.TrimSpace(valueOrEmpty(el.MentionUser.UserId))
+				if id != "" && !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+				}
+			}
+		}
+	}
+	return ids
+}
+
+func docxBlockTextFields(block *larkdocx.Block) []*larkdocx.Text {
+	if block == nil {
+		return nil
+	}
+	candidates := []*larkdocx.Text{
+		block.Page, block.Text, block.Heading1, block.Heading2, block.Heading3,
+		block.Heading4, block.Heading5, block.Heading6, block.Heading7, block.Heading8,
+		block.Heading9, block.Bullet, block.Ordered, block.Code, block.Quote,
+		block.Equation, block.Todo,
+	}
+	texts := make([]*larkdocx.Text, 0, 1)
+	for _, text := range candidates {
+		if text != nil {
+			texts = append(texts, text)
+		}
+	}
+	return texts
+}
+
+func downloadDocxAsset(ctx context.Context, state *appState, token string, tokenType larksdk.AccessTokenType, item docxExportAsset, assetsDir string) (string, error) {
+	download, err := state.SDK.DownloadDriveMedia(ctx, token, tokenType, item.Token)
+	if err != nil {
+		return "", err
+	}
+	defer download.Reader.Close()
+
+	// Sniff the first bytes so images without a server-provided name still get
+	// an extension Markdown viewers recognize.
+	head := make([]byte, 512)
+	n, err := io.ReadFull(download.Reader, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	head = head[:n]
+	name := docxAssetFileName(item.Token, download.FileName, head)
+	path := filepath.Join(assetsDir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(file, io.MultiReader(bytes.NewReader(head), download.Reader)); err != nil {
+		file.Close()
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func docxAssetFileName(token, serverName string, head []byte) string {
+	serverName = strings.TrimSpace(serverName)
+	if serverName != "" {
+		serverName = filepath.Base(serverName)
+	}
+	ext := strings.ToLower(filepath.Ext(serverName))
+	if ext == "" {
+		switch http.DetectContentType(head) {
+		case "image/png":
+			ext = ".png"
+		case "image/jpeg":
+			ext = ".jpg"
+		case "image/gif":
+			ext = ".gif"
+		case "image/webp":
+			ext = ".webp"
+		case "application/pdf":
+			ext = ".pdf"
+		}
+	}
+	base := strings.TrimSuffix(serverName, filepath.Ext(serverName))
+	base = strings.Trim(docxAssetNameUnsafe.ReplaceAllString(base, "_"), "_.")
+	if base == "" {
+		return token + ext
+	}
+	return token + "_" + base + ext
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"lark/internal/config"
+	"lark/internal/larksdk"
+	"lark/internal/output"
+	"lark/internal/testutil"
+)
+
+func TestDocsExportMarkdownWithAssets(t *testing.T) {
+	pngBytes := []byte("\x89PNG\r\n\x1a\nimage-bytes")
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			t.Fatalf("missing auth header")
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/docx/v1/documents/doc1/blocks":
+			text := func(content string) map[string]any {
+				return map[string]any{"elements": []map[string]any{{"text_run": map[string]any{"content": content}}}}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"code": 0,
+				"data": map[string]any{
+					"items": []map[string]any{
+						{"block_id": "doc1", "block_type": 1, "page": text("Doc"), "children": []string{"p1", "img1", "call1", "eq1", "file1", "sheet1"}},
+						{"block_id": "p1", "parent_id": "doc1", "block_type": 2, "text": map[string]any{"elements": []map[string]any{
+							{"text_run": map[string]any{"content": "Owner: "}},
+							{"mention_user": map[string]any{"user_id": "ou_1"}},
+							{"text_run": map[string]any{"content": " area "}},
+							{"equation": map[string]any{"content": "\\pi r^2\n"}},
+						}}},
+						{"block_id": "img1", "parent_id": "doc1", "block_type": 27, "image": map[string]any{"token": "img_tok"}},
+						{"block_id": "call1", "parent_id": "doc1", "block_type": 19, "callout": map[string]any{"emoji_id": "warning"}, "children": []string{"call1_t"}},
+						{"block_id": "call1_t", "parent_id": "call1", "block_type": 2, "text": text("Be careful")},
+						{"block_id": "eq1", "parent_id": "doc1", "block_type": 16, "equation": map[string]any{"elements": []map[string]any{{"equation": map[string]any{"content": "E=mc^2\n"}}}}},
+						{"block_id": "file1", "parent_id": "doc1", "block_type": 23, "file": map[string]any{"token": "file_tok", "name": "spec.pdf"}},
+						{"block_id": "sheet1", "parent_id": "doc1", "block_type": 30, "sheet": map[string]any{"token": "sht_abc"}},
+					},
+					"has_more": false,
+				},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/drive/v1/medias/img_tok/download":
+			_, _ = w.Write(pngBytes)
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/drive/v1/medias/file_tok/download":
+			w.Header().Set("Content-Disposition", `attachment; filename="spec.pdf"`)
+			_, _ = w.Write([]byte("%PDF-1.4"))
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/contact/v3/users/ou_1":
+			if r.URL.Query().Get("user_id_type") != "open_id" {
+				t.Fatalf("unexpected user_id_type: %s", r.URL.RawQuery)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"code": 0,
+				"data": map[string]any{"user": map[string]any{"open_id": "ou_1", "name": "Alice"}},
+			})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	httpClient, baseURL := testutil.NewTestClient(handler)
+	var buf bytes.Buffer
+	state := &appState{
+		Config: &config.Config{
+			AppID:                      "app",
+			AppSecret:                  "secret",
+			BaseURL:                    baseURL,
+			TenantAccessToken:          "token",
+			TenantAccessTokenExpiresAt: time.Now().Add(2 * time.Hour).Unix(),
+		},
+		Printer: output.Printer{Writer: &buf},
+	}
+	sdkClient, err := larksdk.New(state.Config, larksdk.WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatalf("sdk client error: %v", err)
+	}
+	state.SDK = sdkClient
+
+	outDir := t.TempDir()
+	outPath := filepath.Join(outDir, "doc.md")
+	cmd := newDocsCmd(state)
+	cmd.SetArgs([]string{"export", "doc1", "--format", "md", "--out", outPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("docs export error: %v", err)
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read markdown: %v", err)
+	}
+	got := string(data)
+	for _, want := range []string{
+		"Owner: @Alice area $\\pi r^2$",
+		"![image](assets/img_tok.png)",
+		"> [!WARNING] <!-- emoji: warning -->\n> Be careful",
+		"$$\nE=mc^2\n$$",
+		"[spec.pdf](assets/file_tok_spec.pdf)",
+		"<!-- sheet: sht_abc -->",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in markdown:\n%s", want, got)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "assets", "img_tok.png")); err != nil {
+		t.Fatalf("expected image asset: %v", err)
+	}
+	if !strings.Contains(buf.String(), "doc1\t"+outPath) {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestDocsExportMarkdownCustomAssetsDir(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/docx/v1/documents/doc1/blocks":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"code": 0,
+				"data": map[string]any{
+					"items": []map[string]any{
+						{"block_id": "doc1", "block_type": 1, "page": map[string]any{"elements": []map[string]any{{"text_run": map[string]any{"content": "Doc"}}}}, "children": []string{"img1"}},
+						{"block_id": "img1", "parent_id": "doc1", "block_type": 27, "image": map[string]any{"token": "img_tok"}},
+					},
+					"has_more": false,
+				},
+			})
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/drive/v1/medias/img_tok/download":
+			_, _ = w.Write([]byte("\x89PNG\r\n\x1a\nimage-bytes"))
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	httpClient, baseURL := testutil.NewTestClient(handler)
+	state := &appState{
+		Config: &config.Config{
+			AppID:                      "app",
+			AppSecret:                  "secret",
+			BaseURL:                    baseURL,
+			TenantAccessToken:          "token",
+			TenantAccessTokenExpiresAt: time.Now().Add(2 * time.Hour).Unix(),
+		},
+		Printer: output.Printer{Writer: &bytes.Buffer{}},
+	}
+	sdkClient, err := larksdk.New(state.Config, larksdk.WithHTTPClient(httpClient))
+	if err != nil {
+		t.Fatalf("sdk client error: %v", err)
+	}
+	state.SDK = sdkClient
+
+	root := t.TempDir()
+	outPath := filepath.Join(root, "docs", "doc.md")
+	if err := os.MkdirAll(filepath.Dir(outPath), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	cmd := newDocsCmd(state)
+	cmd.SetArgs([]string{"export", "doc1", "--format", "md", "--out", outPath, "--assets-dir", filepath.Join(root, "media")})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("docs export error: %v", err)
+	}
+	data, _ := os.ReadFile(outPath)
+	if !strings.Contains(string(data), "![image](../media/img_tok.png)") {
+		t.Fatalf("expected relative asset link, got:\n%s", data)
+	}
+}
+
+func TestDocsExportAssetsDirRequiresMarkdown(t *testing.T) {
+	state := &appState{Printer: output.Printer{Writer: &bytes.Buffer{}}}
+	cmd := newDocsCmd(state)
+	cmd.SetArgs([]string{"export", "doc1", "--format", "pdf", "--out", "x.pdf", "--assets-dir", "assets"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--assets-dir requires --format md") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
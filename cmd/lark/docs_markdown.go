@@ -269,3 +269,40 @@ func clearDocxBlockChildren(ctx context.Context, sdk *larksdk.Client, token stri
 		total += end
 	}
 }
+
+// replaceDocxMarkdown replaces a document's content with converted Markdown,
+// the same way docs overwrite does. Local images resolve relative to
+// contentFile.
+func replaceDocxMarkdown(ctx context.Context, state *appState, token string, tokenType tokenType, documentID, content, contentFile string) error {
+	accessType := larksdk.AccessTokenType(tokenType)
+	converted, err := state.SDK.ConvertDocxContent(ctx, token, accessType, larkdocx.ContentTypeMarkdown, content)
+	if err != nil {
+		return err
+	}
+	if converted == nil {
+		return errors.New("convert returned empty response")
+	}
+	scrubDocxTableMergeInfo(converted.Blocks)
+	if len(converted.BlockIdToImageUrls) > 0 {
+		summary, err := uploadDocxImageBlocks(ctx, state.SDK, token, documentID, contentFile, converted)
+		if err != nil {
+			return err
+		}
+		for _, record := range summary.Records {
+			if record.Error != "" {
+				fmt.Fprintf(errWriter(state), "warning: image %s in %s not uploaded: %s\n", record.ImageURL, contentFile, record.Error)
+			}
+		}
+	}
+	if _, err := clearDocxBlockChildren(ctx, state.SDK, token, accessType, documentID, documentID); err != nil {
+		return err
+	}
+	if len(converted.FirstLevelBlockIds) == 0 {
+		return nil
+	}
+	_, err = state.SDK.CreateDocxBlockDescendant(ctx, token, accessType, documentID, documentID, &larkdocx.CreateDocumentBlockDescendantReqBody{
+		ChildrenId:  converted.FirstLevelBlockIds,
+		Descendants: converted.Blocks,
+	}, -1, "", "")
+	return err
+}
@@ -6,12 +6,13 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
 )
 
 func newDocsSearchCmd(state *appState) *cobra.Command {
 	var query string
-	var limit int
-	var pages int
+	var paging listPaging
 
 	cmd := &cobra.Command{
 		Use:   "search <query>",
@@ -27,11 +28,8 @@ func newDocsSearchCmd(state *appState) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				return flagUsage(cmd, "limit must be greater than 0")
-			}
-			if pages <= 0 {
-				return flagUsage(cmd, "pages must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			ctx := cmd.Context()
 			token, err := resolveDriveSearchToken(ctx, state)
@@ -41,10 +39,14 @@ func newDocsSearchCmd(state *appState) *cobra.Command {
 			if _, err := requireSDK(state); err != nil {
 				return err
 			}
-			files, err := docsSearchDriveFiles(ctx, state, token, "docs", query, []string{"doc"}, limit, pages)
-			if err != nil {
+			collected := newListCollector[larksdk.DriveFile](state, paging.itemLimit())
+			if err := docsSearchDriveFiles(ctx, state, token, "docs", query, []string{"doc"}, collected, paging.pageLimit()); err != nil {
 				return err
 			}
+			if collected.streaming {
+				return nil
+			}
+			files := collected.items
 			payload := map[string]any{"files": files}
 			lines := make([]string, 0, len(files))
 			for _, file := range files {
@@ -56,8 +58,9 @@ func newDocsSearchCmd(state *appState) *cobra.Command {
 	}
 	annotateAuthServices(cmd, "search-docs")
 
-	cmd.Flags().IntVar(&limit, "limit", 50, "max number of files to return")
-	cmd.Flags().IntVar(&pages, "pages", 1, "max number of pages to fetch")
+	cmd.Flags().IntVar(&paging.limit, "limit", 50, "max number of files to return")
+	cmd.Flags().IntVar(&paging.pages, "pages", 1, "max number of pages to fetch")
+	addPagingFlags(cmd, &paging)
 
 	return cmd
 }
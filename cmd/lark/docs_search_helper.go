@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"lark/internal/larksdk"
@@ -11,44 +13,33 @@ import (
 const maxDocsSearchCount = 50
 const maxDocsSearchWindow = 199
 
-func docsSearchDriveFiles(ctx context.Context, state *appState, token, label, query string, docTypes []string, limit, pages int) ([]larksdk.DriveFile, error) {
+// docsSearchDriveFiles pages through the docs search API, which uses offsets
+// rather than page tokens; the offset is carried as the paginator token.
+func docsSearchDriveFiles(ctx context.Context, state *appState, token, label, query string, docTypes []string, files *listCollector[larksdk.DriveFile], maxPages int) error {
 	if state == nil {
-		return nil, errors.New("state is required")
+		return errors.New("state is required")
 	}
 	if state.SDK == nil {
-		return nil, errors.New("sdk client is required")
-	}
-	if limit <= 0 {
-		return nil, errors.New("limit must be greater than 0")
-	}
-	if pages <= 0 {
-		return nil, errors.New("pages must be greater than 0")
+		return errors.New("sdk client is required")
 	}
 	normalizedTypes := normalizeDocsSearchTypes(docTypes)
 
-	debugf(state, "%s search: query=%q types=%v limit=%d pages=%d\n", label, query, normalizedTypes, limit, pages)
+	debugf(state, "%s search: query=%q types=%v limit=%d pages=%d\n", label, query, normalizedTypes, files.limit, maxPages)
 
-	files := make([]larksdk.DriveFile, 0, limit)
-	offset := 0
-	remaining := limit
-	pageCount := 0
-	for {
-		pageCount++
-		count := remaining
-		if count > maxDocsSearchCount {
-			count = maxDocsSearchCount
-		}
-		maxAllowed := maxDocsSearchWindow - offset
-		if maxAllowed <= 0 {
-			break
-		}
-		if count > maxAllowed {
-			count = maxAllowed
+	pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.DriveFile], error) {
+		offset := 0
+		if pageToken != "" {
+			parsed, err := strconv.Atoi(pageToken)
+			if err != nil {
+				return larksdk.Page[larksdk.DriveFile]{}, fmt.Errorf("invalid search offset %q", pageToken)
+			}
+			offset = parsed
 		}
+		count := min(files.pageSize(maxDocsSearchCount), maxDocsSearchWindow-offset)
 		if count <= 0 {
-			break
+			return larksdk.Page[larksdk.DriveFile]{}, nil
 		}
-		debugf(state, "%s search request: page=%d/%d count=%d offset=%d\n", label, pageCount, pages, count, offset)
+		debugf(state, "%s search request: count=%d offset=%d\n", label, count, offset)
 
 		result, err := state.SDK.SearchDocsObjectsWithUserToken(ctx, token, larksdk.DocsSearchRequest{
 			Query:    query,
@@ -57,23 +48,16 @@ func docsSearchDriveFiles(ctx context.Context, state *appState, token, label, qu
 			Offset:   offset,
 		})
 		if err != nil {
-			return nil, withUserScopeHintForCommand(state, err)
+			return larksdk.Page[larksdk.DriveFile]{}, withUserScopeHintForCommand(state, err)
 		}
 		debugf(state, "%s search response: entities=%d has_more=%t total=%d\n", label, len(result.Entities), result.HasMore, result.Total)
-		files = append(files, mapDocsSearchEntities(result.Entities)...)
-		if len(files) >= limit || !result.HasMore || pageCount >= pages {
-			break
-		}
-		remaining = limit - len(files)
-		offset += count
-		if offset <= 0 {
-			break
-		}
-	}
-	if len(files) > limit {
-		files = files[:limit]
-	}
-	return files, nil
+		return larksdk.Page[larksdk.DriveFile]{
+			Items:     mapDocsSearchEntities(result.Entities),
+			PageToken: strconv.Itoa(offset + count),
+			HasMore:   result.HasMore,
+		}, nil
+	})
+	return drainPages(ctx, state, files, pager, maxPages)
 }
 
 func normalizeDocsSearchTypes(types []string) []string {
@@ -0,0 +1,333 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	larkdocx "github.com/larksuite/oapi-sdk-go/v3/service/docx/v1"
+
+	"lark/internal/larksdk"
+)
+
+const docsWikiMoveMaxAttempts = 20
+
+var docsWikiMovePollInterval = 500 * time.Millisecond
+
+var (
+	docsTemplateVarName     = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	docsTemplatePlaceholder = regexp.MustCompile(`\{\{\s*\.?([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+	// docsTemplateReserved are the text/template builtins and keywords. A
+	// variable with one of these names would replace the builtin in bare
+	// {{key}} form, so such names are rejected.
+	docsTemplateReserved = []string{
+		"and", "call", "html", "index", "slice", "js", "len", "not", "or", "print", "printf", "println", "urlquery",
+		"eq", "ge", "gt", "le", "lt", "ne",
+		"block", "break", "continue", "define", "else", "end", "false", "if", "nil", "range", "template", "true", "with",
+	}
+)
+
+// docsTemplateResult describes how a templated document was produced.
+type docsTemplateResult struct {
+	Template   string   `json:"template"`
+	Source     string   `json:"source"`
+	NodeToken  string   `json:"node_token,omitempty"`
+	Replaced   int      `json:"replaced,omitempty"`
+	Unresolved []string `json:"unresolved,omitempty"`
+}
+
+// parseDocsTemplateVars parses --var key=value pairs. title and date are
+// always defined so templates can use them without extra flags.
+func parseDocsTemplateVars(values []string, title string) (map[string]string, error) {
+	vars := map[string]string{
+		"title": title,
+		"date":  time.Now().Format("2006-01-02"),
+	}
+	for _, raw := range values {
+		key, value, ok := strings.Cut(raw, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !docsTemplateVarName.MatchString(key) {
+			return nil, fmt.Errorf("invalid --var %q (expected key=value with a letter, digit or _ key)", raw)
+		}
+		if containsString(docsTemplateReserved, key) {
+			return nil, fmt.Errorf("invalid --var %q (%s is a template builtin)", raw, key)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+func isLocalDocsTemplate(ref string) bool {
+	lower := strings.ToLower(strings.TrimSpace(ref))
+	return strings.HasSuffix(lower, ".md") || strings.HasSuffix(lower, ".markdown")
+}
+
+// renderDocsTemplate executes a Markdown template. Variables are available
+// both as {{.key}} and as bare {{key}}, matching the placeholders substituted
+// in copied documents.
+func renderDocsTemplate(path string, vars map[string]string) (string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	funcs := template.FuncMap{}
+	for key, value := range vars {
+		funcs[key] = func() string { return value }
+	}
+	tmpl, err := template.New(path).Funcs(funcs).Option("missingkey=error").Parse(string(raw))
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+	var out strings.Builder
+	if err := tmpl.Execute(&out, vars); err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+	return out.String(), nil
+}
+
+// copyDocsTemplate copies a template document into a Drive folder. Without a
+// folder the copy lands next to the template. With wikiNode, a Wiki page
+// template is copied under the node with the Wiki copy API, and a docx
+// template is copied in Drive and then moved under the node.
+func copyDocsTemplate(ctx context.Context, state *appState, token string, tokenType tokenType, ref, title, folderID, wikiNode string) (larksdk.DocxDocument, string, error) {
+	templateToken, kind, err := parseResourceRef(ref)
+	if err != nil {
+		return larksdk.DocxDocument{}, "", err
+	}
+	switch kind {
+	case "", "docx", "wiki":
+	default:
+		return larksdk.DocxDocument{}, "", fmt.Errorf("template must be a docx document or wiki page, got %s", kind)
+	}
+	if wikiNode != "" && kind == "wiki" {
+		parent, err := getWikiNode(ctx, state.SDK, token, tokenType, wikiNode)
+		if err != nil {
+			return larksdk.DocxDocument{}, "", err
+		}
+		source, err := getWikiNode(ctx, state.SDK, token, tokenType, templateToken)
+		if err != nil {
+			return larksdk.DocxDocument{}, "", err
+		}
+		if source.ObjType != "docx" {
+			return larksdk.DocxDocument{}, "", fmt.Errorf("template wiki page is a %s, not a docx document", source.ObjType)
+		}
+		req := larksdk.CopyWikiNodeRequest{
+			SpaceID:               source.SpaceID,
+			NodeToken:             source.NodeToken,
+			TargetParentNodeToken: parent.NodeToken,
+			TargetSpaceID:         parent.SpaceID,
+			Title:                 title,
+		}
+		var node larksdk.WikiNode
+		switch tokenType {
+		case tokenTypeTenant:
+			node, err = state.SDK.CopyWikiNodeV2(ctx, token, req)
+		case tokenTypeUser:
+			node, err = state.SDK.CopyWikiNodeV2WithUserToken(ctx, token, req)
+		default:
+			err = fmt.Errorf("unsupported token type %s", tokenType)
+		}
+		if err != nil {
+			return larksdk.DocxDocument{}, "", err
+		}
+		return larksdk.DocxDocument{DocumentID: node.ObjToken, Title: node.Title}, node.NodeToken, nil
+	}
+
+	if kind == "wiki" {
+		node, err := getWikiNode(ctx, state.SDK, token, tokenType, templateToken)
+		if err != nil {
+			return larksdk.DocxDocument{}, "", err
+		}
+		if node.ObjType != "docx" {
+			return larksdk.DocxDocument{}, "", fmt.Errorf("template wiki page is a %s, not a docx document", node.ObjType)
+		}
+		templateToken = node.ObjToken
+	}
+	if folderID == "" {
+		file, err := driveFileMetadataWithToken(ctx, state.SDK, tokenType, token, templateToken)
+		if err != nil {
+			return larksdk.DocxDocument{}, "", err
+		}
+		if folderID = file.ParentID; folderID == "" {
+			return larksdk.DocxDocument{}, "", errors.New("template folder is unknown; pass --folder-id")
+		}
+	}
+	file, err := state.SDK.CopyDriveFile(ctx, token, larksdk.AccessTokenType(tokenType), larksdk.CopyDriveFileRequest{
+		FileToken:   templateToken,
+		FileType:    "docx",
+		Name:        title,
+		FolderToken: folderID,
+	})
+	if err != nil {
+		return larksdk.DocxDocument{}, "", err
+	}
+	doc := larksdk.DocxDocument{DocumentID: file.Token, Title: file.Name, URL: file.URL}
+	if wikiNode == "" {
+		return doc, "", nil
+	}
+	nodeToken, err := moveDocsToWikiNode(ctx, state, token, tokenType, file.Token, wikiNode)
+	if err != nil {
+		return larksdk.DocxDocument{}, "", fmt.Errorf("template copied to document %s, but moving it under wiki node %s failed: %w", file.Token, wikiNode, err)
+	}
+	// The Drive URL no longer applies once the document lives in the Wiki.
+	doc.URL = ""
+	return doc, nodeToken, nil
+}
+
+// moveDocsToWikiNode moves a docx document under a Wiki node and returns the
+// new node token, waiting for the move task when the API runs it in the
+// background.
+func moveDocsToWikiNode(ctx context.Context, state *appState, token string, tokenType tokenType, documentID, parentToken string) (string, error) {
+	parent, err := getWikiNode(ctx, state.SDK, token, tokenType, parentToken)
+	if err != nil {
+		return "", err
+	}
+	req := larksdk.MoveDocsToWikiRequest{
+		SpaceID:         parent.SpaceID,
+		ParentNodeToken: parent.NodeToken,
+		ObjType:         "docx",
+		ObjToken:        documentID,
+	}
+	var result larksdk.MoveDocsToWikiResult
+	switch tokenType {
+	case tokenTypeTenant:
+		result, err = state.SDK.MoveDocsToWikiV2(ctx, token, req)
+	case tokenTypeUser:
+		result, err = state.SDK.MoveDocsToWikiV2WithUserToken(ctx, token, req)
+	default:
+		err = fmt.Errorf("unsupported token type %s", tokenType)
+	}
+	if err != nil {
+		return "", err
+	}
+	if result.WikiToken != "" {
+		return result.WikiToken, nil
+	}
+	if result.TaskID == "" {
+		return "", errors.New("move was not started (permission may need to be requested)")
+	}
+
+	taskReq := larksdk.GetWikiTaskRequest{TaskID: result.TaskID, TaskType: "move"}
+	for attempt := 0; attempt < docsWikiMoveMaxAttempts; attempt++ {
+		var task larksdk.WikiTaskResult
+		switch tokenType {
+		case tokenTypeTenant:
+			task, err = state.SDK.GetWikiTaskV2(ctx, token, taskReq)
+		default:
+			task, err = state.SDK.GetWikiTaskV2WithUserToken(ctx, token, taskReq)
+		}
+		if err != nil {
+			return "", err
+		}
+		for _, moved := range task.MoveResult {
+			switch {
+			case moved.Status == 0 && moved.Node != nil:
+				return moved.Node.NodeToken, nil
+			case moved.Status != -1:
+				return "", fmt.Errorf("move task %s failed: %s (status %d)", result.TaskID, moved.StatusMsg, moved.Status)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(docsWikiMovePollInterval):
+		}
+	}
+	return "", fmt.Errorf("move task %s not finished after %d attempts; check it with lark wiki task info", result.TaskID, docsWikiMoveMaxAttempts)
+}
+
+// substituteDocxPlaceholders replaces {{key}} placeholders in the text runs of
+// a document. A placeholder split across differently styled runs is not
+// matched. Placeholders without a value are left in place and returned.
+func substituteDocxPlaceholders(ctx context.Context, sdk *larksdk.Client, token string, tokenType larksdk.AccessTokenType, documentID string, vars map[string]string) (int, []string, error) {
+	blocks, err := listDocxBlocks(ctx, sdk, token, tokenType, documentID)
+	if err != nil {
+		return 0, nil, err
+	}
+	replaced := 0
+	unresolved := map[string]bool{}
+	updates := make([]*larkdocx.UpdateBlockRequest, 0)
+	for _, block := range blocks {
+		changed := false
+		for _, text := range docxBlockTextFields(block) {
+			for _, element := range text.Elements {
+				if element == nil || element.TextRun == nil || element.TextRun.Content == nil {
+					continue
+				}
+				content := *element.TextRun.Content
+				if !strings.Contains(content, "{{") {
+					continue
+				}
+				next := docsTemplatePlaceholder.ReplaceAllStringFunc(content, func(match string) string {
+					key := docsTemplatePlaceholder.FindStringSubmatch(match)[1]
+					value, ok := vars[key]
+					if !ok {
+						unresolved[key] = true
+						return match
+					}
+					replaced++
+					return value
+				})
+				if next != content {
+					element.TextRun.Content = &next
+					changed = true
+				}
+			}
+			if changed {
+				blockID := docxBlockID(block)
+				updates = append(updates, &larkdocx.UpdateBlockRequest{
+					BlockId:            &blockID,
+					UpdateTextElements: &larkdocx.UpdateTextElementsRequest{Elements: text.Elements},
+				})
+				break
+			}
+		}
+	}
+	for start := 0; start < len(updates); start += docxBatchUpdateMaxRequests {
+		end := min(start+docxBatchUpdateMaxRequests, len(updates))
+		if _, err := sdk.BatchUpdateDocxBlocks(ctx, token, tokenType, documentID, updates[start:end], -1, "", ""); err != nil {
+			return replaced, nil, err
+		}
+	}
+	missing := make([]string, 0, len(unresolved))
+	for key := range unresolved {
+		missing = append(missing, key)
+	}
+	sort.Strings(missing)
+	return replaced, missing, nil
+}
+
+// createDocsWikiNode creates an empty docx page under a Wiki node.
+func createDocsWikiNode(ctx context.Context, state *appState, token string, tokenType tokenType, title, parentToken string) (larksdk.DocxDocument, string, error) {
+	parent, err := getWikiNode(ctx, state.SDK, token, tokenType, parentToken)
+	if err != nil {
+		return larksdk.DocxDocument{}, "", err
+	}
+	req := larksdk.CreateWikiNodeRequest{
+		SpaceID:         parent.SpaceID,
+		ObjType:         "docx",
+		ParentNodeToken: parent.NodeToken,
+		NodeType:        "origin",
+		Title:           title,
+	}
+	var node larksdk.WikiNode
+	switch tokenType {
+	case tokenTypeTenant:
+		node, err = state.SDK.CreateWikiNodeV2(ctx, token, req)
+	case tokenTypeUser:
+		node, err = state.SDK.CreateWikiNodeV2WithUserToken(ctx, token, req)
+	default:
+		err = fmt.Errorf("unsupported token type %s", tokenType)
+	}
+	if err != nil {
+		return larksdk.DocxDocument{}, "", err
+	}
+	return larksdk.DocxDocument{DocumentID: node.ObjToken, Title: node.Title}, node.NodeToken, nil
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDocsCreateFromTemplateDocument(t *testing.T) {
+	var updates []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/drive/v1/files/tpl1/copy":
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body["name"] != "Incident 42" || body["type"] != "docx" || body["folder_token"] != "fld1" {
+				t.Fatalf("unexpected copy body: %v", body)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"file": map[string]any{
+				"token": "doc2", "name": "Incident 42", "type": "docx", "url": "https://example.feishu.cn/docx/doc2",
+			}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/docx/v1/documents/doc2/blocks":
+			run := func(content string) map[string]any {
+				return map[string]any{"text_run": map[string]any{"content": content}}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+				{"block_id": "doc2", "block_type": 1, "page": map[string]any{"elements": []map[string]any{run("Incident 42")}}},
+				{"block_id": "b1", "parent_id": "doc2", "block_type": 2, "text": map[string]any{"elements": []map[string]any{
+					run("Owner: {{owner}}, severity "), run("{{ .severity }}"), run(" on {{team}}"),
+				}}},
+				{"block_id": "b2", "parent_id": "doc2", "block_type": 2, "text": map[string]any{"elements": []map[string]any{run("Timeline")}}},
+			}}})
+		case r.Method == http.MethodPatch && r.URL.Path == "/open-apis/docx/v1/documents/doc2/blocks/batch_update":
+			var body struct {
+				Requests []struct {
+					BlockID            string `json:"block_id"`
+					UpdateTextElements struct {
+						Elements []struct {
+							TextRun struct {
+								Content string `json:"content"`
+							} `json:"text_run"`
+						} `json:"elements"`
+					} `json:"update_text_elements"`
+				} `json:"requests"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			for _, req := range body.Requests {
+				var parts []string
+				for _, el := range req.UpdateTextElements.Elements {
+					parts = append(parts, el.TextRun.Content)
+				}
+				updates = append(updates, req.BlockID+":"+strings.Join(parts, ""))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"document_revision_id": 3}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newDocsCmd(state)
+	cmd.SetArgs([]string{"create", "Incident 42", "--from-template", "https://example.feishu.cn/docx/tpl1", "--folder-id", "fld1", "--var", "owner=Ada", "--var", "severity=P1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("docs create error: %v", err)
+	}
+	if len(updates) != 1 || updates[0] != "b1:Owner: Ada, severity P1 on {{team}}" {
+		t.Fatalf("unexpected updates: %q", updates)
+	}
+	var payload struct {
+		Document struct {
+			DocumentID string `json:"document_id"`
+		} `json:"document"`
+		Template docsTemplateResult `json:"template"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if payload.Document.DocumentID != "doc2" || payload.Template.Replaced != 2 || strings.Join(payload.Template.Unresolved, ",") != "team" {
+		t.Fatalf("unexpected payload: %s", buf.String())
+	}
+}
+
+func TestDocsCreateFromTemplateDocumentUnderWikiNode(t *testing.T) {
+	prevInterval := docsWikiMovePollInterval
+	docsWikiMovePollInterval = 0
+	t.Cleanup(func() {
+		docsWikiMovePollInterval = prevInterval
+	})
+	var requests []string
+	polls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/drive/v1/files/tpl1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"file": map[string]any{"token": "tpl1", "parent_token": "fld1"}}})
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/drive/v1/files/tpl1/copy":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"file": map[string]any{
+				"token": "doc2", "name": "Incident 42", "type": "docx", "url": "https://example.feishu.cn/docx/doc2",
+			}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/wiki/v2/spaces/get_node":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"node": map[string]any{
+				"space_id": "sp1", "node_token": "wn1", "obj_token": "d1", "obj_type": "docx", "title": "Incidents",
+			}}})
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/wiki/v2/spaces/sp1/nodes/move_docs_to_wiki":
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			if body["obj_token"] != "doc2" || body["obj_type"] != "docx" || body["parent_wiki_token"] != "wn1" {
+				t.Fatalf("unexpected move body: %v", body)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"task_id": "task1"}})
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/wiki/v2/tasks/task1":
+			polls++
+			result := map[string]any{"status": -1}
+			if polls > 1 {
+				result = map[string]any{"status": 0, "node": map[string]any{"space_id": "sp1", "node_token": "wn2", "obj_token": "doc2", "obj_type": "docx"}}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"task": map[string]any{"task_id": "task1", "move_result": []map[string]any{result}}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/docx/v1/documents/doc2/blocks":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+				{"block_id": "doc2", "block_type": 1, "page": map[string]any{"elements": []map[string]any{}}},
+			}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/drive/v1/files/doc2":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"file": map[string]any{"token": "doc2", "url": "https://example.feishu.cn/wiki/wn2"}}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newDocsCmd(state)
+	cmd.SetArgs([]string{"create", "Incident 42", "--from-template", "tpl1", "--wiki-node", "wn1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("docs create error: %v", err)
+	}
+	want := []string{
+		"GET /open-apis/drive/v1/files/tpl1",
+		"POST /open-apis/drive/v1/files/tpl1/copy",
+		"GET /open-apis/wiki/v2/spaces/get_node",
+		"POST /open-apis/wiki/v2/spaces/sp1/nodes/move_docs_to_wiki",
+		"GET /open-apis/wiki/v2/tasks/task1",
+		"GET /open-apis/wiki/v2/tasks/task1",
+		"GET /open-apis/docx/v1/documents/doc2/blocks",
+		"GET /open-apis/drive/v1/files/doc2",
+	}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	var payload struct {
+		Document struct {
+			DocumentID string `json:"document_id"`
+		} `json:"document"`
+		Template docsTemplateResult `json:"template"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if payload.Document.DocumentID != "doc2" || payload.Template.NodeToken != "wn2" {
+		t.Fatalf("unexpected payload: %s", buf.String())
+	}
+}
+
+func TestDocsCreateFromWikiTemplateRejectsNonDocxNode(t *testing.T) {
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		requests = append(requests, r.Method+" "+r.URL.Path+"?"+r.URL.Query().Get("token"))
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/wiki/v2/spaces/get_node" && r.URL.Query().Get("token") == "wn1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"node": map[string]any{
+				"space_id": "sp1", "node_token": "wn1", "obj_token": "d1", "obj_type": "docx", "title": "Incidents",
+			}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/wiki/v2/spaces/get_node" && r.URL.Query().Get("token") == "wtpl":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"node": map[string]any{
+				"space_id": "sp1", "node_token": "wtpl", "obj_token": "sht1", "obj_type": "sheet", "title": "Tracker",
+			}}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, _ := newTestState(t, handler, true)
+
+	cmd := newDocsCmd(state)
+	cmd.SetArgs([]string{"create", "Incident 42", "--from-template", "https://example.feishu.cn/wiki/wtpl", "--wiki-node", "wn1"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "template wiki page is a sheet, not a docx document") {
+		t.Fatalf("expected non-docx template error, got %v", err)
+	}
+	want := []string{
+		"GET /open-apis/wiki/v2/spaces/get_node?wn1",
+		"GET /open-apis/wiki/v2/spaces/get_node?wtpl",
+	}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+}
+
+func TestDocsCreateFromMarkdownTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "review.md")
+	if err := os.WriteFile(path, []byte("# {{title}}\n\nOwner: {{.owner}}\n"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	var converted string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/docx/v1/documents":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"document": map[string]any{
+				"document_id": "doc3", "title": "Review", "url": "https://example.feishu.cn/docx/doc3",
+			}}})
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/docx/v1/documents/blocks/convert":
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			converted, _ = body["content"].(string)
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"first_level_block_ids": []string{"tmp1"},
+				"blocks":                []map[string]any{{"block_id": "tmp1", "block_type": 2, "text": map[string]any{"elements": []map[string]any{}}}},
+			}})
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/docx/v1/documents/doc3/blocks/doc3/children":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"items": []map[string]any{}, "has_more": false}})
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/docx/v1/documents/doc3/blocks/doc3/descendant":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"document_revision_id": 2}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newDocsCmd(state)
+	cmd.SetArgs([]string{"create", "Review", "--from-template", path, "--var", "owner=Ada"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("docs create error: %v", err)
+	}
+	if converted != "# Review\n\nOwner: Ada\n" {
+		t.Fatalf("unexpected rendered content: %q", converted)
+	}
+	if !strings.Contains(buf.String(), `"source": "markdown"`) {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestDocsCreateTemplateValidation(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s", r.URL.Path)
+	})
+	path := filepath.Join(t.TempDir(), "t.md")
+	if err := os.WriteFile(path, []byte("{{.missing}}"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	cases := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"create", "x", "--var", "a=b"}, "--var requires --from-template"},
+		{[]string{"create", "x", "--from-template", "tpl1", "--var", "bad-key=1"}, "invalid --var"},
+		{[]string{"create", "x", "--from-template", "tpl1", "--var", "len=3"}, "template builtin"},
+		{[]string{"create", "x", "--folder-id", "f", "--wiki-node", "n"}, "mutually exclusive"},
+		{[]string{"create", "x", "--from-template", path}, "missing"},
+	}
+	for _, tc := range cases {
+		state, _ := newTestState(t, handler, true)
+		cmd := newDocsCmd(state)
+		cmd.SetArgs(tc.args)
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%v: expected %q error, got %v", tc.args, tc.want, err)
+		}
+	}
+}
@@ -12,6 +12,20 @@ import (
 type docxBlockIndex struct {
 	blocks map[string]*larkdocx.Block
 	order  []string
+	opts   docxMarkdownOptions
+}
+
+// docxMarkdownOptions enables the archive-quality rendering used by
+// `docs export --format md`. The zero value matches `docs get --format md`.
+type docxMarkdownOptions struct {
+	// Rich renders callouts as admonitions, equation blocks as $$ fences and
+	// embedded sheets/bitables as reference comments instead of dropping them.
+	Rich bool
+	// Assets maps image/file tokens to the relative links written in place of
+	// the bare token.
+	Assets map[string]string
+	// Mentions maps user open_ids to display names.
+	Mentions map[string]string
 }
 
 func newDocxBlockIndex(blocks []*larkdocx.Block) *docxBlockIndex {
@@ -33,10 +47,15 @@ func newDocxBlockIndex(blocks []*larkdocx.Block) *docxBlockIndex {
 }
 
 func docxBlocksMarkdown(documentID string, blocks []*larkdocx.Block) string {
+	return docxBlocksMarkdownWithOptions(documentID, blocks, docxMarkdownOptions{})
+}
+
+func docxBlocksMarkdownWithOptions(documentID string, blocks []*larkdocx.Block, opts docxMarkdownOptions) string {
 	if len(blocks) == 0 {
 		return ""
 	}
 	idx := newDocxBlockIndex(blocks)
+	idx.opts = opts
 	start, recursive := idx.startIDs(documentID)
 	lines := make([]string, 0, len(blocks))
 	if recursive {
@@ -91,6 +110,14 @@ func (idx *docxBlockIndex) renderMarkdown(id string, lines *[]string, visited ma
 	if block == nil {
 		return
 	}
+	if idx.opts.Rich && block.Callout != nil {
+		children := make([]string, 0, len(block.Children))
+		for _, childID := range block.Children {
+			idx.renderMarkdown(childID, &children, visited)
+		}
+		*lines = append(*lines, docxCalloutMarkdown(block.Callout, children))
+		return
+	}
 	md, skipChildren := docxBlockMarkdown(idx, block)
 	if md != "" {
 		*lines = append(*lines, strings.TrimRight(md, "\n"))
@@ -109,43 +136,49 @@ func docxBlockMarkdown(idx *docxBlockIndex, block *larkdocx.Block) (string, bool
 	}
 	switch {
 	case block.Heading1 != nil:
-		return docxHeadingMarkdown(1, block.Heading1), true
+		return docxHeadingMarkdown(idx, 1, block.Heading1), true
 	case block.Heading2 != nil:
-		return docxHeadingMarkdown(2, block.Heading2), true
+		return docxHeadingMarkdown(idx, 2, block.Heading2), true
 	case block.Heading3 != nil:
-		return docxHeadingMarkdown(3, block.Heading3), true
+		return docxHeadingMarkdown(idx, 3, block.Heading3), true
 	case block.Heading4 != nil:
-		return docxHeadingMarkdown(4, block.Heading4), true
+		return docxHeadingMarkdown(idx, 4, block.Heading4), true
 	case block.Heading5 != nil:
-		return docxHeadingMarkdown(5, block.Heading5), true
+		return docxHeadingMarkdown(idx, 5, block.Heading5), true
 	case block.Heading6 != nil:
-		return docxHeadingMarkdown(6, block.Heading6), true
+		return docxHeadingMarkdown(idx, 6, block.Heading6), true
 	case block.Heading7 != nil:
-		return docxHeadingMarkdown(7, block.Heading7), true
+		return docxHeadingMarkdown(idx, 7, block.Heading7), true
 	case block.Heading8 != nil:
-		return docxHeadingMarkdown(8, block.Heading8), true
+		return docxHeadingMarkdown(idx, 8, block.Heading8), true
 	case block.Heading9 != nil:
-		return docxHeadingMarkdown(9, block.Heading9), true
+		return docxHeadingMarkdown(idx, 9, block.Heading9), true
 	case block.Text != nil:
-		return strings.TrimSpace(docxTextMarkdown(block.Text)), true
+		return strings.TrimSpace(docxTextMarkdown(idx, block.Text)), true
 	case block.Bullet != nil:
-		return fmt.Sprintf("%s- %s", docxIndentPrefix(block.Bullet), docxTextMarkdown(block.Bullet)), true
+		return fmt.Sprintf("%s- %s", docxIndentPrefix(block.Bullet), docxTextMarkdown(idx, block.Bullet)), true
 	case block.Ordered != nil:
-		return fmt.Sprintf("%s%s %s", docxIndentPrefix(block.Ordered), docxOrderedMarker(block.Ordered), docxTextMarkdown(block.Ordered)), true
+		return fmt.Sprintf("%s%s %s", docxIndentPrefix(block.Ordered), docxOrderedMarker(block.Ordered), docxTextMarkdown(idx, block.Ordered)), true
 	case block.Todo != nil:
-		return fmt.Sprintf("%s%s %s", docxIndentPrefix(block.Todo), docxTodoMarker(block.Todo), docxTextMarkdown(block.Todo)), true
+		return fmt.Sprintf("%s%s %s", docxIndentPrefix(block.Todo), docxTodoMarker(block.Todo), docxTextMarkdown(idx, block.Todo)), true
 	case block.Quote != nil:
-		return docxQuoteMarkdown(block.Quote), true
+		return docxQuoteMarkdown(idx, block.Quote), true
 	case block.Code != nil:
-		return docxCodeMarkdown(block.Code), true
+		return docxCodeMarkdown(idx, block.Code), true
 	case block.Divider != nil:
 		return "---", true
 	case block.Image != nil:
-		return docxImageMarkdown(block.Image), true
+		return docxImageMarkdown(idx, block.Image), true
 	case block.File != nil:
-		return docxFileMarkdown(block.File), true
+		return docxFileMarkdown(idx, block.File), true
 	case block.Table != nil:
 		return docxTableMarkdown(idx, block.Table), true
+	case idx.opts.Rich && block.Equation != nil:
+		return docxEquationBlockMarkdown(idx, block.Equation), true
+	case idx.opts.Rich && block.Sheet != nil:
+		return docxEmbedMarkdown("sheet", block.Sheet.Token), true
+	case idx.opts.Rich && block.Bitable != nil:
+		return docxEmbedMarkdown("bitable", block.Bitable.Token), true
 	case block.TableCell != nil:
 		return "", false
 	case block.Page != nil || block.Grid != nil || block.GridColumn != nil || block.QuoteContainer != nil || block.Callout != nil || block.View != nil:
@@ -157,18 +190,18 @@ func docxBlockMarkdown(idx *docxBlockIndex, block *larkdocx.Block) (string, bool
 	return "", false
 }
 
-func docxHeadingMarkdown(level int, text *larkdocx.Text) string {
+func docxHeadingMarkdown(idx *docxBlockIndex, level int, text *larkdocx.Text) string {
 	if level < 1 {
 		level = 1
 	}
 	if level > 6 {
 		level = 6
 	}
-	return fmt.Sprintf("%s %s", strings.Repeat("#", level), docxTextMarkdown(text))
+	return fmt.Sprintf("%s %s", strings.Repeat("#", level), docxTextMarkdown(idx, text))
 }
 
-func docxQuoteMarkdown(text *larkdocx.Text) string {
-	content := docxTextMarkdown(text)
+func docxQuoteMarkdown(idx *docxBlockIndex, text *larkdocx.Text) string {
+	content := docxTextMarkdown(idx, text)
 	if content == "" {
 		return ""
 	}
@@ -179,25 +212,25 @@ func docxQuoteMarkdown(text *larkdocx.Text) string {
 	return strings.Join(parts, "\n")
 }
 
-func docxCodeMarkdown(text *larkdocx.Text) string {
-	content := docxTextMarkdown(text)
+func docxCodeMarkdown(idx *docxBlockIndex, text *larkdocx.Text) string {
+	content := docxTextMarkdown(idx, text)
 	if content == "" {
 		return "```\n```"
 	}
 	return "```\n" + content + "\n```"
 }
 
-func docxImageMarkdown(image *larkdocx.Image) string {
+func docxImageMarkdown(idx *docxBlockIndex, image *larkdocx.Image) string {
 	if image == nil {
 		return ""
 	}
 	if image.Token != nil && *image.Token != "" {
-		return fmt.Sprintf("![image](%s)", *image.Token)
+		return fmt.Sprintf("![image](%s)", idx.assetLink(*image.Token))
 	}
 	return "![image]"
 }
 
-func docxFileMarkdown(file *larkdocx.File) string {
+func docxFileMarkdown(idx *docxBlockIndex, file *larkdocx.File) string {
 	if file == nil {
 		return ""
 	}
@@ -211,9 +244,73 @@ func docxFileMarkdown(file *larkdocx.File) string {
 	if name == "" {
 		name = "file"
 	}
+	if file.Token != nil {
+		if link, ok := idx.opts.Assets[*file.Token]; ok {
+			return fmt.Sprintf("[%s](%s)", name, link)
+		}
+	}
 	return fmt.Sprintf("[%s]", name)
 }
 
+var docxCalloutKinds = map[string]string{
+	"bulb":                   "TIP",
+	"star":                   "TIP",
+	"warning":                "WARNING",
+	"exclamation":            "IMPORTANT",
+	"heavy_exclamation_mark": "IMPORTANT",
+	"pushpin":                "IMPORTANT",
+	"x":                      "CAUTION",
+	"no_entry":               "CAUTION",
+	"red_circle":             "CAUTION",
+}
+
+// docxCalloutMarkdown renders a callout as a GitHub-style admonition. The
+// emoji is kept in a trailing comment so the block can be restored.
+func docxCalloutMarkdown(callout *larkdocx.Callout, children []string) string {
+	emoji := strings.TrimSpace(valueOrEmpty(callout.EmojiId))
+	kind := docxCalloutKinds[emoji]
+	if kind == "" {
+		kind = "NOTE"
+	}
+	header := "> [!" + kind + "]"
+	if emoji != "" {
+		header += " <!-- emoji: " + emoji + " -->"
+	}
+	lines := []string{header}
+	for _, child := range children {
+		for _, line := range strings.Split(child, "\n") {
+			lines = append(lines, strings.TrimRight("> "+line, " "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func docxEquationBlockMarkdown(idx *docxBlockIndex, text *larkdocx.Text) string {
+	content := strings.TrimSpace(docxTextMarkdown(idx, text))
+	if content == "" {
+		return ""
+	}
+	content = strings.TrimSuffix(strings.TrimPrefix(content, "$"), "$")
+	return "$$\n" + content + "\n$$"
+}
+
+func docxEmbedMarkdown(kind string, token *string) string {
+	value := strings.TrimSpace(valueOrEmpty(token))
+	if value == "" {
+		return "<!-- " + kind + " -->"
+	}
+	return "<!-- " + kind + ": " + value + " -->"
+}
+
+func (idx *docxBlockIndex) assetLink(token string) string {
+	if idx != nil {
+		if link, ok := idx.opts.Assets[token]; ok {
+			return link
+		}
+	}
+	return token
+}
+
 func docxTableMarkdown(idx *docxBlockIndex, table *larkdocx.Table) string {
 	if table == nil || len(table.Cells) == 0 {
 		return ""
@@ -292,7 +389,7 @@ func docxTableCellText(idx *docxBlockIndex, cellID string) string {
 	return strings.Join(parts, "<br>")
 }
 
-func docxTextMarkdown(text *larkdocx.Text) string {
+func docxTextMarkdown(idx *docxBlockIndex, text *larkdocx.Text) string {
 	if text == nil || len(text.Elements) == 0 {
 		return ""
 	}
@@ -301,12 +398,12 @@ func docxTextMarkdown(text *larkdocx.Text) string {
 		if el == nil {
 			continue
 		}
-		b.WriteString(docxTextElementMarkdown(el))
+		b.WriteString(docxTextElementMarkdown(idx, el))
 	}
 	return b.String()
 }
 
-func docxTextElementMarkdown(el *larkdocx.TextElement) string {
+func docxTextElementMarkdown(idx *docxBlockIndex, el *larkdocx.TextElement) string {
 	switch {
 	case el.TextRun != nil:
 		return docxApplyInlineStyle(valueOrEmpty(el.TextRun.Content), el.TextRun.TextElementStyle)
@@ -314,6 +411,9 @@ func docxTextElementMarkdown(el *larkdocx.TextElement) string {
 		content := "@user"
 		if el.MentionUser.UserId != nil && *el.MentionUser.UserId != "" {
 			content = "@" + *el.MentionUser.UserId
+			if name := idx.opts.Mentions[*el.MentionUser.UserId]; name != "" {
+				content = "@" + name
+			}
 		}
 		return docxApplyInlineStyle(content, el.MentionUser.TextElementStyle)
 	case el.MentionDoc != nil:
@@ -335,6 +435,9 @@ func docxTextElementMarkdown(el *larkdocx.TextElement) string {
 		return "[reminder]"
 	case el.File != nil:
 		if el.File.FileToken != nil && *el.File.FileToken != "" {
+			if link, ok := idx.opts.Assets[*el.File.FileToken]; ok {
+				return fmt.Sprintf("[file](%s)", link)
+			}
 			return "[file:" + *el.File.FileToken + "]"
 		}
 		return "[file]"
@@ -342,6 +445,9 @@ func docxTextElementMarkdown(el *larkdocx.TextElement) string {
 		return "[block]"
 	case el.Equation != nil:
 		if el.Equation.Content != nil && *el.Equation.Content != "" {
+			if idx.opts.Rich {
+				return "$" + strings.TrimSpace(*el.Equation.Content) + "$"
+			}
 			return "$" + *el.Equation.Content + "$"
 		}
 		return "[equation]"
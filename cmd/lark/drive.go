@@ -33,6 +33,7 @@ func newDriveCmd(state *appState) *cobra.Command {
 	cmd.AddCommand(newDriveExportCmd(state))
 	cmd.AddCommand(newDriveDownloadCmd(state))
 	cmd.AddCommand(newDriveUploadCmd(state))
+	cmd.AddCommand(newDriveSyncCmd(state))
 	cmd.AddCommand(newDriveURLsCmd(state))
 	cmd.AddCommand(newDriveShareCmd(state))
 	cmd.AddCommand(newDrivePermissionsCmd(state))
@@ -41,23 +42,20 @@ func newDriveCmd(state *appState) *cobra.Command {
 
 func newDriveListCmd(state *appState) *cobra.Command {
 	var folderID string
-	var limit int
+	var paging listPaging
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List files in a Drive folder",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				return flagUsage(cmd, "limit must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			folderID = strings.TrimSpace(folderID)
 			if strings.EqualFold(folderID, "root") {
 				folderID = "0"
 			}
-			if strings.EqualFold(folderID, "root") {
-				folderID = "0"
-			}
 			ctx := cmd.Context()
 			token, tokenTypeValue, err := resolveAccessToken(ctx, state, tokenTypesTenantOrUser, nil)
 			if err != nil {
@@ -66,46 +64,28 @@ func newDriveListCmd(state *appState) *cobra.Command {
 			if _, err := requireSDK(state); err != nil {
 				return err
 			}
-			files := make([]larksdk.DriveFile, 0, limit)
-			pageToken := ""
-			nextPageToken := ""
-			hasMore := false
-			remaining := limit
-			for {
-				pageSize := remaining
-				if pageSize > maxDrivePageSize {
-					pageSize = maxDrivePageSize
-				}
+			files := newListCollector[larksdk.DriveFile](state, paging.itemLimit())
+			pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.DriveFile], error) {
 				result, err := state.SDK.ListDriveFiles(ctx, token, larksdk.AccessTokenType(tokenTypeValue), larksdk.ListDriveFilesRequest{
 					FolderToken: folderID,
-					PageSize:    pageSize,
+					PageSize:    files.pageSize(maxDrivePageSize),
 					PageToken:   pageToken,
 				})
-				if err != nil {
-					return err
-				}
-				nextPageToken = result.PageToken
-				hasMore = result.HasMore
-				files = append(files, result.Files...)
-				if len(files) >= limit || !result.HasMore {
-					break
-				}
-				remaining = limit - len(files)
-				pageToken = result.PageToken
-				if pageToken == "" {
-					break
-				}
+				return larksdk.Page[larksdk.DriveFile]{Items: result.Files, PageToken: result.PageToken, HasMore: result.HasMore}, err
+			})
+			if err := drainPages(ctx, state, files, pager, paging.pageLimit()); err != nil {
+				return err
 			}
-			if len(files) > limit {
-				files = files[:limit]
+			if files.streaming {
+				return nil
 			}
 			payload := map[string]any{
-				"files":           files,
-				"has_more":        hasMore,
-				"next_page_token": nextPageToken,
+				"files":           files.items,
+				"has_more":        pager.HasMore(),
+				"next_page_token": pager.PageToken(),
 			}
-			lines := make([]string, 0, len(files))
-			for _, file := range files {
+			lines := make([]string, 0, len(files.items))
+			for _, file := range files.items {
 				lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s", file.Token, file.Name, file.FileType, file.URL))
 			}
 			text := tableText([]string{"token", "name", "type", "url"}, lines, "no files found")
@@ -114,7 +94,8 @@ func newDriveListCmd(state *appState) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&folderID, "folder-id", "", "Drive folder token (default: root)")
-	cmd.Flags().IntVar(&limit, "limit", 50, "max number of files to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 50, "max number of files to return")
+	addPagingFlags(cmd, &paging)
 	return cmd
 }
 
@@ -122,8 +103,7 @@ func newDriveSearchCmd(state *appState) *cobra.Command {
 	var query string
 	var fileTypes []string
 	var folderID string
-	var limit int
-	var pages int
+	var paging listPaging
 
 	cmd := &cobra.Command{
 		Use:   "search <query>",
@@ -140,11 +120,8 @@ func newDriveSearchCmd(state *appState) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				return flagUsage(cmd, "limit must be greater than 0")
-			}
-			if pages <= 0 {
-				return flagUsage(cmd, "pages must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			ctx := cmd.Context()
 			token, err := resolveDriveSearchToken(ctx, state)
@@ -156,15 +133,19 @@ func newDriveSearchCmd(state *appState) *cobra.Command {
 			}
 
 			folderID = strings.TrimSpace(folderID)
-			var files []larksdk.DriveFile
+			collected := newListCollector[larksdk.DriveFile](state, paging.itemLimit())
 			if folderID != "" {
-				files, err = searchDriveFilesInFolder(ctx, state, token, query, fileTypes, folderID, limit, pages)
+				err = searchDriveFilesInFolder(ctx, state, token, query, fileTypes, folderID, collected, paging.pageLimit())
 			} else {
-				files, err = docsSearchDriveFiles(ctx, state, token, "drive", query, fileTypes, limit, pages)
+				err = docsSearchDriveFiles(ctx, state, token, "drive", query, fileTypes, collected, paging.pageLimit())
 			}
 			if err != nil {
 				return err
 			}
+			if collected.streaming {
+				return nil
+			}
+			files := collected.items
 
 			payload := map[string]any{"files": files}
 			lines := make([]string, 0, len(files))
@@ -179,8 +160,9 @@ func newDriveSearchCmd(state *appState) *cobra.Command {
 
 	cmd.Flags().StringSliceVar(&fileTypes, "type", nil, "filter by doc type (docx|doc|sheet|slides|bitable|mindnote|file); repeatable or comma-separated")
 	cmd.Flags().StringVar(&folderID, "folder-id", "", "Drive folder token to scope the search")
-	cmd.Flags().IntVar(&limit, "limit", 50, "max number of files to return")
-	cmd.Flags().IntVar(&pages, "pages", 1, "max number of pages to fetch")
+	cmd.Flags().IntVar(&paging.limit, "limit", 50, "max number of files to return")
+	cmd.Flags().IntVar(&paging.pages, "pages", 1, "max number of pages to fetch")
+	addPagingFlags(cmd, &paging)
 	return cmd
 }
 
@@ -230,10 +212,17 @@ func newDriveUploadCmd(state *appState) *cobra.Command {
 	var filePath string
 	var folderToken string
 	var uploadName string
+	var resume bool
+	var concurrency int
 
 	cmd := &cobra.Command{
 		Use:   "upload <path>",
 		Short: "Upload a local file to Drive",
+		Long: `Upload a local file to a Drive folder.
+
+Files larger than 20MB are uploaded in parts (upload_prepare -> upload_part ->
+upload_finish) with --concurrency parallel workers. Progress is saved after
+every part; rerun with --resume to continue an interrupted upload.`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
 				return argsUsageError(cmd, err)
@@ -257,6 +246,9 @@ func newDriveUploadCmd(state *appState) *cobra.Command {
 			if info.IsDir() {
 				return fmt.Errorf("file path is a directory: %s", filePath)
 			}
+			if concurrency <= 0 || concurrency > maxDriveUploadConcurrency {
+				return flagUsage(cmd, fmt.Sprintf("concurrency must be between 1 and %d", maxDriveUploadConcurrency))
+			}
 			if uploadName == "" {
 				uploadName = filepath.Base(filePath)
 			}
@@ -276,11 +268,14 @@ func newDriveUploadCmd(state *appState) *cobra.Command {
 				// Lark/Feishu Drive root folder token is "0".
 				folderToken = "0"
 			}
-			result, err := state.SDK.UploadDriveFile(cmd.Context(), token, larksdk.AccessTokenType(tokenTypeValue), larksdk.UploadDriveFileRequest{
-				FileName:    uploadName,
-				FolderToken: folderToken,
-				Size:        info.Size(),
-				File:        file,
+			result, err := uploadLocalDriveFile(cmd.Context(), state, token, larksdk.AccessTokenType(tokenTypeValue), localDriveUpload{
+				file:        file,
+				info:        info,
+				path:        filePath,
+				name:        uploadName,
+				folderToken: folderToken,
+				concurrency: concurrency,
+				resume:      resume,
 			})
 			if err != nil {
 				return err
@@ -317,6 +312,8 @@ func newDriveUploadCmd(state *appState) *cobra.Command {
 	cmd.Flags().StringVar(&folderToken, "folder-token", "", "Drive folder token (deprecated; use --folder-id)")
 	_ = cmd.Flags().MarkDeprecated("folder-token", "use --folder-id")
 	cmd.Flags().StringVar(&uploadName, "name", "", "override the uploaded file name")
+	cmd.Flags().BoolVar(&resume, "resume", false, "continue an interrupted multipart upload of the same file")
+	cmd.Flags().IntVar(&concurrency, "concurrency", defaultDriveUploadConcurrency, "parallel part uploads for files larger than 20MB")
 	return cmd
 }
 
@@ -8,61 +8,30 @@ import (
 	"lark/internal/larksdk"
 )
 
-func searchDriveFilesInFolder(ctx context.Context, state *appState, userToken, query string, fileTypes []string, folderID string, limit, pages int) ([]larksdk.DriveFile, error) {
+func searchDriveFilesInFolder(ctx context.Context, state *appState, userToken, query string, fileTypes []string, folderID string, files *listCollector[larksdk.DriveFile], maxPages int) error {
 	if state == nil {
-		return nil, errors.New("state is required")
+		return errors.New("state is required")
 	}
 	if state.SDK == nil {
-		return nil, errors.New("sdk client is required")
-	}
-	if limit <= 0 {
-		return nil, errors.New("limit must be greater than 0")
-	}
-	if pages <= 0 {
-		return nil, errors.New("pages must be greater than 0")
+		return errors.New("sdk client is required")
 	}
 	folderID = strings.TrimSpace(folderID)
 	if folderID == "" {
-		return nil, errors.New("folder id is required")
+		return errors.New("folder id is required")
 	}
 
-	files := make([]larksdk.DriveFile, 0, limit)
-	pageToken := ""
-	remaining := limit
-	pageCount := 0
-	for {
-		if pageCount >= pages {
-			break
-		}
-		pageCount++
-
-		pageSize := remaining
-		if pageSize > maxDrivePageSize {
-			pageSize = maxDrivePageSize
-		}
-
+	pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.DriveFile], error) {
 		result, err := state.SDK.SearchDriveFilesWithUserToken(ctx, userToken, larksdk.SearchDriveFilesRequest{
 			Query:       query,
 			FileTypes:   fileTypes,
 			FolderToken: folderID,
-			PageSize:    pageSize,
+			PageSize:    files.pageSize(maxDrivePageSize),
 			PageToken:   pageToken,
 		})
 		if err != nil {
-			return nil, withUserScopeHintForCommand(state, err)
-		}
-		files = append(files, result.Files...)
-		if len(files) >= limit || !result.HasMore {
-			break
-		}
-		remaining = limit - len(files)
-		pageToken = result.PageToken
-		if pageToken == "" {
-			break
+			return larksdk.Page[larksdk.DriveFile]{}, withUserScopeHintForCommand(state, err)
 		}
-	}
-	if len(files) > limit {
-		files = files[:limit]
-	}
-	return files, nil
+		return larksdk.Page[larksdk.DriveFile]{Items: result.Files, PageToken: result.PageToken, HasMore: result.HasMore}, nil
+	})
+	return drainPages(ctx, state, files, pager, maxPages)
 }
@@ -0,0 +1,677 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+// driveSyncManifestName is the local checksum manifest written at the root of
+// the synced directory. It records the state of every path after the last
+// successful sync so both sides can be diffed against a common base.
+const driveSyncManifestName = ".lark-sync.json"
+
+const (
+	driveSyncUp   = "up"
+	driveSyncDown = "down"
+	driveSyncBoth = "both"
+)
+
+const (
+	syncActionMkdirRemote  = "mkdir_remote"
+	syncActionUpload       = "upload"
+	syncActionReplace      = "replace"
+	syncActionDeleteRemote = "delete_remote"
+	syncActionMkdirLocal   = "mkdir_local"
+	syncActionDownload     = "download"
+	syncActionDeleteLocal  = "delete_local"
+	syncActionSkip         = "skip"
+)
+
+type driveSyncAction struct {
+	Action string `json:"action"`
+	Path   string `json:"path"`
+	Token  string `json:"token,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type driveSyncManifest struct {
+	FolderToken string                            `json:"folder_token"`
+	Files       map[string]driveSyncManifestEntry `json:"files"`
+}
+
+type driveSyncManifestEntry struct {
+	Size           int64  `json:"size"`
+	ModTime        int64  `json:"mod_time"`
+	SHA256         string `json:"sha256"`
+	FileToken      string `json:"file_token"`
+	RemoteModified int64  `json:"remote_modified"`
+}
+
+type syncLocalEntry struct {
+	IsDir   bool
+	Size    int64
+	ModTime int64
+	AbsPath string
+}
+
+type syncRemoteEntry struct {
+	Token    string
+	Type     string
+	Parent   string
+	Modified int64
+}
+
+func (e syncRemoteEntry) isDir() bool {
+	return e.Type == "folder"
+}
+
+func newDriveSyncCmd(state *appState) *cobra.Command {
+	var direction string
+	var deleteExtra bool
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "sync <local-dir> <folder-token>",
+		Short: "Sync a local directory with a Drive folder",
+		Long: `Sync a local directory with a Drive folder by relative path.
+
+Files are compared by size and modification time against a checksum manifest
+(` + driveSyncManifestName + `) stored in the local directory. Online documents
+(docx, sheets, bitables) are never downloaded or overwritten.
+
+- up:   upload new/changed local files and create missing folders
+- down: download new/changed Drive files and create missing directories
+- both: two-way sync; when both sides changed, the newer file wins
+
+--delete prunes paths missing on the source side (in both mode: paths deleted
+since the last sync). Use --dry-run --json to print the plan as JSON.`,
+		Example: `  lark drive sync ./docs <FOLDER_TOKEN> --dry-run --json
+  lark drive sync ./assets <FOLDER_TOKEN> --direction up --delete`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			if strings.TrimSpace(args[0]) == "" {
+				return argsUsageError(cmd, errors.New("local-dir is required"))
+			}
+			if strings.TrimSpace(args[1]) == "" {
+				return argsUsageError(cmd, errors.New("folder-token is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			direction = strings.ToLower(strings.TrimSpace(direction))
+			switch direction {
+			case driveSyncUp, driveSyncDown, driveSyncBoth:
+			default:
+				return flagUsage(cmd, "direction must be up, down, or both")
+			}
+			localDir := strings.TrimSpace(args[0])
+			folderToken, _, err := parseResourceRef(args[1])
+			if err != nil {
+				return err
+			}
+			if strings.EqualFold(folderToken, "root") {
+				folderToken = "0"
+			}
+			info, err := os.Stat(localDir)
+			switch {
+			case err == nil && !info.IsDir():
+				return fmt.Errorf("local path is not a directory: %s", localDir)
+			case errors.Is(err, os.ErrNotExist) && direction == driveSyncDown:
+				if !dryRun {
+					if err := os.MkdirAll(localDir, 0o755); err != nil {
+						return err
+					}
+				}
+			case err != nil:
+				return err
+			}
+
+			ctx := cmd.Context()
+			token, tokenTypeValue, err := resolveAccessToken(ctx, state, tokenTypesTenantOrUser, nil)
+			if err != nil {
+				return err
+			}
+			if _, err := requireSDK(state); err != nil {
+				return err
+			}
+			syncer := &driveSyncer{
+				state:       state,
+				token:       token,
+				tokenType:   larksdk.AccessTokenType(tokenTypeValue),
+				localDir:    localDir,
+				folderToken: folderToken,
+			}
+			local, err := scanSyncLocalTree(localDir)
+			if err != nil {
+				return err
+			}
+			remote, skipped, err := syncer.scanRemoteTree(ctx)
+			if err != nil {
+				return err
+			}
+			manifest, err := loadDriveSyncManifest(localDir, folderToken)
+			if err != nil {
+				return err
+			}
+			actions := append(skipped, planDriveSync(direction, deleteExtra, local, remote, manifest)...)
+
+			applied := 0
+			if !dryRun {
+				applied, err = syncer.apply(ctx, actions, local, remote, manifest)
+				if saveErr := saveDriveSyncManifest(localDir, manifest); saveErr != nil && err == nil {
+					err = saveErr
+				}
+				if err != nil {
+					return err
+				}
+			}
+
+			summary := map[string]int{}
+			for _, action := range actions {
+				summary[action.Action]++
+			}
+			payload := map[string]any{
+				"local_dir":    localDir,
+				"folder_token": folderToken,
+				"direction":    direction,
+				"dry_run":      dryRun,
+				"actions":      actions,
+				"summary":      summary,
+			}
+			if !dryRun {
+				payload["applied"] = applied
+			}
+			lines := make([]string, 0, len(actions))
+			for _, action := range actions {
+				lines = append(lines, fmt.Sprintf("%s\t%s\t%s", action.Action, action.Path, action.Reason))
+			}
+			text := tableText([]string{"action", "path", "reason"}, lines, "already in sync")
+			return state.Printer.Print(payload, text)
+		},
+	}
+	annotateAuthServices(cmd, "drive")
+
+	cmd.Flags().StringVar(&direction, "direction", driveSyncUp, "sync direction (up|down|both)")
+	cmd.Flags().BoolVar(&deleteExtra, "delete", false, "delete paths that no longer exist on the source side")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the planned actions without changing anything")
+	return cmd
+}
+
+// planDriveSync computes the ordered list of actions. Directory creation comes
+// first (parents before children), then transfers, then deletions (children
+// are covered by deleting their topmost missing parent).
+func planDriveSync(direction string, deleteExtra bool, local map[string]syncLocalEntry, remote map[string]syncRemoteEntry, manifest *driveSyncManifest) []driveSyncAction {
+	var mkdirs, transfers, deletes []driveSyncAction
+
+	paths := make(map[string]struct{}, len(local)+len(remote))
+	for p := range local {
+		paths[p] = struct{}{}
+	}
+	for p := range remote {
+		paths[p] = struct{}{}
+	}
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	pushUp := direction == driveSyncUp || direction == driveSyncBoth
+	pullDown := direction == driveSyncDown || direction == driveSyncBoth
+	deletedRemote := map[string]bool{}
+	deletedLocal := map[string]bool{}
+	underDeleted := func(p string, deleted map[string]bool) bool {
+		for dir := path.Dir(p); dir != "." && dir != "/"; dir = path.Dir(dir) {
+			if deleted[dir] {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, p := range sorted {
+		l, hasLocal := local[p]
+		r, hasRemote := remote[p]
+		_, synced := manifest.Files[p]
+
+		switch {
+		case hasLocal && !hasRemote:
+			removedRemotely := direction == driveSyncBoth && synced
+			if (direction == driveSyncDown || removedRemotely) && deleteExtra {
+				if !underDeleted(p, deletedLocal) {
+					deletedLocal[p] = true
+					deletes = append(deletes, driveSyncAction{Action: syncActionDeleteLocal, Path: p, Reason: "missing in Drive"})
+				}
+				continue
+			}
+			if !pushUp || removedRemotely {
+				continue
+			}
+			if l.IsDir {
+				mkdirs = append(mkdirs, driveSyncAction{Action: syncActionMkdirRemote, Path: p, Reason: "new local directory"})
+			} else {
+				transfers = append(transfers, driveSyncAction{Action: syncActionUpload, Path: p, Reason: "new local file"})
+			}
+
+		case hasRemote && !hasLocal:
+			removedLocally := direction == driveSyncBoth && synced
+			if (direction == driveSyncUp || removedLocally) && deleteExtra {
+				if !r.isDir() && r.Type != "file" {
+					// Online documents never have a local copy; pruning them
+					// would delete every doc in the folder.
+					transfers = append(transfers, driveSyncAction{Action: syncActionSkip, Path: p, Token: r.Token, Type: r.Type, Reason: "online document; use docs/drive export"})
+					continue
+				}
+				if !underDeleted(p, deletedRemote) {
+					deletedRemote[p] = true
+					deletes = append(deletes, driveSyncAction{Action: syncActionDeleteRemote, Path: p, Token: r.Token, Type: r.Type, Reason: "missing locally"})
+				}
+				continue
+			}
+			if !pullDown || removedLocally {
+				continue
+			}
+			switch {
+			case r.isDir():
+				mkdirs = append(mkdirs, driveSyncAction{Action: syncActionMkdirLocal, Path: p, Token: r.Token, Reason: "new Drive folder"})
+			case r.Type != "file":
+				transfers = append(transfers, driveSyncAction{Action: syncActionSkip, Path: p, Token: r.Token, Type: r.Type, Reason: "online document; use docs/drive export"})
+			default:
+				transfers = append(transfers, driveSyncAction{Action: syncActionDownload, Path: p, Token: r.Token, Reason: "new Drive file"})
+			}
+
+		default:
+			if l.IsDir || r.isDir() {
+				if l.IsDir != r.isDir() {
+					transfers = append(transfers, driveSyncAction{Action: syncActionSkip, Path: p, Token: r.Token, Type: r.Type, Reason: "file/folder type mismatch"})
+				}
+				continue
+			}
+			if r.Type != "file" {
+				transfers = append(transfers, driveSyncAction{Action: syncActionSkip, Path: p, Token: r.Token, Type: r.Type, Reason: "online document; use docs/drive export"})
+				continue
+			}
+			localChanged, remoteChanged := syncChanges(p, l, r, manifest)
+			if !localChanged && !remoteChanged {
+				continue
+			}
+			var upload bool
+			var reason string
+			switch {
+			case direction == driveSyncUp:
+				if !localChanged {
+					continue
+				}
+				upload, reason = true, "local file changed"
+			case direction == driveSyncDown:
+				if !remoteChanged {
+					continue
+				}
+				upload, reason = false, "Drive file changed"
+			case localChanged && remoteChanged:
+				upload = l.ModTime >= r.Modified
+				reason = "conflict: Drive copy is newer"
+				if upload {
+					reason = "conflict: local copy is newer"
+				}
+			default:
+				upload = localChanged
+				reason = "Drive file changed"
+				if upload {
+					reason = "local file changed"
+				}
+			}
+			if upload {
+				transfers = append(transfers, driveSyncAction{Action: syncActionReplace, Path: p, Token: r.Token, Reason: reason})
+			} else {
+				transfers = append(transfers, driveSyncAction{Action: syncActionDownload, Path: p, Token: r.Token, Reason: reason})
+			}
+		}
+	}
+
+	actions := make([]driveSyncAction, 0, len(mkdirs)+len(transfers)+len(deletes))
+	actions = append(actions, mkdirs...)
+	actions = append(actions, transfers...)
+	actions = append(actions, deletes...)
+	return actions
+}
+
+// syncChanges reports which sides changed since the last sync. Without a
+// manifest entry the sides are compared by modification time.
+func syncChanges(p string, l syncLocalEntry, r syncRemoteEntry, manifest *driveSyncManifest) (bool, bool) {
+	entry, ok := manifest.Files[p]
+	if !ok {
+		return l.ModTime > r.Modified, r.Modified > l.ModTime
+	}
+	localChanged := false
+	if entry.Size != l.Size || entry.ModTime != l.ModTime {
+		sum, err := fileSHA256(l.AbsPath)
+		localChanged = err != nil || sum != entry.SHA256
+	}
+	remoteChanged := entry.FileToken != r.Token || r.Modified > entry.RemoteModified
+	return localChanged, remoteChanged
+}
+
+type driveSyncer struct {
+	state       *appState
+	token       string
+	tokenType   larksdk.AccessTokenType
+	localDir    string
+	folderToken string
+}
+
+// scanRemoteTree lists the Drive folder recursively by relative path. Entries
+// whose names cannot map to a local path, and later entries that repeat a
+// name already seen in the same folder, are returned as skip actions.
+func (s *driveSyncer) scanRemoteTree(ctx context.Context) (map[string]syncRemoteEntry, []driveSyncAction, error) {
+	entries := map[string]syncRemoteEntry{}
+	var skipped []driveSyncAction
+	var walk func(folderToken, prefix string) error
+	walk = func(folderToken, prefix string) error {
+		pageToken := ""
+		for {
+			result, err := s.state.SDK.ListDriveFiles(ctx, s.token, s.tokenType, larksdk.ListDriveFilesRequest{
+				FolderToken: folderToken,
+				PageSize:    maxDrivePageSize,
+				PageToken:   pageToken,
+			})
+			if err != nil {
+				return err
+			}
+			for _, file := range result.Files {
+				name := strings.TrimSpace(file.Name)
+				if name == "" || file.FileType == "shortcut" {
+					continue
+				}
+				if !driveSyncSafeName(name) {
+					display := strings.TrimPrefix(prefix+"/"+name, "/")
+					skipped = append(skipped, driveSyncAction{Action: syncActionSkip, Path: display, Token: file.Token, Type: file.FileType, Reason: "name is not a valid local path"})
+					continue
+				}
+				rel := path.Join(prefix, name)
+				if _, ok := entries[rel]; ok {
+					skipped = append(skipped, driveSyncAction{Action: syncActionSkip, Path: rel, Token: file.Token, Type: file.FileType, Reason: "duplicate name in Drive"})
+					continue
+				}
+				modified, _ := strconv.ParseInt(file.ModifiedTime, 10, 64)
+				entries[rel] = syncRemoteEntry{Token: file.Token, Type: file.FileType, Parent: folderToken, Modified: modified}
+				if file.FileType == "folder" {
+					if err := walk(file.Token, rel); err != nil {
+						return err
+					}
+				}
+			}
+			if !result.HasMore || result.PageToken == "" {
+				return nil
+			}
+			pageToken = result.PageToken
+		}
+	}
+	if err := walk(s.folderToken, ""); err != nil {
+		return nil, nil, err
+	}
+	return entries, skipped, nil
+}
+
+// driveSyncSafeName reports whether a Drive name can be used as a single local
+// path element.
+func driveSyncSafeName(name string) bool {
+	return name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+func scanSyncLocalTree(root string) (map[string]syncLocalEntry, error) {
+	entries := map[string]syncLocalEntry{}
+	if _, err := os.Stat(root); errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == driveSyncManifestName {
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entries[rel] = syncLocalEntry{
+			IsDir:   d.IsDir(),
+			Size:    info.Size(),
+			ModTime: info.ModTime().Unix(),
+			AbsPath: p,
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// apply executes the plan in order and keeps the manifest up to date. It
+// returns the number of actions applied before any error.
+func (s *driveSyncer) apply(ctx context.Context, actions []driveSyncAction, local map[string]syncLocalEntry, remote map[string]syncRemoteEntry, manifest *driveSyncManifest) (int, error) {
+	folders := map[string]string{"": s.folderToken, ".": s.folderToken}
+	for p, entry := range remote {
+		if entry.isDir() {
+			folders[p] = entry.Token
+		}
+	}
+	applied := 0
+	for _, action := range actions {
+		if err := ctx.Err(); err != nil {
+			return applied, err
+		}
+		if s.state.Verbose {
+			fmt.Fprintf(errWriter(s.state), "%s %s\n", action.Action, action.Path)
+		}
+		parent := path.Dir(action.Path)
+		switch action.Action {
+		case syncActionMkdirRemote:
+			token, err := s.state.SDK.CreateDriveFolder(ctx, s.token, path.Base(action.Path), folders[parent])
+			if err != nil {
+				return applied, fmt.Errorf("create folder %s: %w", action.Path, err)
+			}
+			folders[action.Path] = token
+		case syncActionUpload, syncActionReplace:
+			entry, err := s.upload(ctx, action.Path, local[action.Path], folders[parent])
+			if err != nil {
+				return applied, fmt.Errorf("upload %s: %w", action.Path, err)
+			}
+			if action.Action == syncActionReplace && action.Token != "" {
+				if _, err := s.state.SDK.DeleteDriveFile(ctx, s.token, action.Token, "file"); err != nil {
+					return applied, fmt.Errorf("delete replaced %s: %w", action.Path, err)
+				}
+			}
+			manifest.Files[action.Path] = entry
+		case syncActionMkdirLocal:
+			if err := os.MkdirAll(s.localPath(action.Path), 0o755); err != nil {
+				return applied, err
+			}
+		case syncActionDownload:
+			entry, err := s.download(ctx, action.Path, remote[action.Path])
+			if err != nil {
+				return applied, fmt.Errorf("download %s: %w", action.Path, err)
+			}
+			manifest.Files[action.Path] = entry
+		case syncActionDeleteRemote:
+			if _, err := s.state.SDK.DeleteDriveFile(ctx, s.token, action.Token, action.Type); err != nil {
+				return applied, fmt.Errorf("delete %s: %w", action.Path, err)
+			}
+			forgetSyncPath(manifest, action.Path)
+		case syncActionDeleteLocal:
+			if err := os.RemoveAll(s.localPath(action.Path)); err != nil {
+				return applied, err
+			}
+			forgetSyncPath(manifest, action.Path)
+		case syncActionSkip:
+			continue
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+func (s *driveSyncer) upload(ctx context.Context, rel string, entry syncLocalEntry, folderToken string) (driveSyncManifestEntry, error) {
+	file, err := os.Open(entry.AbsPath)
+	if err != nil {
+		return driveSyncManifestEntry{}, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return driveSyncManifestEntry{}, err
+	}
+	result, err := uploadLocalDriveFile(ctx, s.state, s.token, s.tokenType, localDriveUpload{
+		file:        file,
+		info:        info,
+		path:        entry.AbsPath,
+		name:        path.Base(rel),
+		folderToken: folderToken,
+	})
+	if err != nil {
+		return driveSyncManifestEntry{}, err
+	}
+	sum, err := fileSHA256(entry.AbsPath)
+	if err != nil {
+		return driveSyncManifestEntry{}, err
+	}
+	// Record Drive's own modified time so the next run does not mistake the
+	// upload for a remote change (or miss one made right after it).
+	meta, err := driveFileMetadataWithToken(ctx, s.state.SDK, tokenType(s.tokenType), s.token, result.FileToken)
+	if err != nil {
+		return driveSyncManifestEntry{}, fmt.Errorf("read uploaded file metadata: %w", err)
+	}
+	modified, _ := strconv.ParseInt(meta.ModifiedTime, 10, 64)
+	return driveSyncManifestEntry{
+		Size:           info.Size(),
+		ModTime:        info.ModTime().Unix(),
+		SHA256:         sum,
+		FileToken:      result.FileToken,
+		RemoteModified: modified,
+	}, nil
+}
+
+func (s *driveSyncer) download(ctx context.Context, rel string, entry syncRemoteEntry) (driveSyncManifestEntry, error) {
+	download, err := s.state.SDK.DownloadDriveFile(ctx, s.token, s.tokenType, entry.Token)
+	if err != nil {
+		return driveSyncManifestEntry{}, err
+	}
+	defer download.Reader.Close()
+	target := s.localPath(rel)
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return driveSyncManifestEntry{}, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".lark-sync-*")
+	if err != nil {
+		return driveSyncManifestEntry{}, err
+	}
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hasher), download.Reader)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return driveSyncManifestEntry{}, err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		_ = os.Remove(tmp.Name())
+		return driveSyncManifestEntry{}, err
+	}
+	modTime := time.Now()
+	if entry.Modified > 0 {
+		modTime = time.Unix(entry.Modified, 0)
+		_ = os.Chtimes(target, modTime, modTime)
+	}
+	return driveSyncManifestEntry{
+		Size:           size,
+		ModTime:        modTime.Unix(),
+		SHA256:         hex.EncodeToString(hasher.Sum(nil)),
+		FileToken:      entry.Token,
+		RemoteModified: entry.Modified,
+	}, nil
+}
+
+func (s *driveSyncer) localPath(rel string) string {
+	return filepath.Join(s.localDir, filepath.FromSlash(rel))
+}
+
+func forgetSyncPath(manifest *driveSyncManifest, rel string) {
+	prefix := rel + "/"
+	for p := range manifest.Files {
+		if p == rel || strings.HasPrefix(p, prefix) {
+			delete(manifest.Files, p)
+		}
+	}
+}
+
+func loadDriveSyncManifest(localDir, folderToken string) (*driveSyncManifest, error) {
+	manifest := &driveSyncManifest{FolderToken: folderToken, Files: map[string]driveSyncManifestEntry{}}
+	data, err := os.ReadFile(filepath.Join(localDir, driveSyncManifestName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return manifest, nil
+		}
+		return nil, err
+	}
+	var saved driveSyncManifest
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", driveSyncManifestName, err)
+	}
+	// A manifest recorded against another folder says nothing about this one.
+	if saved.FolderToken != folderToken || saved.Files == nil {
+		return manifest, nil
+	}
+	return &saved, nil
+}
+
+func saveDriveSyncManifest(localDir string, manifest *driveSyncManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(localDir, driveSyncManifestName), data, 0o644)
+}
+
+func fileSHA256(p string) (string, error) {
+	file, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
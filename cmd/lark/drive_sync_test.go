@@ -0,0 +1,388 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDriveSyncDryRunPrintsPlan(t *testing.T) {
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/drive/v1/files":
+			requests = append(requests, "list "+r.URL.Query().Get("folder_token"))
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"files": []map[string]any{
+					{"token": "doc1", "name": "Notes", "type": "docx", "modified_time": "100"},
+				},
+				"has_more": false,
+			}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+	localDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(localDir, "sub"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(localDir, "a.txt"), []byte("a"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(localDir, "sub", "b.txt"), []byte("b"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"sync", localDir, "fld_root", "--dry-run"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("drive sync error: %v", err)
+	}
+
+	var payload struct {
+		DryRun  bool              `json:"dry_run"`
+		Actions []driveSyncAction `json:"actions"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, buf.String())
+	}
+	got := make([]string, 0, len(payload.Actions))
+	for _, action := range payload.Actions {
+		got = append(got, action.Action+":"+action.Path)
+	}
+	want := "mkdir_remote:sub upload:a.txt upload:sub/b.txt"
+	if !payload.DryRun || strings.Join(got, " ") != want {
+		t.Fatalf("unexpected plan: %v", got)
+	}
+	if strings.Join(requests, ",") != "list fld_root" {
+		t.Fatalf("dry run should only list drive, got %v", requests)
+	}
+	if _, err := os.Stat(filepath.Join(localDir, driveSyncManifestName)); !os.IsNotExist(err) {
+		t.Fatalf("dry run should not write manifest, got %v", err)
+	}
+}
+
+func TestDriveSyncUpUploadsAndDeletes(t *testing.T) {
+	var requests []string
+	listings := map[string][]map[string]any{
+		"fld_root": {{"token": "old1", "name": "stale.txt", "type": "file", "modified_time": "100"}},
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/drive/v1/files":
+			folder := r.URL.Query().Get("folder_token")
+			requests = append(requests, "list "+folder)
+			files := listings[folder]
+			if files == nil {
+				files = []map[string]any{}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"files": files, "has_more": false}})
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/drive/v1/files/create_folder":
+			var body map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			requests = append(requests, "mkdir "+body["folder_token"]+"/"+body["name"])
+			listings[body["folder_token"]] = append(listings[body["folder_token"]], map[string]any{
+				"token": "fld_sub", "name": body["name"], "type": "folder", "modified_time": "100",
+			})
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"folder_token": "fld_sub"}})
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/drive/v1/files/upload_all":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Fatalf("parse multipart: %v", err)
+			}
+			part, err := r.MultipartForm.File["file"][0].Open()
+			if err != nil {
+				t.Fatalf("open file part: %v", err)
+			}
+			data, _ := io.ReadAll(part)
+			part.Close()
+			name, parent := r.FormValue("file_name"), r.FormValue("parent_node")
+			if string(data) != strings.TrimSuffix(name, ".txt") {
+				t.Fatalf("unexpected content for %s: %q", name, data)
+			}
+			requests = append(requests, "upload "+parent+"/"+name)
+			token := "file_" + strings.TrimSuffix(name, ".txt")
+			listings[parent] = append(listings[parent], map[string]any{
+				"token": token, "name": name, "type": "file", "modified_time": "100",
+			})
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"file_token": token}})
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/open-apis/drive/v1/files/file_"):
+			token := strings.TrimPrefix(r.URL.Path, "/open-apis/drive/v1/files/")
+			requests = append(requests, "get "+token)
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"file": map[string]any{"token": token, "type": "file", "modified_time": "100"},
+			}})
+		case r.Method == http.MethodDelete && r.URL.Path == "/open-apis/drive/v1/files/old1":
+			requests = append(requests, "delete old1")
+			listings["fld_root"] = listings["fld_root"][1:]
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, false)
+	localDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(localDir, "sub"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(localDir, "a.txt"), []byte("a"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(localDir, "sub", "b.txt"), []byte("b"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"sync", localDir, "fld_root", "--direction", "up", "--delete"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("drive sync error: %v", err)
+	}
+
+	want := "list fld_root,mkdir fld_root/sub,upload fld_root/a.txt,get file_a,upload fld_sub/b.txt,get file_b,delete old1"
+	if strings.Join(requests, ",") != want {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	if !strings.Contains(buf.String(), "delete_remote\tstale.txt\tmissing locally") {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+
+	manifest, err := loadDriveSyncManifest(localDir, "fld_root")
+	if err != nil {
+		t.Fatalf("load manifest: %v", err)
+	}
+	if len(manifest.Files) != 2 || manifest.Files["sub/b.txt"].SHA256 == "" || manifest.Files["sub/b.txt"].RemoteModified != 100 {
+		t.Fatalf("unexpected manifest: %+v", manifest.Files)
+	}
+
+	// A second run against the same tree is a no-op.
+	requests = nil
+	buf.Reset()
+	cmd = newDriveCmd(state)
+	cmd.SetArgs([]string{"sync", localDir, "fld_root", "--direction", "up"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("second drive sync error: %v", err)
+	}
+	if strings.Join(requests, ",") != "list fld_root,list fld_sub" || !strings.Contains(buf.String(), "already in sync") {
+		t.Fatalf("expected no changes, requests=%v output=%q", requests, buf.String())
+	}
+}
+
+func TestDriveSyncUpDeleteKeepsOnlineDocs(t *testing.T) {
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/drive/v1/files":
+			requests = append(requests, "list "+r.URL.Query().Get("folder_token"))
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"files": []map[string]any{
+					{"token": "doc1", "name": "Notes", "type": "docx", "modified_time": "100"},
+					{"token": "old1", "name": "stale.txt", "type": "file", "modified_time": "100"},
+				},
+				"has_more": false,
+			}})
+		case r.Method == http.MethodDelete && r.URL.Path == "/open-apis/drive/v1/files/old1":
+			requests = append(requests, "delete old1")
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, false)
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"sync", t.TempDir(), "fld_root", "--direction", "up", "--delete"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("drive sync error: %v", err)
+	}
+
+	if strings.Join(requests, ",") != "list fld_root,delete old1" {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	if !strings.Contains(buf.String(), "skip\tNotes\tonline document") {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestDriveSyncDownDownloadsAndSkipsOnlineDocs(t *testing.T) {
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/drive/v1/files":
+			folder := r.URL.Query().Get("folder_token")
+			requests = append(requests, "list "+folder)
+			var files []map[string]any
+			switch folder {
+			case "fld_root":
+				files = []map[string]any{
+					{"token": "fld_sub", "name": "sub", "type": "folder", "modified_time": "100"},
+					{"token": "doc1", "name": "Notes", "type": "docx", "modified_time": "100"},
+				}
+			case "fld_sub":
+				files = []map[string]any{
+					{"token": "f1", "name": "c.txt", "type": "file", "modified_time": "1700000000"},
+				}
+			default:
+				t.Fatalf("unexpected folder: %s", folder)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"files": files, "has_more": false}})
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/drive/v1/files/f1/download":
+			requests = append(requests, "download f1")
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write([]byte("remote"))
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, false)
+	localDir := filepath.Join(t.TempDir(), "mirror")
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"sync", localDir, "fld_root", "--direction", "down"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("drive sync error: %v", err)
+	}
+
+	if strings.Join(requests, ",") != "list fld_root,list fld_sub,download f1" {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	data, err := os.ReadFile(filepath.Join(localDir, "sub", "c.txt"))
+	if err != nil || string(data) != "remote" {
+		t.Fatalf("unexpected downloaded file: %q %v", data, err)
+	}
+	info, err := os.Stat(filepath.Join(localDir, "sub", "c.txt"))
+	if err != nil || info.ModTime().Unix() != 1700000000 {
+		t.Fatalf("expected mtime from drive, got %v %v", info.ModTime(), err)
+	}
+	if !strings.Contains(buf.String(), "skip\tNotes\tonline document") {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestDriveSyncDownSkipsUnsafeAndDuplicateNames(t *testing.T) {
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/drive/v1/files":
+			requests = append(requests, "list "+r.URL.Query().Get("folder_token"))
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"files": []map[string]any{
+					{"token": "f1", "name": "a.txt", "type": "file", "modified_time": "100"},
+					{"token": "f2", "name": "a.txt", "type": "file", "modified_time": "100"},
+					{"token": "f3", "name": "..", "type": "folder", "modified_time": "100"},
+					{"token": "f4", "name": `..\evil.txt`, "type": "file", "modified_time": "100"},
+					{"token": "f5", "name": "x/y.txt", "type": "file", "modified_time": "100"},
+				},
+				"has_more": false,
+			}})
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/drive/v1/files/f1/download":
+			requests = append(requests, "download f1")
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write([]byte("first"))
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+	localDir := t.TempDir()
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"sync", localDir, "fld_root", "--direction", "down"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("drive sync error: %v", err)
+	}
+
+	if strings.Join(requests, ",") != "list fld_root,download f1" {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	var payload struct {
+		Actions []driveSyncAction `json:"actions"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, buf.String())
+	}
+	got := make([]string, 0, len(payload.Actions))
+	for _, action := range payload.Actions {
+		got = append(got, action.Action+":"+action.Token)
+	}
+	if strings.Join(got, " ") != "skip:f2 skip:f3 skip:f4 skip:f5 download:f1" {
+		t.Fatalf("unexpected plan: %v", got)
+	}
+	entries, err := os.ReadDir(localDir)
+	if err != nil {
+		t.Fatalf("read dir: %v", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if strings.Join(names, ",") != driveSyncManifestName+",a.txt" {
+		t.Fatalf("unexpected local files: %v", names)
+	}
+}
+
+func TestDriveSyncBothPrefersNewerSide(t *testing.T) {
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/drive/v1/files":
+			requests = append(requests, "list "+r.URL.Query().Get("folder_token"))
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"files": []map[string]any{
+					{"token": "f1", "name": "a.txt", "type": "file", "modified_time": "200"},
+				},
+				"has_more": false,
+			}})
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/drive/v1/files/f1/download":
+			requests = append(requests, "download f1")
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write([]byte("remote"))
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, _ := newTestState(t, handler, false)
+	localDir := t.TempDir()
+	localPath := filepath.Join(localDir, "a.txt")
+	if err := os.WriteFile(localPath, []byte("local"), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	if err := os.Chtimes(localPath, time.Unix(100, 0), time.Unix(100, 0)); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"sync", localDir, "fld_root", "--direction", "both"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("drive sync error: %v", err)
+	}
+	data, _ := os.ReadFile(localPath)
+	if string(data) != "remote" || strings.Join(requests, ",") != "list fld_root,download f1" {
+		t.Fatalf("expected newer drive copy to win, local=%q requests=%v", data, requests)
+	}
+}
+
+func TestDriveSyncDirectionValidation(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+	})
+	state, _ := newTestState(t, handler, false)
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"sync", t.TempDir(), "fld_root", "--direction", "sideways"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "direction must be up, down, or both") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
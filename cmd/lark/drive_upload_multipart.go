@@ -0,0 +1,311 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/adler32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"lark/internal/larksdk"
+)
+
+const (
+	defaultDriveUploadConcurrency = 4
+	maxDriveUploadConcurrency     = 16
+
+	// Drive multipart upload transactions expire server-side; treat older
+	// resume state as stale and start a new transaction.
+	driveUploadStateTTL = 24 * time.Hour
+)
+
+// driveUploadState is persisted after every completed part so that
+// `drive upload --resume` can continue an interrupted multipart upload.
+type driveUploadState struct {
+	FilePath    string `json:"file_path"`
+	FileName    string `json:"file_name"`
+	FolderToken string `json:"folder_token"`
+	Size        int64  `json:"size"`
+	ModTime     int64  `json:"mod_time"`
+	UploadID    string `json:"upload_id"`
+	BlockSize   int64  `json:"block_size"`
+	BlockNum    int    `json:"block_num"`
+	Completed   []int  `json:"completed"`
+	CreatedAt   int64  `json:"created_at"`
+}
+
+type localDriveUpload struct {
+	file        *os.File
+	info        os.FileInfo
+	path        string
+	name        string
+	folderToken string
+	concurrency int
+	resume      bool
+}
+
+// uploadLocalDriveFile uploads an open local file, switching to the multipart
+// flow above the upload_all size limit or when resuming.
+func uploadLocalDriveFile(ctx context.Context, state *appState, token string, tokenType larksdk.AccessTokenType, req localDriveUpload) (larksdk.DriveUploadResult, error) {
+	if !req.resume && req.info.Size() <= larksdk.DriveUploadAllMaxSize {
+		return state.SDK.UploadDriveFile(ctx, token, tokenType, larksdk.UploadDriveFileRequest{
+			FileName:    req.name,
+			FolderToken: req.folderToken,
+			Size:        req.info.Size(),
+			File:        req.file,
+		})
+	}
+	absPath, err := filepath.Abs(req.path)
+	if err != nil {
+		return larksdk.DriveUploadResult{}, err
+	}
+	concurrency := req.concurrency
+	if concurrency <= 0 {
+		concurrency = defaultDriveUploadConcurrency
+	}
+	upload := &driveMultipartUpload{
+		state:       state,
+		token:       token,
+		tokenType:   tokenType,
+		file:        req.file,
+		filePath:    absPath,
+		fileName:    req.name,
+		folderToken: req.folderToken,
+		info:        req.info,
+		concurrency: concurrency,
+		resume:      req.resume,
+	}
+	fileToken, err := upload.run(ctx)
+	if err != nil {
+		return larksdk.DriveUploadResult{}, err
+	}
+	return larksdk.DriveUploadResult{FileToken: fileToken}, nil
+}
+
+type driveMultipartUpload struct {
+	state       *appState
+	token       string
+	tokenType   larksdk.AccessTokenType
+	file        *os.File
+	filePath    string
+	fileName    string
+	folderToken string
+	info        os.FileInfo
+	concurrency int
+	resume      bool
+}
+
+func (u *driveMultipartUpload) run(ctx context.Context) (string, error) {
+	statePath, err := driveUploadStatePath(u.state, u.filePath, u.folderToken, u.fileName)
+	if err != nil {
+		return "", err
+	}
+	var saved driveUploadState
+	resumed := false
+	if u.resume {
+		loaded, ok, err := loadDriveUploadState(statePath)
+		if err != nil {
+			return "", err
+		}
+		if ok && u.matches(loaded) {
+			saved = loaded
+			resumed = true
+		} else if u.state.Verbose {
+			fmt.Fprintln(errWriter(u.state), "no resumable upload state found; starting a new upload")
+		}
+	}
+	if !resumed {
+		session, err := u.state.SDK.PrepareDriveUpload(ctx, u.token, u.tokenType, larksdk.PrepareDriveUploadRequest{
+			FileName:    u.fileName,
+			FolderToken: u.folderToken,
+			Size:        u.info.Size(),
+		})
+		if err != nil {
+			return "", err
+		}
+		saved = driveUploadState{
+			FilePath:    u.filePath,
+			FileName:    u.fileName,
+			FolderToken: u.folderToken,
+			Size:        u.info.Size(),
+			ModTime:     u.info.ModTime().Unix(),
+			UploadID:    session.UploadID,
+			BlockSize:   session.BlockSize,
+			BlockNum:    session.BlockNum,
+			CreatedAt:   time.Now().Unix(),
+		}
+		if err := saveDriveUploadState(statePath, saved); err != nil {
+			return "", err
+		}
+	} else if u.state.Verbose {
+		fmt.Fprintf(errWriter(u.state), "resuming upload %s (%d/%d parts done)\n", saved.UploadID, len(saved.Completed), saved.BlockNum)
+	}
+
+	if err := u.uploadParts(ctx, statePath, &saved); err != nil {
+		return "", fmt.Errorf("%w; rerun with --resume to continue the upload", err)
+	}
+	fileToken, err := u.state.SDK.FinishDriveUpload(ctx, u.token, u.tokenType, saved.UploadID, saved.BlockNum)
+	if err != nil {
+		return "", err
+	}
+	_ = os.Remove(statePath)
+	return fileToken, nil
+}
+
+func (u *driveMultipartUpload) matches(saved driveUploadState) bool {
+	if saved.UploadID == "" || saved.BlockSize <= 0 || saved.BlockNum <= 0 {
+		return false
+	}
+	if time.Since(time.Unix(saved.CreatedAt, 0)) > driveUploadStateTTL {
+		return false
+	}
+	return saved.FilePath == u.filePath &&
+		saved.FileName == u.fileName &&
+		saved.FolderToken == u.folderToken &&
+		saved.Size == u.info.Size() &&
+		saved.ModTime == u.info.ModTime().Unix()
+}
+
+func (u *driveMultipartUpload) uploadParts(ctx context.Context, statePath string, saved *driveUploadState) error {
+	done := make(map[int]bool, len(saved.Completed))
+	for _, seq := range saved.Completed {
+		done[seq] = true
+	}
+	pending := make([]int, 0, saved.BlockNum)
+	var uploadedBytes int64
+	for seq := 0; seq < saved.BlockNum; seq++ {
+		if done[seq] {
+			uploadedBytes += partSize(saved, seq)
+			continue
+		}
+		pending = append(pending, seq)
+	}
+
+	progress := newProgressBar(u.state, "uploading "+u.fileName, saved.Size)
+	defer progress.Finish()
+	progress.Add(uploadedBytes)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var mu sync.Mutex
+	var firstErr error
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	workers := u.concurrency
+	if workers > len(pending) {
+		workers = len(pending)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, saved.BlockSize)
+			for seq := range jobs {
+				size := partSize(saved, seq)
+				err := u.uploadPart(ctx, saved, seq, buf[:size])
+				mu.Lock()
+				if err == nil {
+					saved.Completed = append(saved.Completed, seq)
+					sort.Ints(saved.Completed)
+					err = saveDriveUploadState(statePath, *saved)
+				}
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+				if err == nil {
+					progress.Add(size)
+				}
+			}
+		}()
+	}
+	for _, seq := range pending {
+		select {
+		case jobs <- seq:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+func (u *driveMultipartUpload) uploadPart(ctx context.Context, saved *driveUploadState, seq int, buf []byte) error {
+	offset := int64(seq) * saved.BlockSize
+	if _, err := io.ReadFull(io.NewSectionReader(u.file, offset, int64(len(buf))), buf); err != nil {
+		return fmt.Errorf("read part %d: %w", seq, err)
+	}
+	return u.state.SDK.UploadDrivePart(ctx, u.token, u.tokenType, larksdk.UploadDrivePartRequest{
+		UploadID: saved.UploadID,
+		Seq:      seq,
+		Size:     int64(len(buf)),
+		Checksum: strconv.FormatUint(uint64(adler32.Checksum(buf)), 10),
+		Data:     bytes.NewReader(buf),
+	})
+}
+
+func partSize(saved *driveUploadState, seq int) int64 {
+	offset := int64(seq) * saved.BlockSize
+	size := saved.BlockSize
+	if remaining := saved.Size - offset; remaining < size {
+		size = remaining
+	}
+	return size
+}
+
+func driveUploadStatePath(state *appState, filePath, folderToken, fileName string) (string, error) {
+	if state == nil || state.ConfigPath == "" {
+		return "", errors.New("config path is required for multipart uploads")
+	}
+	sum := sha256.Sum256([]byte(filePath + "\x00" + folderToken + "\x00" + fileName))
+	name := hex.EncodeToString(sum[:8]) + ".json"
+	return filepath.Join(filepath.Dir(state.ConfigPath), "uploads", name), nil
+}
+
+func loadDriveUploadState(path string) (driveUploadState, bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return driveUploadState{}, false, nil
+		}
+		return driveUploadState{}, false, err
+	}
+	var saved driveUploadState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return driveUploadState{}, false, fmt.Errorf("parse upload state %s: %w", path, err)
+	}
+	return saved, true, nil
+}
+
+func saveDriveUploadState(path string, saved driveUploadState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/adler32"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDriveUploadResumeWithoutStateStartsMultipartUpload(t *testing.T) {
+	content := []byte("0123456789")
+	var mu sync.Mutex
+	var requests []string
+	var parts []int
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/open-apis/drive/v1/files/upload_prepare":
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode prepare body: %v", err)
+			}
+			if body["parent_node"] != "fld_123" || body["file_name"] != "big.bin" {
+				t.Fatalf("unexpected prepare body: %v", body)
+			}
+			mu.Lock()
+			requests = append(requests, "prepare")
+			mu.Unlock()
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"code": 0,
+				"data": map[string]any{"upload_id": "up_1", "block_size": 4, "block_num": 3},
+			})
+		case "/open-apis/drive/v1/files/upload_part":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Fatalf("parse multipart: %v", err)
+			}
+			var seq int
+			fmt.Sscanf(r.FormValue("seq"), "%d", &seq)
+			part, err := r.MultipartForm.File["file"][0].Open()
+			if err != nil {
+				t.Fatalf("open part: %v", err)
+			}
+			data, _ := io.ReadAll(part)
+			part.Close()
+			want := content[seq*4 : min(seq*4+4, len(content))]
+			if !bytes.Equal(data, want) {
+				t.Fatalf("unexpected data for part %d: %q", seq, data)
+			}
+			if r.FormValue("checksum") != fmt.Sprintf("%d", adler32.Checksum(data)) {
+				t.Fatalf("unexpected checksum for part %d: %s", seq, r.FormValue("checksum"))
+			}
+			mu.Lock()
+			parts = append(parts, seq)
+			mu.Unlock()
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{}})
+		case "/open-apis/drive/v1/files/upload_finish":
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode finish body: %v", err)
+			}
+			if body["upload_id"] != "up_1" {
+				t.Fatalf("unexpected finish body: %v", body)
+			}
+			mu.Lock()
+			requests = append(requests, "finish")
+			mu.Unlock()
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"file_token": "file_big"}})
+		case "/open-apis/drive/v1/files/file_big":
+			mu.Lock()
+			requests = append(requests, "get")
+			mu.Unlock()
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"code": 0,
+				"data": map[string]any{"file": map[string]any{"token": "file_big", "name": "big.bin", "type": "file", "url": "https://example.com/big"}},
+			})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, false)
+	state.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"upload", path, "--folder-id", "fld_123", "--resume", "--concurrency", "2"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("drive upload error: %v", err)
+	}
+
+	sort.Ints(parts)
+	if strings.Join(requests, ",") != "prepare,finish,get" || fmt.Sprint(parts) != "[0 1 2]" {
+		t.Fatalf("unexpected calls: requests=%v parts=%v", requests, parts)
+	}
+	if !strings.Contains(buf.String(), "file_big\tbig.bin\tfile\thttps://example.com/big") {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+	entries, _ := os.ReadDir(filepath.Join(filepath.Dir(state.ConfigPath), "uploads"))
+	if len(entries) != 0 {
+		t.Fatalf("expected upload state to be removed, found %d entries", len(entries))
+	}
+}
+
+func TestDriveUploadResumeSkipsCompletedParts(t *testing.T) {
+	content := []byte("0123456789")
+	var requests []string
+	var parts []int
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/open-apis/drive/v1/files/upload_part":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Fatalf("parse multipart: %v", err)
+			}
+			if r.FormValue("upload_id") != "up_1" {
+				t.Fatalf("unexpected upload id: %s", r.FormValue("upload_id"))
+			}
+			var seq int
+			fmt.Sscanf(r.FormValue("seq"), "%d", &seq)
+			part, err := r.MultipartForm.File["file"][0].Open()
+			if err != nil {
+				t.Fatalf("open part: %v", err)
+			}
+			data, _ := io.ReadAll(part)
+			part.Close()
+			if want := content[seq*4 : min(seq*4+4, len(content))]; !bytes.Equal(data, want) {
+				t.Fatalf("unexpected data for part %d: %q", seq, data)
+			}
+			parts = append(parts, seq)
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{}})
+		case "/open-apis/drive/v1/files/upload_finish":
+			requests = append(requests, "finish")
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"file_token": "file_big"}})
+		case "/open-apis/drive/v1/files/file_big":
+			requests = append(requests, "get")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"code": 0,
+				"data": map[string]any{"file": map[string]any{"token": "file_big", "name": "big.bin", "type": "file", "url": "https://example.com/big"}},
+			})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, _ := newTestState(t, handler, false)
+	state.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	statePath, err := driveUploadStatePath(state, path, "fld_123", "big.bin")
+	if err != nil {
+		t.Fatalf("state path: %v", err)
+	}
+	if err := saveDriveUploadState(statePath, driveUploadState{
+		FilePath:    path,
+		FileName:    "big.bin",
+		FolderToken: "fld_123",
+		Size:        info.Size(),
+		ModTime:     info.ModTime().Unix(),
+		UploadID:    "up_1",
+		BlockSize:   4,
+		BlockNum:    3,
+		Completed:   []int{0},
+		CreatedAt:   time.Now().Unix(),
+	}); err != nil {
+		t.Fatalf("save state: %v", err)
+	}
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"upload", path, "--folder-id", "fld_123", "--resume"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("drive upload error: %v", err)
+	}
+
+	sort.Ints(parts)
+	if strings.Join(requests, ",") != "finish,get" || fmt.Sprint(parts) != "[1 2]" {
+		t.Fatalf("unexpected calls: requests=%v parts=%v", requests, parts)
+	}
+	if _, err := os.Stat(statePath); !os.IsNotExist(err) {
+		t.Fatalf("expected state file removed, got %v", err)
+	}
+}
+
+func TestDriveUploadPartFailureKeepsResumeState(t *testing.T) {
+	content := []byte("0123456789")
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/open-apis/drive/v1/files/upload_prepare":
+			requests = append(requests, "prepare")
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"code": 0,
+				"data": map[string]any{"upload_id": "up_1", "block_size": 4, "block_num": 3},
+			})
+		case "/open-apis/drive/v1/files/upload_part":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Fatalf("parse multipart: %v", err)
+			}
+			requests = append(requests, "part "+r.FormValue("seq"))
+			if r.FormValue("seq") == "2" {
+				_ = json.NewEncoder(w).Encode(map[string]any{"code": 1061001, "msg": "internal error"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, _ := newTestState(t, handler, false)
+	state.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"upload", path, "--folder-id", "fld_123", "--resume", "--concurrency", "1"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--resume") {
+		t.Fatalf("expected resumable error, got %v", err)
+	}
+	if strings.Join(requests, ",") != "prepare,part 0,part 1,part 2" {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	statePath, _ := driveUploadStatePath(state, path, "fld_123", "big.bin")
+	saved, ok, err := loadDriveUploadState(statePath)
+	if err != nil || !ok {
+		t.Fatalf("expected saved state, ok=%t err=%v", ok, err)
+	}
+	if saved.UploadID != "up_1" || fmt.Sprint(saved.Completed) != "[0 1]" {
+		t.Fatalf("unexpected saved state: %+v", saved)
+	}
+}
+
+func TestDriveUploadConcurrencyValidation(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+	})
+	state, _ := newTestState(t, handler, false)
+	state.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, []byte("x"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"upload", path, "--concurrency", "0"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "concurrency must be between 1 and") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -232,15 +232,15 @@ func newMailFoldersCmd(state *appState) *cobra.Command {
 func newMailListCmd(state *appState) *cobra.Command {
 	var mailboxID string
 	var folderID string
-	var limit int
+	var paging listPaging
 	var onlyUnread bool
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List mail messages",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				return flagUsage(cmd, "limit must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			mailboxID = resolveMailboxID(state, mailboxID)
 			if _, err := requireSDK(state); err != nil {
@@ -254,28 +254,24 @@ func newMailListCmd(state *appState) *cobra.Command {
 			if err != nil {
 				return withUserScopeHintForCommand(state, err)
 			}
-			debugf(state, "mail list: mailbox_id=%q folder_id=%q limit=%d only_unread=%t\n", mailboxID, folderID, limit, onlyUnread)
+			debugf(state, "mail list: mailbox_id=%q folder_id=%q limit=%d only_unread=%t\n", mailboxID, folderID, paging.itemLimit(), onlyUnread)
 			ctx := cmd.Context()
-			messages := make([]larksdk.MailMessage, 0, limit)
-			pageToken := ""
-			remaining := limit
-			for {
-				pageSize := remaining
-				if pageSize > maxMailPageSize {
-					pageSize = maxMailPageSize
-				}
+			collected := newListCollector[larksdk.MailMessage](state, paging.itemLimit())
+			pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.MailMessage], error) {
 				result, err := state.SDK.ListMailMessages(ctx, token, larksdk.ListMailMessagesRequest{
 					MailboxID:  mailboxID,
 					FolderID:   folderID,
-					PageSize:   pageSize,
+					PageSize:   collected.pageSize(maxMailPageSize),
 					PageToken:  pageToken,
 					OnlyUnread: onlyUnread,
 				})
 				if err != nil {
-					return withUserScopeHintForCommand(state, err)
+					return larksdk.Page[larksdk.MailMessage]{}, err
 				}
+				// The list API returns IDs only; fetch details for what will be kept.
+				items := make([]larksdk.MailMessage, 0, len(result.Items))
 				for _, message := range result.Items {
-					if len(messages) >= limit {
+					if collected.limit > 0 && len(items) >= collected.remaining() {
 						break
 					}
 					if message.MessageID == "" {
@@ -283,26 +279,23 @@ func newMailListCmd(state *appState) *cobra.Command {
 					}
 					item, err := state.SDK.GetMailMessage(ctx, token, mailboxID, message.MessageID)
 					if err != nil {
-						return withUserScopeHintForCommand(state, err)
+						return larksdk.Page[larksdk.MailMessage]{}, err
 					}
 					if item.MessageID == "" {
 						item.MessageID = message.MessageID
 					}
 					stripMailMessageContent(&item)
-					messages = append(messages, item)
-				}
-				if len(messages) >= limit || !result.HasMore {
-					break
-				}
-				remaining = limit - len(messages)
-				pageToken = result.PageToken
-				if pageToken == "" {
-					break
+					items = append(items, item)
 				}
+				return larksdk.Page[larksdk.MailMessage]{Items: items, PageToken: result.PageToken, HasMore: result.HasMore}, nil
+			})
+			if err := drainPages(ctx, state, collected, pager, 0); err != nil {
+				return withUserScopeHintForCommand(state, err)
 			}
-			if len(messages) > limit {
-				messages = messages[:limit]
+			if collected.streaming {
+				return nil
 			}
+			messages := collected.items
 			payload := map[string]any{"messages": messages}
 			lines := make([]string, 0, len(messages))
 			for _, message := range messages {
@@ -315,7 +308,8 @@ func newMailListCmd(state *appState) *cobra.Command {
 
 	cmd.Flags().StringVar(&mailboxID, "mailbox-id", "", "user mailbox ID (defaults to config default_mailbox_id or 'me')")
 	cmd.Flags().StringVar(&folderID, "folder-id", "", "filter by folder ID (system aliases: INBOX/SENT/DRAFT/TRASH/SPAM/ARCHIVED)")
-	cmd.Flags().IntVar(&limit, "limit", 20, "max number of messages to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 20, "max number of messages to return")
+	addPagingFlags(cmd, &paging)
 	cmd.Flags().BoolVar(&onlyUnread, "only-unread", false, "only return unread messages")
 	return cmd
 }
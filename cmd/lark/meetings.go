@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
@@ -142,7 +143,7 @@ func isLikelyMeetingNo(value string) bool {
 func newMeetingListCmd(state *appState) *cobra.Command {
 	var start string
 	var end string
-	var limit int
+	var paging listPaging
 	var meetingStatus int
 	var meetingNo string
 	var userID string
@@ -156,8 +157,8 @@ func newMeetingListCmd(state *appState) *cobra.Command {
 		Use:   "list",
 		Short: "List meetings",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				return flagUsage(cmd, "limit must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			var startUnix int64
 			var endUnix int64
@@ -210,9 +211,7 @@ func newMeetingListCmd(state *appState) *cobra.Command {
 				return err
 			}
 
-			meetings := make([]larksdk.MeetingListItem, 0, limit)
-			pageToken := ""
-			remaining := limit
+			collected := newListCollector[larksdk.MeetingListItem](state, paging.itemLimit())
 			var meetingStatusPtr *int
 			if cmd.Flags().Changed("status") {
 				meetingStatusPtr = &meetingStatus
@@ -229,18 +228,14 @@ func newMeetingListCmd(state *appState) *cobra.Command {
 			if cmd.Flags().Changed("include-webinar") {
 				includeWebinarPtr = &includeWebinar
 			}
-			for {
-				pageSize := meetingListPageSize(remaining)
-				if pageSize <= 0 {
-					break
-				}
+			pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.MeetingListItem], error) {
 				req := larksdk.ListMeetingsRequest{
 					MeetingStatus:           meetingStatusPtr,
 					MeetingNo:               meetingNo,
 					UserID:                  userID,
 					RoomID:                  roomID,
 					MeetingType:             meetingTypePtr,
-					PageSize:                pageSize,
+					PageSize:                meetingListPageSize(collected.pageSize(meetingListMaxPageSize)),
 					PageToken:               pageToken,
 					IncludeExternalMeetings: includeExternalPtr,
 					IncludeWebinar:          includeWebinarPtr,
@@ -250,23 +245,16 @@ func newMeetingListCmd(state *appState) *cobra.Command {
 					req.StartTime = strconv.FormatInt(startUnix, 10)
 					req.EndTime = strconv.FormatInt(endUnix, 10)
 				}
-				result, err := state.SDK.ListMeetings(cmd.Context(), token, req)
-				if err != nil {
-					return err
-				}
-				meetings = append(meetings, result.Items...)
-				if len(meetings) >= limit || !result.HasMore {
-					break
-				}
-				remaining = limit - len(meetings)
-				pageToken = result.PageToken
-				if pageToken == "" || remaining <= 0 {
-					break
-				}
+				result, err := state.SDK.ListMeetings(ctx, token, req)
+				return larksdk.Page[larksdk.MeetingListItem]{Items: result.Items, PageToken: result.PageToken, HasMore: result.HasMore}, err
+			})
+			if err := drainPages(cmd.Context(), state, collected, pager, 0); err != nil {
+				return err
 			}
-			if len(meetings) > limit {
-				meetings = meetings[:limit]
+			if collected.streaming {
+				return nil
 			}
+			meetings := collected.items
 			payload := map[string]any{"meetings": meetings}
 			lines := make([]string, 0, len(meetings))
 			for _, meeting := range meetings {
@@ -288,7 +276,8 @@ func newMeetingListCmd(state *appState) *cobra.Command {
 
 	cmd.Flags().StringVar(&start, "start", "", "start time (RFC3339 or unix seconds; default: now-6 months when omitted)")
 	cmd.Flags().StringVar(&end, "end", "", "end time (RFC3339 or unix seconds; default: now when omitted)")
-	cmd.Flags().IntVar(&limit, "limit", 20, "max number of meetings to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 20, "max number of meetings to return")
+	addPagingFlags(cmd, &paging)
 	cmd.Flags().IntVar(&meetingStatus, "status", 0, "meeting status")
 	cmd.Flags().StringVar(&meetingNo, "meeting-no", "", "meeting number (9 digits)")
 	cmd.Flags().StringVar(&userID, "user-id", "", "participant user ID")
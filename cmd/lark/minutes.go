@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -69,7 +70,7 @@ func newMinutesInfoCmd(state *appState) *cobra.Command {
 }
 
 func newMinutesListCmd(state *appState) *cobra.Command {
-	var limit int
+	var paging listPaging
 	var folderID string
 	var fileType string
 	var query string
@@ -79,8 +80,8 @@ func newMinutesListCmd(state *appState) *cobra.Command {
 		Short: "List Minutes",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				return flagUsage(cmd, "limit must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			if _, err := requireSDK(state); err != nil {
 				return err
@@ -96,25 +97,14 @@ func newMinutesListCmd(state *appState) *cobra.Command {
 			fileType = strings.TrimSpace(fileType)
 			query = strings.TrimSpace(query)
 			queryLower := strings.ToLower(query)
-			minutes := make([]larksdk.Minute, 0, limit)
-			pageToken := ""
-			for {
-				pageSize := maxDrivePageSize
-				remaining := limit - len(minutes)
-				if remaining <= 0 {
-					break
-				}
-				if pageSize > remaining {
-					pageSize = remaining
-				}
-				result, err := state.SDK.ListDriveFiles(cmd.Context(), token, larksdk.AccessTokenType(tokenTypeValue), larksdk.ListDriveFilesRequest{
+			collected := newListCollector[larksdk.Minute](state, paging.itemLimit())
+			pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.Minute], error) {
+				result, err := state.SDK.ListDriveFiles(ctx, token, larksdk.AccessTokenType(tokenTypeValue), larksdk.ListDriveFilesRequest{
 					FolderToken: folderID,
-					PageSize:    pageSize,
+					PageSize:    collected.pageSize(maxDrivePageSize),
 					PageToken:   pageToken,
 				})
-				if err != nil {
-					return err
-				}
+				matched := make([]larksdk.Minute, 0, len(result.Files))
 				for _, file := range result.Files {
 					if fileType != "" && !strings.EqualFold(file.FileType, fileType) {
 						continue
@@ -122,23 +112,21 @@ func newMinutesListCmd(state *appState) *cobra.Command {
 					if queryLower != "" && !strings.Contains(strings.ToLower(file.Name), queryLower) {
 						continue
 					}
-					minutes = append(minutes, larksdk.Minute{
+					matched = append(matched, larksdk.Minute{
 						Token: file.Token,
 						Title: file.Name,
 						URL:   file.URL,
 					})
-					if len(minutes) >= limit {
-						break
-					}
-				}
-				if len(minutes) >= limit || !result.HasMore {
-					break
-				}
-				pageToken = result.PageToken
-				if pageToken == "" {
-					break
 				}
+				return larksdk.Page[larksdk.Minute]{Items: matched, PageToken: result.PageToken, HasMore: result.HasMore}, err
+			})
+			if err := drainPages(cmd.Context(), state, collected, pager, 0); err != nil {
+				return err
+			}
+			if collected.streaming {
+				return nil
 			}
+			minutes := collected.items
 			payload := map[string]any{"minutes": minutes}
 			lines := make([]string, 0, len(minutes))
 			for _, minute := range minutes {
@@ -149,7 +137,8 @@ func newMinutesListCmd(state *appState) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().IntVar(&limit, "limit", 20, "max number of minutes to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 20, "max number of minutes to return")
+	addPagingFlags(cmd, &paging)
 	cmd.Flags().StringVar(&folderID, "folder-id", "", "Drive folder token to list minutes from (default: root)")
 	cmd.Flags().StringVar(&fileType, "type", "minutes", "Drive file type to match (default: minutes)")
 	cmd.Flags().StringVar(&query, "query", "", "filter minutes by title substring")
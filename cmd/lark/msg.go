@@ -3,7 +3,9 @@ package main
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -23,8 +25,19 @@ func newMsgCmd(state *appState) *cobra.Command {
 	}
 	annotateAuthServices(cmd, "im")
 	cmd.AddCommand(newMsgSendCmd(state))
+	cmd.AddCommand(newMsgScheduleCmd(state))
 	cmd.AddCommand(newMsgReplyCmd(state))
+	cmd.AddCommand(newMsgUpdateCmd(state))
+	cmd.AddCommand(newMsgEditCmd(state))
+	cmd.AddCommand(newMsgRecallCmd(state))
+	cmd.AddCommand(newMsgForwardCmd(state))
+	cmd.AddCommand(newMsgMergeForwardCmd(state))
+	cmd.AddCommand(newMsgUrgentCmd(state))
+	cmd.AddCommand(newMsgDownloadCmd(state))
+	cmd.AddCommand(newMsgExportCmd(state))
 	cmd.AddCommand(newMsgListCmd(state))
+	cmd.AddCommand(newMsgThreadCmd(state))
+	cmd.AddCommand(newMsgReadersCmd(state))
 	cmd.AddCommand(newMsgSearchCmd(state))
 	cmd.AddCommand(newMsgReactionsCmd(state))
 	cmd.AddCommand(newMsgPinCmd(state))
@@ -36,10 +49,14 @@ func newMsgSendCmd(state *appState) *cobra.Command {
 	var receiveID string
 	var receiveIDType string
 	var contentOpts messageContentOptions
+	var attachments []messageAttachment
+	var at string
 
 	cmd := &cobra.Command{
 		Use:   "send <receive-id>",
 		Short: "Send a message to a chat or user",
+		Example: `  lark messages send <CHAT_ID> --text "hello"
+  lark messages send <CHAT_ID> --text "standup in 5" --at 2025-06-02T09:55:00+08:00`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
 				return argsUsageError(cmd, err)
@@ -56,10 +73,48 @@ func newMsgSendCmd(state *appState) *cobra.Command {
 				return flagUsage(cmd, "receive-id-type must be one of chat_id, open_id, user_id, email")
 			}
 			receiveIDType = normalizedType
-			msgType, content, err := resolveMessageContent(contentOpts)
+			if strings.TrimSpace(at) != "" && (strings.TrimSpace(contentOpts.Markdown) != "" || len(attachments) > 0) {
+				return flagUsage(cmd, "--at cannot be combined with --markdown, --image, or --file")
+			}
+			// Inline content (if any) is sent first, then each attachment in
+			// command-line order.
+			type pendingMessage struct {
+				msgType    string
+				content    string
+				markdown   *markdownMessage
+				attachment *messageAttachment
+			}
+			var pending []pendingMessage
+			markdown, err := parseMarkdownMessage(contentOpts)
 			if err != nil {
 				return err
 			}
+			if markdown != nil {
+				pending = append(pending, pendingMessage{msgType: "post", markdown: markdown})
+			} else if len(attachments) == 0 || messageContentProvided(contentOpts) {
+				msgType, content, err := resolveMessageContent(contentOpts)
+				if err != nil {
+					return err
+				}
+				pending = append(pending, pendingMessage{msgType: msgType, content: content})
+			} else if strings.TrimSpace(contentOpts.MsgType) != "" {
+				return flagUsage(cmd, "msg-type cannot be used with only --image/--file")
+			}
+			if err := validateMessageAttachments(attachments); err != nil {
+				return err
+			}
+			for i := range attachments {
+				pending = append(pending, pendingMessage{attachment: &attachments[i]})
+			}
+			if strings.TrimSpace(at) != "" {
+				return scheduleMessageSend(cmd, state, at, scheduledMessage{
+					ReceiveID:     receiveID,
+					ReceiveIDType: receiveIDType,
+					MsgType:       pending[0].msgType,
+					Content:       pending[0].content,
+					UUID:          strings.TrimSpace(contentOpts.UUID),
+				})
+			}
 			if _, err := requireSDK(state); err != nil {
 				return err
 			}
@@ -67,24 +122,88 @@ func newMsgSendCmd(state *appState) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			messageID, err := state.SDK.SendMessage(cmd.Context(), token, larksdk.MessageRequest{
-				ReceiveID:     receiveID,
-				ReceiveIDType: receiveIDType,
-				MsgType:       msgType,
-				Content:       content,
-				Text:          strings.TrimSpace(contentOpts.Text),
-				UUID:          strings.TrimSpace(contentOpts.UUID),
-			})
-			if err != nil {
-				return err
+			sent := make([]sentMessage, 0, len(pending))
+			for i, message := range pending {
+				item := sentMessage{MsgType: message.msgType}
+				content := message.content
+				if message.markdown != nil {
+					content, err = message.markdown.content(cmd.Context(), state, token)
+					if err != nil {
+						return sendBatchError(state, sent, err)
+					}
+				}
+				if message.attachment != nil {
+					item.Path = message.attachment.Path
+					item.MsgType, content, err = uploadMessageAttachment(cmd.Context(), state, token, *message.attachment)
+					if err != nil {
+						return sendBatchError(state, sent, err)
+					}
+				}
+				item.MessageID, err = state.SDK.SendMessage(cmd.Context(), token, larksdk.MessageRequest{
+					ReceiveID:     receiveID,
+					ReceiveIDType: receiveIDType,
+					MsgType:       item.MsgType,
+					Content:       content,
+					Text:          strings.TrimSpace(contentOpts.Text),
+					UUID:          attachmentUUID(contentOpts.UUID, i),
+				})
+				if err != nil {
+					return sendBatchError(state, sent, err)
+				}
+				sent = append(sent, item)
+			}
+			if len(attachments) == 0 {
+				payload := map[string]any{"message_id": sent[0].MessageID}
+				return state.Printer.Print(payload, fmt.Sprintf("message_id: %s", sent[0].MessageID))
+			}
+			payload := map[string]any{"message_id": sent[0].MessageID, "messages": sent}
+			lines := make([]string, 0, len(sent))
+			for _, item := range sent {
+				lines = append(lines, fmt.Sprintf("%s\t%s\t%s", item.MessageID, item.MsgType, item.Path))
 			}
-			payload := map[string]any{"message_id": messageID}
-			return state.Printer.Print(payload, fmt.Sprintf("message_id: %s", messageID))
+			return state.Printer.Print(payload, tableText([]string{"message_id", "msg_type", "path"}, lines, "no messages sent"))
 		},
 	}
 
 	cmd.Flags().StringVar(&receiveIDType, "receive-id-type", "chat_id", "receive ID type (chat_id, open_id, user_id, email)")
+	cmd.Flags().StringVar(&at, "at", "", "queue the message for this time (unix seconds, RFC3339, or relative like +2h); see messages schedule")
 	addMessageContentFlags(cmd, &contentOpts)
+	addMessageAttachmentFlags(cmd, &attachments)
 	registerEnumCompletion(cmd, "receive-id-type", receiveIDTypeValues)
 	return cmd
 }
+
+// scheduleMessageSend queues a resolved message for messages schedule run.
+func scheduleMessageSend(cmd *cobra.Command, state *appState, at string, message scheduledMessage) error {
+	now := time.Now()
+	raw, err := parseTimeArg(at, now)
+	if err != nil {
+		return flagUsage(cmd, err.Error())
+	}
+	sendAt, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return flagUsage(cmd, fmt.Sprintf("invalid --at %q", at))
+	}
+	if sendAt <= now.Unix() {
+		return flagUsage(cmd, "--at must be in the future")
+	}
+	message.SendAt = sendAt
+	queued, err := enqueueScheduledMessage(state, message)
+	if err != nil {
+		return err
+	}
+	payload := map[string]any{"scheduled_id": queued.ID, "send_at": queued.SendAt}
+	return state.Printer.Print(payload, fmt.Sprintf("scheduled %s for %s (delivered by messages schedule run)", queued.ID, formatUnixTime(queued.SendAt)))
+}
+
+// sendBatchError reports which messages of a multi-message send already went
+// out, so a retry does not duplicate them.
+func sendBatchError(state *appState, sent []sentMessage, err error) error {
+	for _, item := range sent {
+		fmt.Fprintf(errWriter(state), "sent %s message %s\n", item.MsgType, item.MessageID)
+	}
+	if len(sent) > 0 {
+		return fmt.Errorf("send stopped after %d messages: %w", len(sent), err)
+	}
+	return err
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+// messageAttachment is a local file passed with --image or --file.
+type messageAttachment struct {
+	Kind string
+	Path string
+}
+
+// sentMessage describes one message created by messages send.
+type sentMessage struct {
+	MessageID string `json:"message_id"`
+	MsgType   string `json:"msg_type"`
+	Path      string `json:"path,omitempty"`
+}
+
+// attachmentFlag appends to a list shared by --image and --file so
+// attachments are sent in the order they appear on the command line.
+type attachmentFlag struct {
+	kind string
+	list *[]messageAttachment
+}
+
+func (f attachmentFlag) String() string {
+	return ""
+}
+
+func (f attachmentFlag) Set(value string) error {
+	path := strings.TrimSpace(value)
+	if path == "" {
+		return fmt.Errorf("%s path must not be empty", f.kind)
+	}
+	*f.list = append(*f.list, messageAttachment{Kind: f.kind, Path: path})
+	return nil
+}
+
+func (f attachmentFlag) Type() string {
+	return "path"
+}
+
+func addMessageAttachmentFlags(cmd *cobra.Command, list *[]messageAttachment) {
+	cmd.Flags().Var(attachmentFlag{kind: "image", list: list}, "image", "local image to upload and send (repeatable)")
+	cmd.Flags().Var(attachmentFlag{kind: "file", list: list}, "file", "local file to upload and send; .opus as audio, .mp4 as media (repeatable)")
+}
+
+// messageFileType maps a local file to the im/v1/files file_type and the
+// msg_type used to send it.
+func messageFileType(path string) (fileType string, msgType string) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".opus":
+		return "opus", "audio"
+	case ".mp4":
+		return "mp4", "media"
+	case ".pdf":
+		return "pdf", "file"
+	case ".doc", ".docx":
+		return "doc", "file"
+	case ".xls", ".xlsx":
+		return "xls", "file"
+	case ".ppt", ".pptx":
+		return "ppt", "file"
+	default:
+		return "stream", "file"
+	}
+}
+
+// validateMessageAttachments checks every attachment before anything is
+// uploaded, so a bad path does not leave a half-sent batch behind.
+func validateMessageAttachments(attachments []messageAttachment) error {
+	for _, attachment := range attachments {
+		info, err := os.Stat(attachment.Path)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", attachment.Kind, attachment.Path, err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("%s %s is a directory", attachment.Kind, attachment.Path)
+		}
+		if info.Size() == 0 {
+			return fmt.Errorf("%s %s is empty", attachment.Kind, attachment.Path)
+		}
+		limit := larksdk.MessageFileMaxSize
+		if attachment.Kind == "image" {
+			limit = larksdk.MessageImageMaxSize
+		}
+		if info.Size() > limit {
+			return fmt.Errorf("%s %s is %d bytes; the limit is %d MB", attachment.Kind, attachment.Path, info.Size(), limit>>20)
+		}
+	}
+	return nil
+}
+
+// uploadMessageAttachment uploads one attachment and returns the msg_type and
+// content JSON for sending it.
+func uploadMessageAttachment(ctx context.Context, state *appState, token string, attachment messageAttachment) (string, string, error) {
+	file, err := os.Open(attachment.Path)
+	if err != nil {
+		return "", "", err
+	}
+	defer file.Close()
+
+	if attachment.Kind == "image" {
+		imageKey, err := state.SDK.UploadMessageImage(ctx, token, file)
+		if err != nil {
+			return "", "", fmt.Errorf("%s: %w", attachment.Path, err)
+		}
+		raw, err := json.Marshal(map[string]string{"image_key": imageKey})
+		return "image", string(raw), err
+	}
+	fileType, msgType := messageFileType(attachment.Path)
+	fileKey, err := state.SDK.UploadMessageFile(ctx, token, larksdk.UploadMessageFileRequest{
+		FileType: fileType,
+		FileName: filepath.Base(attachment.Path),
+		File:     file,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("%s: %w", attachment.Path, err)
+	}
+	raw, err := json.Marshal(map[string]string{"file_key": fileKey})
+	return msgType, string(raw), err
+}
+
+// messageContentProvided reports whether any inline content flag was set.
+func messageContentProvided(opts messageContentOptions) bool {
+	for _, value := range []string{opts.Text, opts.Post, opts.Content, opts.ContentFile, opts.ImageKey, opts.FileKey, opts.MediaKey, opts.Markdown} {
+		if strings.TrimSpace(value) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// attachmentUUID derives a distinct idempotency key per message so a batch
+// sent with --uuid is not collapsed into one message.
+func attachmentUUID(uuid string, index int) string {
+	uuid = strings.TrimSpace(uuid)
+	if uuid == "" || index == 0 {
+		return uuid
+	}
+	return fmt.Sprintf("%s-%d", uuid, index)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMsgSendUploadsAttachmentsInOrder(t *testing.T) {
+	dir := t.TempDir()
+	image := filepath.Join(dir, "shot.png")
+	report := filepath.Join(dir, "report.pdf")
+	clip := filepath.Join(dir, "clip.mp4")
+	for _, path := range []string{image, report, clip} {
+		if err := os.WriteFile(path, []byte("data"), 0o600); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+
+	var sent []string
+	var fileTypes []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/open-apis/im/v1/images":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Fatalf("parse image form: %v", err)
+			}
+			if r.FormValue("image_type") != "message" {
+				t.Fatalf("unexpected image_type: %q", r.FormValue("image_type"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"image_key": "img_1"}})
+		case "/open-apis/im/v1/files":
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Fatalf("parse file form: %v", err)
+			}
+			fileTypes = append(fileTypes, r.FormValue("file_type")+":"+r.FormValue("file_name"))
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"file_key": "file_" + r.FormValue("file_type")}})
+		case "/open-apis/im/v1/messages":
+			var payload map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode message: %v", err)
+			}
+			sent = append(sent, payload["msg_type"]+" "+payload["content"])
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"message_id": "om_" + payload["msg_type"]}})
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"send", "oc_1", "--text", "build done", "--file", report, "--image", image, "--file", clip})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	want := []string{
+		`text {"text":"build done"}`,
+		`file {"file_key":"file_pdf"}`,
+		`image {"image_key":"img_1"}`,
+		`media {"file_key":"file_mp4"}`,
+	}
+	if strings.Join(sent, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected messages:\n%s", strings.Join(sent, "\n"))
+	}
+	if strings.Join(fileTypes, ",") != "pdf:report.pdf,mp4:clip.mp4" {
+		t.Fatalf("unexpected file uploads: %v", fileTypes)
+	}
+	var payload struct {
+		MessageID string        `json:"message_id"`
+		Messages  []sentMessage `json:"messages"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if payload.MessageID != "om_text" || len(payload.Messages) != 4 || payload.Messages[2].Path != image {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestMsgSendMissingAttachmentDoesNotCallHTTP(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s", r.URL.Path)
+	})
+	state, _ := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"send", "oc_1", "--text", "hi", "--image", filepath.Join(t.TempDir(), "missing.png")})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "missing.png") {
+		t.Fatalf("expected missing file error, got %v", err)
+	}
+}
+
+func TestMessageFileType(t *testing.T) {
+	cases := map[string][2]string{
+		"a.opus":   {"opus", "audio"},
+		"b.MP4":    {"mp4", "media"},
+		"c.docx":   {"doc", "file"},
+		"d.xlsx":   {"xls", "file"},
+		"e.pptx":   {"ppt", "file"},
+		"f.pdf":    {"pdf", "file"},
+		"g.tar.gz": {"stream", "file"},
+	}
+	for name, want := range cases {
+		fileType, msgType := messageFileType(name)
+		if fileType != want[0] || msgType != want[1] {
+			t.Fatalf("%s: got %s/%s, want %s/%s", name, fileType, msgType, want[0], want[1])
+		}
+	}
+}
@@ -18,6 +18,8 @@ type messageContentOptions struct {
 	ImageKey    string
 	FileKey     string
 	MediaKey    string
+	Markdown    string
+	Lang        string
 	UUID        string
 }
 
@@ -30,6 +32,8 @@ func addMessageContentFlags(cmd *cobra.Command, opts *messageContentOptions) {
 	cmd.Flags().StringVar(&opts.ImageKey, "image-key", "", "image key (msg_type=image)")
 	cmd.Flags().StringVar(&opts.FileKey, "file-key", "", "file key (msg_type=file|audio|media)")
 	cmd.Flags().StringVar(&opts.MediaKey, "media-key", "", "media key (msg_type=media)")
+	cmd.Flags().StringVar(&opts.Markdown, "markdown", "", "Markdown converted to a post message (text, @file, or @- for stdin)")
+	cmd.Flags().StringVar(&opts.Lang, "lang", "zh_cn", "post locale for --markdown (zh_cn, en_us, ja_jp)")
 	cmd.Flags().StringVar(&opts.UUID, "uuid", "", "request UUID for idempotency")
 }
 
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+// messageResource is one image or file referenced by a message's content.
+type messageResource struct {
+	MessageID string `json:"message_id"`
+	Key       string `json:"key"`
+	Type      string `json:"type"`
+	Path      string `json:"path,omitempty"`
+	Bytes     int64  `json:"bytes,omitempty"`
+	Error     string `json:"error,omitempty"`
+
+	name string
+}
+
+func newMsgDownloadCmd(state *appState) *cobra.Command {
+	var messageID string
+	var outDir string
+	var chatID string
+	var since string
+	var until string
+	var resourceType string
+
+	cmd := &cobra.Command{
+		Use:   "download [message-id]",
+		Short: "Download images and files attached to messages",
+		Long: `Download every image and file referenced by a message, including images
+embedded in rich-text (post) messages and video covers.
+
+With --chat-id and --since, download the resources of every message in a chat
+sent in that time range instead.`,
+		Example: `  lark messages download <MESSAGE_ID> --out-dir ./downloads
+  lark messages download --chat-id <CHAT_ID> --since -7d --out-dir ./downloads --type image`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			if len(args) == 1 {
+				messageID = strings.TrimSpace(args[0])
+			}
+			chatID = strings.TrimSpace(chatID)
+			switch {
+			case messageID != "" && chatID != "":
+				return usageError(cmd, "use either <message-id> or --chat-id, not both", "")
+			case messageID == "" && chatID == "":
+				return usageError(cmd, "message-id or --chat-id is required", `Examples:
+  lark messages download <MESSAGE_ID> --out-dir ./downloads
+  lark messages download --chat-id <CHAT_ID> --since -7d`)
+			case chatID != "" && strings.TrimSpace(since) == "":
+				return usageError(cmd, "--since is required with --chat-id", "")
+			case messageID != "" && (strings.TrimSpace(since) != "" || strings.TrimSpace(until) != ""):
+				return usageError(cmd, "--since/--until require --chat-id", "")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch resourceType {
+			case "", "image", "file":
+			default:
+				return flagUsage(cmd, "type must be image or file")
+			}
+			now := time.Now()
+			startTime, err := parseTimeArg(since, now)
+			if err != nil {
+				return flagUsage(cmd, err.Error())
+			}
+			endTime, err := parseTimeArg(until, now)
+			if err != nil {
+				return flagUsage(cmd, err.Error())
+			}
+			if _, err := requireSDK(state); err != nil {
+				return err
+			}
+			token, tokenType, err := resolveAccessToken(cmd.Context(), state, tokenTypesTenantOrUser, nil)
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			var messages []larksdk.Message
+			if messageID != "" {
+				message, err := state.SDK.GetMessageWithToken(ctx, token, larksdk.AccessTokenType(tokenType), messageID, "")
+				if err != nil {
+					return err
+				}
+				messages = append(messages, message)
+			} else {
+				messages, err = listAllMessages(ctx, state, token, larksdk.ListMessagesRequest{ContainerIDType: "chat", ContainerID: chatID, StartTime: startTime, EndTime: endTime})
+				if err != nil {
+					return err
+				}
+			}
+
+			resources := make([]messageResource, 0)
+			for _, message := range messages {
+				for _, resource := range messageResources(message) {
+					if resourceType == "" || resource.Type == resourceType {
+						resources = append(resources, resource)
+					}
+				}
+			}
+			if len(resources) > 0 {
+				if err := os.MkdirAll(outDir, 0o755); err != nil {
+					return err
+				}
+			}
+			used := map[string]string{}
+			failed := 0
+			for i := range resources {
+				if err := ctx.Err(); err != nil {
+					state.interrupted = fmt.Errorf("interrupted: %w", err)
+					resources = resources[:i]
+					break
+				}
+				resource := &resources[i]
+				if err := downloadMessageResource(ctx, state, token, larksdk.AccessTokenType(tokenType), outDir, used, resource); err != nil {
+					resource.Error = err.Error()
+					failed++
+				}
+			}
+
+			payload := map[string]any{"resources": resources}
+			lines := make([]string, 0, len(resources))
+			for _, resource := range resources {
+				result := resource.Path
+				if resource.Error != "" {
+					result = "error: " + resource.Error
+				}
+				lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s", resource.MessageID, resource.Type, resource.Key, result))
+			}
+			if err := state.Printer.Print(payload, tableText([]string{"message_id", "type", "key", "path"}, lines, "no images or files found")); err != nil {
+				return err
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d downloads failed", failed, len(resources))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&outDir, "out-dir", ".", "directory to save files into")
+	cmd.Flags().StringVar(&chatID, "chat-id", "", "download from every message in this chat (requires --since)")
+	cmd.Flags().StringVar(&since, "since", "", "with --chat-id: start time (unix seconds, RFC3339, or relative like -7d)")
+	cmd.Flags().StringVar(&until, "until", "", "with --chat-id: end time (unix seconds, RFC3339, or relative like -1h)")
+	cmd.Flags().StringVar(&resourceType, "type", "", "only download image or file resources")
+	return cmd
+}
+
+// listAllMessages returns every message in a chat or thread, oldest first.
+func listAllMessages(ctx context.Context, state *appState, token string, req larksdk.ListMessagesRequest) ([]larksdk.Message, error) {
+	req.SortType = "ByCreateTimeAsc"
+	req.PageSize = maxMessagesPageSize
+	pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.Message], error) {
+		pageReq := req
+		pageReq.PageToken = pageToken
+		result, err := state.SDK.ListMessages(ctx, token, pageReq)
+		return larksdk.Page[larksdk.Message]{Items: result.Items, PageToken: result.PageToken, HasMore: result.HasMore}, err
+	})
+	var messages []larksdk.Message
+	for !pager.Done() {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, page...)
+		debugf(state, "fetched page %d (%d messages)\n", pager.Pages(), len(messages))
+	}
+	return messages, nil
+}
+
+// messageResources finds every image_key and file_key in a message's content.
+// Stickers are skipped because the resources API does not serve them.
+func messageResources(message larksdk.Message) []messageResource {
+	if message.Deleted || message.MsgType == "sticker" || strings.TrimSpace(message.Body.Content) == "" {
+		return nil
+	}
+	var content any
+	if err := json.Unmarshal([]byte(message.Body.Content), &content); err != nil {
+		return nil
+	}
+	var resources []messageResource
+	seen := map[string]bool{}
+	add := func(key, resourceType, name string) {
+		if key == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		resources = append(resources, messageResource{MessageID: message.MessageID, Key: key, Type: resourceType, name: name})
+	}
+	var walk func(value any)
+	walk = func(value any) {
+		switch v := value.(type) {
+		case map[string]any:
+			name, _ := v["file_name"].(string)
+			if key, ok := v["file_key"].(string); ok {
+				add(key, "file", name)
+			}
+			if key, ok := v["image_key"].(string); ok {
+				add(key, "image", "")
+			}
+			keys := make([]string, 0, len(v))
+			for key := range v {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				walk(v[key])
+			}
+		case []any:
+			for _, item := range v {
+				walk(item)
+			}
+		}
+	}
+	walk(content)
+	return resources
+}
+
+// downloadMessageResource saves one resource into outDir. Names come from the
+// message content or the download, falling back to the key plus an extension
+// sniffed from the data; a name already used by another resource in this run
+// is prefixed with the message ID.
+func downloadMessageResource(ctx context.Context, state *appState, token string, tokenType larksdk.AccessTokenType, outDir string, used map[string]string, resource *messageResource) error {
+	download, err := state.SDK.DownloadMessageResource(ctx, token, tokenType, resource.MessageID, resource.Key, resource.Type)
+	if err != nil {
+		return err
+	}
+	defer download.Reader.Close()
+	reader := bufio.NewReader(download.Reader)
+
+	name := safeResourceName(resource.name)
+	if name == "" {
+		name = safeResourceName(download.FileName)
+	}
+	if name == "" {
+		head, _ := reader.Peek(512)
+		name = resource.Key + sniffedExtension(head)
+	}
+	if owner, ok := used[name]; ok && owner != resource.Key {
+		name = resource.MessageID + "_" + name
+	}
+	used[name] = resource.Key
+
+	path := filepath.Join(outDir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	written, err := io.Copy(file, reader)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+	resource.Path = path
+	resource.Bytes = written
+	return nil
+}
+
+func safeResourceName(name string) string {
+	name = filepath.Base(strings.TrimSpace(name))
+	if name == "." || name == ".." || name == string(filepath.Separator) {
+		return ""
+	}
+	return name
+}
+
+func sniffedExtension(head []byte) string {
+	if len(head) == 0 {
+		return ""
+	}
+	switch http.DetectContentType(head) {
+	case "image/png":
+		return ".png"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	case "image/bmp":
+		return ".bmp"
+	case "application/pdf":
+		return ".pdf"
+	case "video/mp4":
+		return ".mp4"
+	}
+	return ""
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"lark/internal/larksdk"
+)
+
+func TestMessageResourcesFindsPostImagesAndFiles(t *testing.T) {
+	post := larksdk.Message{MessageID: "om_1", MsgType: "post", Body: larksdk.MessageBody{Content: `{"title":"t","content":[[{"tag":"text","text":"see"},{"tag":"img","image_key":"img_a"}],[{"tag":"media","file_key":"file_v","image_key":"img_cover"}]]}`}}
+	got := messageResources(post)
+	keys := make([]string, 0, len(got))
+	for _, resource := range got {
+		keys = append(keys, resource.Type+":"+resource.Key)
+	}
+	if strings.Join(keys, ",") != "image:img_a,file:file_v,image:img_cover" {
+		t.Fatalf("unexpected resources: %v", keys)
+	}
+	if len(messageResources(larksdk.Message{MessageID: "om_2", MsgType: "sticker", Body: larksdk.MessageBody{Content: `{"file_key":"sticker"}`}})) != 0 {
+		t.Fatalf("stickers should be skipped")
+	}
+}
+
+func TestMsgDownloadSinceSavesResources(t *testing.T) {
+	outDir := filepath.Join(t.TempDir(), "out")
+	var startTime string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/open-apis/im/v1/messages":
+			startTime = r.URL.Query().Get("start_time")
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"has_more": false,
+				"items": []map[string]any{
+					{"message_id": "om_1", "msg_type": "file", "body": map[string]any{"content": `{"file_key":"file_1","file_name":"report.pdf"}`}},
+					{"message_id": "om_2", "msg_type": "image", "body": map[string]any{"content": `{"image_key":"img_2"}`}},
+					{"message_id": "om_3", "msg_type": "text", "body": map[string]any{"content": `{"text":"hi"}`}},
+				},
+			}})
+		case r.URL.Path == "/open-apis/im/v1/messages/om_1/resources/file_1" && r.URL.Query().Get("type") == "file":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write([]byte("pdf-bytes"))
+		case r.URL.Path == "/open-apis/im/v1/messages/om_2/resources/img_2" && r.URL.Query().Get("type") == "image":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("\x89PNG\r\n\x1a\n0000"))
+		default:
+			t.Fatalf("unexpected request: %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"download", "--chat-id", "oc_1", "--since", "1700000000", "--out-dir", outDir})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("download error: %v", err)
+	}
+	if startTime != "1700000000" {
+		t.Fatalf("unexpected start_time: %q", startTime)
+	}
+	if data, err := os.ReadFile(filepath.Join(outDir, "report.pdf")); err != nil || string(data) != "pdf-bytes" {
+		t.Fatalf("report.pdf: %q %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "img_2.png")); err != nil {
+		t.Fatalf("img_2.png: %v", err)
+	}
+	var payload struct {
+		Resources []messageResource `json:"resources"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(payload.Resources) != 2 || payload.Resources[0].Bytes != 9 {
+		t.Fatalf("unexpected payload: %+v", payload.Resources)
+	}
+}
+
+func TestMsgDownloadRequiresSinceForChat(t *testing.T) {
+	state, _ := newTestState(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s", r.URL.Path)
+	}), true)
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"download", "--chat-id", "oc_1"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--since") {
+		t.Fatalf("expected --since error, got %v", err)
+	}
+}
@@ -0,0 +1,460 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+const (
+	messageExportCheckpointName = ".lark-export.json"
+	messageExportDataName       = "messages.ndjson"
+	messageExportAttachmentsDir = "attachments"
+)
+
+var messageExportFormats = []string{"ndjson", "html", "markdown"}
+
+// messageExportCheckpoint records how far an export got. messages.ndjson is
+// truncated back to Offset on resume so a page interrupted half-way is
+// fetched and written again rather than duplicated.
+type messageExportCheckpoint struct {
+	ChatID      string            `json:"chat_id"`
+	StartTime   string            `json:"start_time,omitempty"`
+	EndTime     string            `json:"end_time,omitempty"`
+	PageToken   string            `json:"page_token,omitempty"`
+	Pages       int               `json:"pages"`
+	Messages    int               `json:"messages"`
+	Replies     int               `json:"replies"`
+	Attachments int               `json:"attachments"`
+	Offset      int64             `json:"offset"`
+	SenderNames map[string]string `json:"sender_names,omitempty"`
+	Done        bool              `json:"done"`
+	UpdatedAt   string            `json:"updated_at"`
+}
+
+// exportedMessage is one line of messages.ndjson. Thread replies follow their
+// root message and have ThreadReply set.
+type exportedMessage struct {
+	larksdk.Message
+	SenderName  string            `json:"sender_name,omitempty"`
+	ThreadReply bool              `json:"thread_reply,omitempty"`
+	Attachments []messageResource `json:"attachments,omitempty"`
+}
+
+// messageExportOptions holds the parsed flags. startFixed and endFixed are set
+// when --since/--until name an absolute time, which a resumed export must
+// match.
+type messageExportOptions struct {
+	chatID      string
+	outDir      string
+	format      string
+	startTime   string
+	endTime     string
+	startFixed  bool
+	endFixed    bool
+	threads     bool
+	attachments bool
+}
+
+func newMsgExportCmd(state *appState) *cobra.Command {
+	var opts messageExportOptions
+	var since string
+	var until string
+	var noThreads bool
+	var noAttachments bool
+	var restart bool
+
+	cmd := &cobra.Command{
+		Use:   "export <chat-id>",
+		Short: "Archive a chat's history to NDJSON, HTML, or Markdown",
+		Long: `Export every message in a chat, with sender names, thread replies, and
+downloaded attachments, into --out.
+
+Messages are written to messages.ndjson page by page, and progress is saved in
+.lark-export.json. Re-running the same command resumes where it stopped; use
+--restart to start over. With --format html or markdown, a transcript is
+rendered from messages.ndjson once all pages are fetched.`,
+		Example: `  lark messages export <CHAT_ID> --out ./archive --format html
+  lark messages export <CHAT_ID> --out ./archive --since 2025-01-01T00:00:00Z --no-attachments`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			opts.chatID = strings.TrimSpace(args[0])
+			if opts.chatID == "" {
+				return argsUsageError(cmd, errors.New("chat-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.format = strings.ToLower(strings.TrimSpace(opts.format))
+			if opts.format == "md" {
+				opts.format = "markdown"
+			}
+			if !containsString(messageExportFormats, opts.format) {
+				return flagUsage(cmd, "format must be one of ndjson, html, markdown")
+			}
+			opts.outDir = strings.TrimSpace(opts.outDir)
+			if opts.outDir == "" {
+				opts.outDir = opts.chatID + "-export"
+			}
+			now := time.Now()
+			var err error
+			if opts.startTime, err = parseTimeArg(since, now); err != nil {
+				return flagUsage(cmd, err.Error())
+			}
+			if opts.endTime, err = parseTimeArg(until, now); err != nil {
+				return flagUsage(cmd, err.Error())
+			}
+			opts.startFixed = opts.startTime != "" && !isRelativeTimeArg(since)
+			opts.endFixed = opts.endTime != "" && !isRelativeTimeArg(until)
+			opts.threads = !noThreads
+			opts.attachments = !noAttachments
+			if _, err := requireSDK(state); err != nil {
+				return err
+			}
+			token, tokenType, err := resolveAccessToken(cmd.Context(), state, tokenTypesTenantOrUser, nil)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(opts.outDir, 0o755); err != nil {
+				return err
+			}
+			checkpoint, resumed, err := loadMessageExportCheckpoint(opts, restart)
+			if err != nil {
+				return err
+			}
+			if resumed {
+				fmt.Fprintf(errWriter(state), "resuming export after %d messages (%d pages)\n", checkpoint.Messages, checkpoint.Pages)
+			}
+			if !checkpoint.Done {
+				if err := fetchMessageExport(cmd.Context(), state, token, tokenType, opts, checkpoint); err != nil {
+					return err
+				}
+				if !checkpoint.Done {
+					return nil
+				}
+			}
+
+			outPath := filepath.Join(opts.outDir, messageExportDataName)
+			switch opts.format {
+			case "html":
+				outPath = filepath.Join(opts.outDir, "transcript.html")
+				err = renderMessageExport(opts, outPath, writeMessageExportHTML)
+			case "markdown":
+				outPath = filepath.Join(opts.outDir, "transcript.md")
+				err = renderMessageExport(opts, outPath, writeMessageExportMarkdown)
+			}
+			if err != nil {
+				return err
+			}
+			payload := map[string]any{
+				"chat_id":     opts.chatID,
+				"format":      opts.format,
+				"output":      outPath,
+				"messages":    checkpoint.Messages,
+				"replies":     checkpoint.Replies,
+				"attachments": checkpoint.Attachments,
+				"resumed":     resumed,
+			}
+			text := fmt.Sprintf("exported %d messages (%d thread replies, %d attachments) to %s", checkpoint.Messages, checkpoint.Replies, checkpoint.Attachments, outPath)
+			return state.Printer.Print(payload, text)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.outDir, "out", "", "output directory (default: <chat-id>-export)")
+	cmd.Flags().StringVar(&opts.format, "format", "ndjson", "output format (ndjson, html, markdown)")
+	cmd.Flags().StringVar(&since, "since", "", "only messages after this time (unix seconds, RFC3339, or relative like -30d)")
+	cmd.Flags().StringVar(&until, "until", "", "only messages before this time (unix seconds, RFC3339, or relative like -1d)")
+	cmd.Flags().BoolVar(&noThreads, "no-threads", false, "skip thread replies")
+	cmd.Flags().BoolVar(&noAttachments, "no-attachments", false, "skip downloading images and files")
+	cmd.Flags().BoolVar(&restart, "restart", false, "ignore any saved checkpoint and export from the beginning")
+	registerEnumCompletion(cmd, "format", messageExportFormats)
+	return cmd
+}
+
+func loadMessageExportCheckpoint(opts messageExportOptions, restart bool) (*messageExportCheckpoint, bool, error) {
+	fresh := &messageExportCheckpoint{ChatID: opts.chatID, StartTime: opts.startTime, EndTime: opts.endTime, SenderNames: map[string]string{}}
+	path := filepath.Join(opts.outDir, messageExportCheckpointName)
+	if restart {
+		return fresh, false, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fresh, false, nil
+		}
+		return nil, false, err
+	}
+	var saved messageExportCheckpoint
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, false, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if saved.ChatID != opts.chatID {
+		return nil, false, fmt.Errorf("%s holds an export of %s; use another --out or --restart", opts.outDir, saved.ChatID)
+	}
+	if (opts.startFixed && opts.startTime != saved.StartTime) || (opts.endFixed && opts.endTime != saved.EndTime) {
+		return nil, false, fmt.Errorf("%s holds an export with a different --since/--until window; use --restart to export the new window", opts.outDir)
+	}
+	if saved.SenderNames == nil {
+		saved.SenderNames = map[string]string{}
+	}
+	return &saved, true, nil
+}
+
+// isRelativeTimeArg reports whether parseTimeArg resolves raw against the
+// current time.
+func isRelativeTimeArg(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	return strings.HasPrefix(raw, "-") || strings.HasPrefix(raw, "+")
+}
+
+func saveMessageExportCheckpoint(outDir string, checkpoint *messageExportCheckpoint) error {
+	checkpoint.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(outDir, messageExportCheckpointName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// fetchMessageExport pages through the chat, appending each page (with its
+// thread replies) to messages.ndjson and saving the checkpoint after every
+// page. An interrupt stops after the current page is saved.
+func fetchMessageExport(ctx context.Context, state *appState, token string, tokenType tokenType, opts messageExportOptions, checkpoint *messageExportCheckpoint) error {
+	dataPath := filepath.Join(opts.outDir, messageExportDataName)
+	file, err := os.OpenFile(dataPath, os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := file.Truncate(checkpoint.Offset); err != nil {
+		return err
+	}
+	if _, err := file.Seek(checkpoint.Offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	resumeToken := checkpoint.PageToken
+	pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.Message], error) {
+		if pageToken == "" {
+			pageToken = resumeToken
+		}
+		// Relative --since values resolve to a new time on every run; keep the
+		// window of the original run so the page token stays valid.
+		result, err := state.SDK.ListMessages(ctx, token, larksdk.ListMessagesRequest{
+			ContainerIDType: "chat",
+			ContainerID:     opts.chatID,
+			StartTime:       checkpoint.StartTime,
+			EndTime:         checkpoint.EndTime,
+			SortType:        "ByCreateTimeAsc",
+			PageSize:        maxMessagesPageSize,
+			PageToken:       pageToken,
+		})
+		return larksdk.Page[larksdk.Message]{Items: result.Items, PageToken: result.PageToken, HasMore: result.HasMore}, err
+	})
+	progress := newPageProgress(state)
+	defer progress.clear()
+	interrupt := func() error {
+		progress.clear()
+		fmt.Fprintf(errWriter(state), "WARNING: interrupted after %d messages; re-run the same command to resume\n", checkpoint.Messages)
+		state.interrupted = fmt.Errorf("interrupted: %w", ctx.Err())
+		return nil
+	}
+	for !pager.Done() {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return interrupt()
+			}
+			return err
+		}
+		messages, replies, attachments := checkpoint.Messages, checkpoint.Replies, checkpoint.Attachments
+		records, err := buildExportRecords(ctx, state, token, tokenType, opts, checkpoint, page)
+		if err != nil {
+			if ctx.Err() != nil {
+				// The page is fetched again on resume, so drop its counts.
+				checkpoint.Messages, checkpoint.Replies, checkpoint.Attachments = messages, replies, attachments
+				return interrupt()
+			}
+			return err
+		}
+		writer := bufio.NewWriter(file)
+		encoder := json.NewEncoder(writer)
+		encoder.SetEscapeHTML(false)
+		for _, record := range records {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		if err := file.Sync(); err != nil {
+			return err
+		}
+		offset, err := file.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		checkpoint.Offset = offset
+		checkpoint.Pages++
+		checkpoint.PageToken = pager.PageToken()
+		checkpoint.Done = pager.Done()
+		if err := saveMessageExportCheckpoint(opts.outDir, checkpoint); err != nil {
+			return err
+		}
+		progress.update(checkpoint.Pages, checkpoint.Messages)
+	}
+	if !checkpoint.Done {
+		checkpoint.Done = true
+		return saveMessageExportCheckpoint(opts.outDir, checkpoint)
+	}
+	return nil
+}
+
+// buildExportRecords expands one page of chat messages with thread replies,
+// sender names, and downloaded attachments.
+func buildExportRecords(ctx context.Context, state *appState, token string, tokenType tokenType, opts messageExportOptions, checkpoint *messageExportCheckpoint, page []larksdk.Message) ([]exportedMessage, error) {
+	records := make([]exportedMessage, 0, len(page))
+	for _, message := range page {
+		records = append(records, exportedMessage{Message: message})
+		checkpoint.Messages++
+		if !opts.threads || message.ThreadID == "" {
+			continue
+		}
+		replies, err := listAllMessages(ctx, state, token, larksdk.ListMessagesRequest{ContainerIDType: "thread", ContainerID: message.ThreadID})
+		if err != nil {
+			return nil, fmt.Errorf("thread %s: %w", message.ThreadID, err)
+		}
+		for _, reply := range replies {
+			if reply.MessageID == message.MessageID {
+				continue
+			}
+			records = append(records, exportedMessage{Message: reply, ThreadReply: true})
+			checkpoint.Replies++
+		}
+	}
+
+	if tokenType == tokenTypeTenant {
+		unknown := make([]larksdk.Message, 0)
+		for _, record := range records {
+			if key, ok := exportSenderKey(record.Message); ok {
+				if _, known := checkpoint.SenderNames[key]; !known {
+					unknown = append(unknown, record.Message)
+				}
+			}
+		}
+		names := resolveMessageSenderNames(ctx, state, token, unknown)
+		for _, message := range unknown {
+			// Senders that could not be looked up are cached as "" so later
+			// pages do not ask again.
+			key, _ := exportSenderKey(message)
+			checkpoint.SenderNames[key] = names[key]
+		}
+	}
+	for i := range records {
+		name, _ := messageSenderDisplay(records[i].Message, checkpoint.SenderNames)
+		records[i].SenderName = name
+		if !opts.attachments {
+			continue
+		}
+		resources := messageResources(records[i].Message)
+		if len(resources) == 0 {
+			continue
+		}
+		dir := filepath.Join(opts.outDir, messageExportAttachmentsDir, records[i].MessageID)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+		used := map[string]string{}
+		for j := range resources {
+			resource := &resources[j]
+			if err := downloadMessageResource(ctx, state, token, larksdk.AccessTokenType(tokenType), dir, used, resource); err != nil {
+				if ctx.Err() != nil {
+					return nil, err
+				}
+				resource.Error = err.Error()
+				fmt.Fprintf(errWriter(state), "WARNING: %s %s: %v\n", resource.MessageID, resource.Key, err)
+				continue
+			}
+			if rel, err := filepath.Rel(opts.outDir, resource.Path); err == nil {
+				resource.Path = filepath.ToSlash(rel)
+			}
+			checkpoint.Attachments++
+		}
+		records[i].Attachments = resources
+	}
+	return records, nil
+}
+
+func exportSenderKey(message larksdk.Message) (string, bool) {
+	sender := message.Sender
+	id := strings.TrimSpace(sender.ID)
+	senderType := strings.TrimSpace(sender.SenderType)
+	if id == "" || (senderType != "" && senderType != "user") {
+		return "", false
+	}
+	idType := strings.TrimSpace(sender.IDType)
+	if idType == "" {
+		idType = "user_id"
+	}
+	return messageSenderKey("user", idType, id), true
+}
+
+// renderMessageExport streams messages.ndjson through write into outPath.
+func renderMessageExport(opts messageExportOptions, outPath string, write func(io.Writer, string, string, func(func(exportedMessage) error) error) error) error {
+	in, err := os.Open(filepath.Join(opts.outDir, messageExportDataName))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	each := func(fn func(exportedMessage) error) error {
+		scanner := bufio.NewScanner(in)
+		scanner.Buffer(make([]byte, 0, 64*1024), 64<<20)
+		for scanner.Scan() {
+			var record exportedMessage
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				return fmt.Errorf("parse %s: %w", messageExportDataName, err)
+			}
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+		return scanner.Err()
+	}
+	tmp := outPath + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	buffered := bufio.NewWriter(out)
+	err = write(buffered, opts.chatID, opts.outDir, each)
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, outPath)
+}
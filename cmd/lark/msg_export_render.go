@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// exportMessageText renders a message body as plain text for transcripts.
+// Attachments are listed separately, so media messages render as a label.
+func exportMessageText(message exportedMessage) string {
+	if message.Deleted {
+		return "(deleted)"
+	}
+	raw := strings.TrimSpace(message.Body.Content)
+	switch message.MsgType {
+	case "post":
+		if text, ok := postContentText(raw); ok {
+			return text
+		}
+	case "image":
+		return "[image]"
+	case "file", "audio", "media":
+		var payload struct {
+			FileName string `json:"file_name"`
+		}
+		_ = json.Unmarshal([]byte(raw), &payload)
+		if payload.FileName != "" {
+			return fmt.Sprintf("[%s: %s]", message.MsgType, payload.FileName)
+		}
+		return "[" + message.MsgType + "]"
+	case "sticker":
+		return "[sticker]"
+	case "interactive":
+		return "[card]"
+	}
+	return messageContentForDisplay(message.Message)
+}
+
+// postContentText flattens a post body, which arrives either keyed by
+// language or (from the messages API) as a single {title, content} object.
+func postContentText(raw string) (string, bool) {
+	type postBody struct {
+		Title   string             `json:"title"`
+		Content [][]map[string]any `json:"content"`
+	}
+	var body postBody
+	if err := json.Unmarshal([]byte(raw), &body); err != nil {
+		return "", false
+	}
+	if body.Content == nil {
+		var localized map[string]postBody
+		if err := json.Unmarshal([]byte(raw), &localized); err != nil {
+			return "", false
+		}
+		for _, lang := range postLanguages {
+			if candidate, ok := localized[lang]; ok {
+				body = candidate
+				break
+			}
+		}
+		if body.Content == nil {
+			for _, candidate := range localized {
+				body = candidate
+				break
+			}
+		}
+	}
+	lines := make([]string, 0, len(body.Content)+1)
+	if title := strings.TrimSpace(body.Title); title != "" {
+		lines = append(lines, title)
+	}
+	for _, paragraph := range body.Content {
+		var line strings.Builder
+		for _, element := range paragraph {
+			tag, _ := element["tag"].(string)
+			text, _ := element["text"].(string)
+			switch tag {
+			case "text", "code_block":
+				line.WriteString(text)
+			case "a":
+				href, _ := element["href"].(string)
+				if text == "" {
+					text = href
+				}
+				line.WriteString(text)
+			case "at":
+				name, _ := element["user_name"].(string)
+				if name == "" {
+					name, _ = element["user_id"].(string)
+				}
+				line.WriteString("@" + name)
+			case "emotion":
+				emoji, _ := element["emoji_type"].(string)
+				line.WriteString(":" + emoji + ":")
+			case "img":
+				line.WriteString("[image]")
+			case "media":
+				line.WriteString("[video]")
+			case "hr":
+				line.WriteString("---")
+			}
+		}
+		lines = append(lines, line.String())
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), true
+}
+
+func exportMessageHeader(message exportedMessage) string {
+	name := message.SenderName
+	if name == "" {
+		name, _ = messageSenderDisplay(message.Message, nil)
+	}
+	if created := formatMessageTime(message.CreateTime); created != "" {
+		return name + " · " + created
+	}
+	return name
+}
+
+func writeMessageExportMarkdown(w io.Writer, chatID, _ string, each func(func(exportedMessage) error) error) error {
+	if _, err := fmt.Fprintf(w, "# Chat %s\n", chatID); err != nil {
+		return err
+	}
+	return each(func(message exportedMessage) error {
+		var b strings.Builder
+		prefix := ""
+		if message.ThreadReply {
+			prefix = "> "
+		}
+		b.WriteString("\n")
+		if message.ThreadReply {
+			fmt.Fprintf(&b, "> **%s**\n>\n", exportMessageHeader(message))
+		} else {
+			fmt.Fprintf(&b, "### %s\n\n", exportMessageHeader(message))
+		}
+		for _, line := range strings.Split(exportMessageText(message), "\n") {
+			b.WriteString(strings.TrimRight(prefix+line, " ") + "\n")
+		}
+		for _, attachment := range message.Attachments {
+			if attachment.Error != "" || attachment.Path == "" {
+				continue
+			}
+			name := filepath.Base(attachment.Path)
+			if attachment.Type == "image" {
+				fmt.Fprintf(&b, "%s\n%s![%s](%s)\n", strings.TrimRight(prefix, " "), prefix, name, attachment.Path)
+			} else {
+				fmt.Fprintf(&b, "%s\n%s[%s](%s)\n", strings.TrimRight(prefix, " "), prefix, name, attachment.Path)
+			}
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	})
+}
+
+type exportHTMLAttachment struct {
+	Name  string
+	Href  string
+	Image template.URL
+}
+
+type exportHTMLMessage struct {
+	Header      string
+	Text        string
+	Reply       bool
+	Attachments []exportHTMLAttachment
+}
+
+var exportHTMLHead = template.Must(template.New("head").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Chat {{.}}</title>
+<style>
+body{font-family:-apple-system,"Segoe UI",sans-serif;max-width:860px;margin:2em auto;padding:0 1em;color:#1f2329}
+.msg{border-bottom:1px solid #e5e6eb;padding:.6em 0}
+.reply{margin-left:2em;padding-left:.8em;border-left:3px solid #c9cdd4;border-bottom:none}
+.meta{color:#646a73;font-size:.85em}
+.text{white-space:pre-wrap;margin-top:.2em}
+img{max-width:100%;max-height:480px;display:block;margin-top:.4em}
+</style></head><body>
+<h1>Chat {{.}}</h1>
+`))
+
+var exportHTMLMessageTemplate = template.Must(template.New("message").Parse(`<div class="msg{{if .Reply}} reply{{end}}"><div class="meta">{{.Header}}</div><div class="text">{{.Text}}</div>{{range .Attachments}}{{if .Image}}<img src="{{.Image}}" alt="{{.Name}}">{{else}}<div><a href="{{.Href}}">{{.Name}}</a></div>{{end}}{{end}}</div>
+`))
+
+// writeMessageExportHTML writes a single self-contained page: images are
+// embedded as data URIs and other files are linked relative to the export.
+func writeMessageExportHTML(w io.Writer, chatID, outDir string, each func(func(exportedMessage) error) error) error {
+	if err := exportHTMLHead.Execute(w, chatID); err != nil {
+		return err
+	}
+	err := each(func(message exportedMessage) error {
+		view := exportHTMLMessage{Header: exportMessageHeader(message), Text: exportMessageText(message), Reply: message.ThreadReply}
+		for _, attachment := range message.Attachments {
+			if attachment.Error != "" || attachment.Path == "" {
+				continue
+			}
+			item := exportHTMLAttachment{Name: filepath.Base(attachment.Path), Href: attachment.Path}
+			if attachment.Type == "image" {
+				item.Image = exportImageDataURI(filepath.Join(outDir, filepath.FromSlash(attachment.Path)))
+			}
+			view.Attachments = append(view.Attachments, item)
+		}
+		return exportHTMLMessageTemplate.Execute(w, view)
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "</body></html>\n")
+	return err
+}
+
+func exportImageDataURI(path string) template.URL {
+	if path == "" {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	contentType := http.DetectContentType(data)
+	if !strings.HasPrefix(contentType, "image/") {
+		return ""
+	}
+	return template.URL("data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data))
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMsgExportResumesFromCheckpoint(t *testing.T) {
+	outDir := filepath.Join(t.TempDir(), "archive")
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	first := `{"message_id":"om_1","msg_type":"text","create_time":"1700000000000","body":{"content":"{\"text\":\"first\"}"},"sender_name":"Alice"}` + "\n"
+	// A partial line after the checkpointed offset must be dropped on resume.
+	if err := os.WriteFile(filepath.Join(outDir, messageExportDataName), []byte(first+`{"message_id":"om_partial`), 0o644); err != nil {
+		t.Fatalf("write data: %v", err)
+	}
+	checkpoint := messageExportCheckpoint{ChatID: "oc_1", PageToken: "p2", Pages: 1, Messages: 1, Offset: int64(len(first)), SenderNames: map[string]string{"user:open_id:ou_1": "Alice"}}
+	raw, _ := json.Marshal(checkpoint)
+	if err := os.WriteFile(filepath.Join(outDir, messageExportCheckpointName), raw, 0o644); err != nil {
+		t.Fatalf("write checkpoint: %v", err)
+	}
+
+	lookups := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query()
+		switch {
+		case r.URL.Path == "/open-apis/im/v1/messages" && query.Get("container_id_type") == "chat":
+			if query.Get("page_token") != "p2" {
+				t.Fatalf("expected resume from p2, got %q", query.Get("page_token"))
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+				{"message_id": "om_2", "thread_id": "omt_1", "msg_type": "image", "create_time": "1700000060000", "sender": map[string]any{"id": "ou_2", "id_type": "open_id", "sender_type": "user"}, "body": map[string]any{"content": `{"image_key":"img_2"}`}},
+			}}})
+		case r.URL.Path == "/open-apis/im/v1/messages" && query.Get("container_id_type") == "thread":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+				{"message_id": "om_2", "thread_id": "omt_1", "msg_type": "image"},
+				{"message_id": "om_3", "thread_id": "omt_1", "msg_type": "text", "sender": map[string]any{"id": "ou_1", "id_type": "open_id", "sender_type": "user"}, "body": map[string]any{"content": `{"text":"nice <b>shot</b>"}`}},
+			}}})
+		case r.URL.Path == "/open-apis/contact/v3/users/ou_2":
+			lookups++
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"user": map[string]any{"open_id": "ou_2", "name": "Bob"}}})
+		case r.URL.Path == "/open-apis/im/v1/messages/om_2/resources/img_2":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("\x89PNG\r\n\x1a\n0000"))
+		default:
+			t.Fatalf("unexpected request: %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"export", "oc_1", "--out", outDir, "--format", "html"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("export error: %v", err)
+	}
+	if lookups != 1 {
+		t.Fatalf("expected one sender lookup, got %d", lookups)
+	}
+
+	data, err := os.ReadFile(filepath.Join(outDir, messageExportDataName))
+	if err != nil {
+		t.Fatalf("read data: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 || strings.Contains(string(data), "om_partial") {
+		t.Fatalf("unexpected ndjson:\n%s", data)
+	}
+	var reply exportedMessage
+	if err := json.Unmarshal([]byte(lines[2]), &reply); err != nil || !reply.ThreadReply || reply.SenderName != "Alice" {
+		t.Fatalf("unexpected reply record: %s", lines[2])
+	}
+	var image exportedMessage
+	if err := json.Unmarshal([]byte(lines[1]), &image); err != nil || len(image.Attachments) != 1 || image.Attachments[0].Path != "attachments/om_2/img_2.png" {
+		t.Fatalf("unexpected image record: %s", lines[1])
+	}
+
+	html, err := os.ReadFile(filepath.Join(outDir, "transcript.html"))
+	if err != nil {
+		t.Fatalf("read transcript: %v", err)
+	}
+	for _, want := range []string{"Bob · ", `src="data:image/png;base64,`, `class="msg reply"`, "nice &lt;b&gt;shot&lt;/b&gt;"} {
+		if !strings.Contains(string(html), want) {
+			t.Fatalf("missing %q in transcript:\n%s", want, html)
+		}
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if payload["messages"] != float64(2) || payload["replies"] != float64(1) || payload["resumed"] != true {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+	saved, _ := os.ReadFile(filepath.Join(outDir, messageExportCheckpointName))
+	if !strings.Contains(string(saved), `"done": true`) {
+		t.Fatalf("checkpoint not finished: %s", saved)
+	}
+}
+
+func TestMsgExportInterruptDuringThreadFetchKeepsCheckpoint(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	outDir := filepath.Join(t.TempDir(), "archive")
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query()
+		switch {
+		case r.URL.Path == "/open-apis/im/v1/messages" && query.Get("container_id_type") == "chat":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": true, "page_token": "p2", "items": []map[string]any{
+				{"message_id": "om_1", "thread_id": "omt_1", "msg_type": "text", "create_time": "1700000000000", "body": map[string]any{"content": `{"text":"hi"}`}},
+			}}})
+		case r.URL.Path == "/open-apis/im/v1/messages" && query.Get("container_id_type") == "thread" && query.Get("page_token") == "":
+			// Simulate Ctrl-C while the thread replies are in flight.
+			cancel()
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": true, "page_token": "t2", "items": []map[string]any{
+				{"message_id": "om_1", "thread_id": "omt_1", "msg_type": "text"},
+			}}})
+		default:
+			t.Fatalf("unexpected request: %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+	var stderr bytes.Buffer
+	state.ErrWriter = &stderr
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"export", "oc_1", "--out", outDir})
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		t.Fatalf("export error: %v", err)
+	}
+	if state.interrupted == nil || !strings.Contains(stderr.String(), "interrupted after 0 messages") {
+		t.Fatalf("expected interrupt warning, got %q", stderr.String())
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+	data, err := os.ReadFile(filepath.Join(outDir, messageExportDataName))
+	if err != nil || len(data) != 0 {
+		t.Fatalf("half-processed page should not be written: %q %v", data, err)
+	}
+	if raw, err := os.ReadFile(filepath.Join(outDir, messageExportCheckpointName)); err == nil {
+		var saved messageExportCheckpoint
+		if err := json.Unmarshal(raw, &saved); err != nil || saved.Messages != 0 || saved.Offset != 0 || saved.PageToken != "" {
+			t.Fatalf("checkpoint should not advance: %s", raw)
+		}
+	}
+}
+
+func TestWriteMessageExportMarkdownQuotesThreadReplies(t *testing.T) {
+	records := []exportedMessage{
+		{SenderName: "Alice"},
+		{SenderName: "Bob", ThreadReply: true},
+	}
+	records[0].MsgType = "post"
+	records[0].Body.Content = `{"title":"Plan","content":[[{"tag":"text","text":"ship "},{"tag":"at","user_name":"Bob"}],[{"tag":"a","text":"doc","href":"https://x"}]]}`
+	records[1].MsgType = "text"
+	records[1].Body.Content = `{"text":"ok\nsoon"}`
+	records[1].Attachments = []messageResource{{Type: "file", Path: "attachments/om_2/a.pdf"}}
+
+	var out strings.Builder
+	err := writeMessageExportMarkdown(&out, "oc_1", "", func(fn func(exportedMessage) error) error {
+		for _, record := range records {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("render error: %v", err)
+	}
+	got := out.String()
+	for _, want := range []string{"# Chat oc_1\n", "### Alice\n\nPlan\nship @Bob\ndoc\n", "> **Bob**\n>\n> ok\n> soon\n>\n> [a.pdf](attachments/om_2/a.pdf)\n"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestMsgExportRejectsResumeWithAnotherWindow(t *testing.T) {
+	outDir := t.TempDir()
+	checkpoint := messageExportCheckpoint{ChatID: "oc_1", StartTime: "1700000000", PageToken: "p2", Pages: 1}
+	raw, _ := json.Marshal(checkpoint)
+	if err := os.WriteFile(filepath.Join(outDir, messageExportCheckpointName), raw, 0o644); err != nil {
+		t.Fatalf("write checkpoint: %v", err)
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s", r.URL.Path)
+	})
+	state, _ := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"export", "oc_1", "--out", outDir, "--since", "1800000000"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--restart") {
+		t.Fatalf("expected window mismatch error, got %v", err)
+	}
+}
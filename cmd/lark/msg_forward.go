@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+func newMsgForwardCmd(state *appState) *cobra.Command {
+	var messageID string
+	var receiveID string
+	var receiveIDType string
+	var uuid string
+
+	cmd := &cobra.Command{
+		Use:   "forward <message-id>",
+		Short: "Forward a message to a chat or user",
+		Example: `  lark messages forward <MESSAGE_ID> --to <CHAT_ID>
+  lark messages forward <MESSAGE_ID> --to ada@example.com --receive-id-type email`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			messageID = strings.TrimSpace(args[0])
+			if messageID == "" {
+				return argsUsageError(cmd, errors.New("message-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			normalizedType, err := forwardReceiver(cmd, receiveID, receiveIDType)
+			if err != nil {
+				return err
+			}
+			return runWithToken(cmd, state, tokenTypesTenantOrUser, nil, func(ctx context.Context, sdk *larksdk.Client, token string, _ tokenType) (any, string, error) {
+				newID, err := sdk.ForwardMessage(ctx, token, larksdk.ForwardMessageRequest{
+					MessageID:     messageID,
+					ReceiveID:     strings.TrimSpace(receiveID),
+					ReceiveIDType: normalizedType,
+					UUID:          strings.TrimSpace(uuid),
+				})
+				if err != nil {
+					return nil, "", err
+				}
+				payload := map[string]any{"message_id": newID}
+				return payload, fmt.Sprintf("message_id: %s", newID), nil
+			})
+		},
+	}
+
+	addForwardFlags(cmd, &receiveID, &receiveIDType, &uuid)
+	return cmd
+}
+
+func newMsgMergeForwardCmd(state *appState) *cobra.Command {
+	var messageIDs []string
+	var receiveID string
+	var receiveIDType string
+	var uuid string
+
+	cmd := &cobra.Command{
+		Use:   "merge-forward <message-id>...",
+		Short: "Forward several messages as one merged chat-history message",
+		Long: `Forward messages from the same chat as a single merged message.
+
+Message IDs the API rejects (recalled, from another chat, or not visible to
+the app) are listed as invalid; the command fails if any are.`,
+		Example: `  lark messages merge-forward <MESSAGE_ID> <MESSAGE_ID> --to <CHAT_ID>`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.MinimumNArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			messageIDs = messageIDs[:0]
+			for _, arg := range args {
+				if id := strings.TrimSpace(arg); id != "" {
+					messageIDs = append(messageIDs, id)
+				}
+			}
+			if len(messageIDs) == 0 {
+				return argsUsageError(cmd, errors.New("message-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			normalizedType, err := forwardReceiver(cmd, receiveID, receiveIDType)
+			if err != nil {
+				return err
+			}
+			var invalid []string
+			err = runWithToken(cmd, state, tokenTypesTenantOrUser, nil, func(ctx context.Context, sdk *larksdk.Client, token string, _ tokenType) (any, string, error) {
+				result, err := sdk.MergeForwardMessage(ctx, token, larksdk.MergeForwardMessageRequest{
+					MessageIDs:    messageIDs,
+					ReceiveID:     strings.TrimSpace(receiveID),
+					ReceiveIDType: normalizedType,
+					UUID:          strings.TrimSpace(uuid),
+				})
+				if err != nil {
+					return nil, "", err
+				}
+				invalid = result.InvalidMessageIDs
+				payload := map[string]any{"message_id": result.Message.MessageID, "invalid_message_ids": result.InvalidMessageIDs}
+				text := fmt.Sprintf("message_id: %s", result.Message.MessageID)
+				if len(invalid) > 0 {
+					text += "\ninvalid: " + strings.Join(invalid, ", ")
+				}
+				return payload, text, nil
+			})
+			if err != nil {
+				return err
+			}
+			if len(invalid) > 0 {
+				return fmt.Errorf("%d of %d messages could not be forwarded", len(invalid), len(messageIDs))
+			}
+			return nil
+		},
+	}
+
+	addForwardFlags(cmd, &receiveID, &receiveIDType, &uuid)
+	return cmd
+}
+
+func addForwardFlags(cmd *cobra.Command, receiveID, receiveIDType, uuid *string) {
+	cmd.Flags().StringVar(receiveID, "to", "", "receive ID to forward to (required)")
+	cmd.Flags().StringVar(receiveIDType, "receive-id-type", "chat_id", "receive ID type (chat_id, open_id, user_id, email)")
+	cmd.Flags().StringVar(uuid, "uuid", "", "request UUID for idempotency")
+	registerEnumCompletion(cmd, "receive-id-type", receiveIDTypeValues)
+}
+
+func forwardReceiver(cmd *cobra.Command, receiveID, receiveIDType string) (string, error) {
+	if strings.TrimSpace(receiveID) == "" {
+		return "", usageError(cmd, "--to is required", "")
+	}
+	normalizedType, ok := normalizeReceiveIDType(receiveIDType)
+	if !ok {
+		return "", flagUsage(cmd, "receive-id-type must be one of chat_id, open_id, user_id, email")
+	}
+	return normalizedType, nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMsgForwardUsesReceiveIDType(t *testing.T) {
+	var query string
+	var body string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/open-apis/im/v1/messages/om_1/forward" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		query = r.URL.Query().Get("receive_id_type")
+		raw, _ := io.ReadAll(r.Body)
+		body = string(raw)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"message_id": "om_fwd"}})
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"forward", "om_1", "--to", "ada@example.com", "--receive-id-type", "email"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("forward error: %v", err)
+	}
+	if query != "email" || !strings.Contains(body, `"receive_id":"ada@example.com"`) {
+		t.Fatalf("unexpected request: %s %s", query, body)
+	}
+	if !strings.Contains(buf.String(), "om_fwd") {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestMsgMergeForwardReportsInvalidIDs(t *testing.T) {
+	var sent struct {
+		ReceiveID     string   `json:"receive_id"`
+		MessageIDList []string `json:"message_id_list"`
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/open-apis/im/v1/messages/merge_forward" || r.URL.Query().Get("receive_id_type") != "chat_id" {
+			t.Fatalf("unexpected request: %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+			"message":                 map[string]any{"message_id": "om_merged", "msg_type": "merge_forward"},
+			"invalid_message_id_list": []string{"om_3"},
+		}})
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"merge-forward", "om_1", "om_2", "om_3", "--to", "oc_1"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "1 of 3 messages") {
+		t.Fatalf("expected partial failure, got %v", err)
+	}
+	if sent.ReceiveID != "oc_1" || strings.Join(sent.MessageIDList, ",") != "om_1,om_2,om_3" {
+		t.Fatalf("unexpected body: %+v", sent)
+	}
+	if !strings.Contains(buf.String(), "om_merged") || !strings.Contains(buf.String(), "om_3") {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+}
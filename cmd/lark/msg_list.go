@@ -25,13 +25,15 @@ func newMsgListCmd(state *appState) *cobra.Command {
 	var startTime string
 	var endTime string
 	var sortType string
-	var limit int
+	var paging listPaging
 	var pageSize int
+	var withThreads bool
 
 	cmd := &cobra.Command{
-		Use:     "list <container-id>",
-		Short:   "List messages in a chat or thread",
-		Example: `  lark messages list <chat_id>`,
+		Use:   "list <container-id>",
+		Short: "List messages in a chat or thread",
+		Example: `  lark messages list <chat_id>
+  lark messages list <chat_id> --with-threads`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
 				return argsUsageError(cmd, err)
@@ -48,12 +50,15 @@ func newMsgListCmd(state *appState) *cobra.Command {
 			return nil
 		},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				return flagUsage(cmd, "limit must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			if pageSize <= 0 {
 				return flagUsage(cmd, "page-size must be greater than 0")
 			}
+			if withThreads && containerIDType != "chat" {
+				return flagUsage(cmd, "--with-threads requires --container-id-type chat")
+			}
 			if _, err := requireSDK(state); err != nil {
 				return err
 			}
@@ -64,53 +69,52 @@ func newMsgListCmd(state *appState) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			messages := make([]larksdk.Message, 0, limit)
-			pageToken := ""
-			remaining := limit
-			for {
-				size := pageSize
-				if size > maxMessagesPageSize {
-					size = maxMessagesPageSize
-				}
-				if size > remaining {
-					size = remaining
-				}
-				result, err := state.SDK.ListMessages(cmd.Context(), token, larksdk.ListMessagesRequest{
+			collected := newListCollector[threadedMessage](state, paging.itemLimit())
+			threads := newThreadNester(state, token)
+			pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[threadedMessage], error) {
+				result, err := state.SDK.ListMessages(ctx, token, larksdk.ListMessagesRequest{
 					ContainerIDType: containerIDType,
 					ContainerID:     containerID,
 					StartTime:       startTime,
 					EndTime:         endTime,
 					SortType:        sortType,
-					PageSize:        size,
+					PageSize:        collected.pageSize(min(pageSize, maxMessagesPageSize)),
 					PageToken:       pageToken,
 				})
+				page := larksdk.Page[threadedMessage]{PageToken: result.PageToken, HasMore: result.HasMore}
 				if err != nil {
-					return err
-				}
-				messages = append(messages, result.Items...)
-				if len(messages) >= limit || !result.HasMore {
-					break
+					return page, err
 				}
-				remaining = limit - len(messages)
-				pageToken = result.PageToken
-				if strings.TrimSpace(pageToken) == "" {
-					break
+				if withThreads {
+					page.Items, err = threads.nest(ctx, result.Items)
+				} else {
+					page.Items = unthreadedMessages(result.Items)
 				}
+				return page, err
+			})
+			if err := drainPages(cmd.Context(), state, collected, pager, 0); err != nil {
+				return err
 			}
-			if len(messages) > limit {
-				messages = messages[:limit]
+			if collected.streaming {
+				return nil
 			}
+			messages := collected.items
 			payload := map[string]any{"messages": messages}
 			text := output.Notice(output.NoticeInfo, "no messages found", nil)
 			if len(messages) > 0 {
 				var senderNames map[string]string
 				if !state.JSON && tokenType == tokenTypeTenant {
-					senderNames = resolveMessageSenderNames(cmd.Context(), state, token, messages)
+					senderNames = resolveMessageSenderNames(cmd.Context(), state, token, flattenThreadedMessages(messages))
 				}
 				styles := newMessageFormatStyles(state.Printer.Styled)
 				displays := make([]messageDisplay, 0, len(messages))
 				for _, message := range messages {
-					displays = append(displays, buildMessageDisplay(message, styles, senderNames))
+					displays = append(displays, buildMessageDisplay(message.Message, styles, senderNames))
+					for _, reply := range message.Replies {
+						display := buildMessageDisplay(reply, styles, senderNames)
+						display.reply = true
+						displays = append(displays, display)
+					}
 				}
 				text = renderMessageTable(displays, styles)
 			}
@@ -122,8 +126,10 @@ func newMsgListCmd(state *appState) *cobra.Command {
 	cmd.Flags().StringVar(&startTime, "start-time", "", "start time (unix seconds)")
 	cmd.Flags().StringVar(&endTime, "end-time", "", "end time (unix seconds)")
 	cmd.Flags().StringVar(&sortType, "sort", "ByCreateTimeAsc", "sort type (ByCreateTimeAsc or ByCreateTimeDesc)")
-	cmd.Flags().IntVar(&limit, "limit", 20, "max number of messages to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 20, "max number of messages to return")
 	cmd.Flags().IntVar(&pageSize, "page-size", 20, "page size per request")
+	cmd.Flags().BoolVar(&withThreads, "with-threads", false, "nest thread replies under their root messages")
+	addPagingFlags(cmd, &paging)
 	return cmd
 }
 
@@ -198,6 +204,8 @@ func (s messageFormatStyles) renderMeta(text string) string {
 type messageDisplay struct {
 	leftStyled []string
 	rightLines []string
+	// reply renders the row indented under the message before it.
+	reply bool
 }
 
 func buildMessageDisplay(message larksdk.Message, styles messageFormatStyles, senderNames map[string]string) messageDisplay {
@@ -228,7 +236,18 @@ func renderMessageTable(displays []messageDisplay, styles messageFormatStyles) s
 	}
 	rows := make([][]string, 0, len(displays))
 	for _, display := range displays {
-		left := strings.Join(display.leftStyled, "\n")
+		leftLines := display.leftStyled
+		if display.reply {
+			leftLines = make([]string, len(display.leftStyled))
+			for i, line := range display.leftStyled {
+				indent := "  "
+				if i == 0 {
+					indent = styles.renderDim("↳ ")
+				}
+				leftLines[i] = indent + line
+			}
+		}
+		left := strings.Join(leftLines, "\n")
 		right := strings.Join(display.rightLines, "\n")
 		rows = append(rows, []string{left, right})
 	}
@@ -301,6 +320,9 @@ func formatMessageMetaLine(message larksdk.Message, styles messageFormatStyles)
 	if message.MessageID != "" {
 		parts = append(parts, "message id: "+message.MessageID)
 	}
+	if message.ThreadID != "" {
+		parts = append(parts, "thread: "+message.ThreadID)
+	}
 	if len(parts) == 0 {
 		return ""
 	}
@@ -386,6 +408,11 @@ func formatMessageTime(raw string) string {
 	return t.Local().Format("2006-01-02 15:04:05")
 }
 
+// formatUnixTime formats unix seconds in the layout of formatMessageTime.
+func formatUnixTime(unix int64) string {
+	return time.Unix(unix, 0).Local().Format("2006-01-02 15:04:05")
+}
+
 func normalizeMessageContentLines(content string) []string {
 	if strings.TrimSpace(content) == "" {
 		return nil
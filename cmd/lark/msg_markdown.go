@@ -0,0 +1,471 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"lark/internal/larksdk"
+)
+
+// postLanguages are the locale keys accepted in post message content.
+var postLanguages = []string{"zh_cn", "en_us", "ja_jp"}
+
+// postElement is one element of a post paragraph. imagePath and email are
+// references that resolveMarkdownPost turns into image_key / user_id.
+type postElement struct {
+	Tag      string   `json:"tag"`
+	Text     string   `json:"text,omitempty"`
+	Href     string   `json:"href,omitempty"`
+	UserID   string   `json:"user_id,omitempty"`
+	ImageKey string   `json:"image_key,omitempty"`
+	Language string   `json:"language,omitempty"`
+	Style    []string `json:"style,omitempty"`
+
+	imagePath string
+	email     string
+}
+
+// markdownPost is a post message built from Markdown.
+type markdownPost struct {
+	Title   string          `json:"title"`
+	Content [][]postElement `json:"content"`
+}
+
+var (
+	markdownHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	markdownListPattern    = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	markdownRulePattern    = regexp.MustCompile(`^\s*([-*_])(\s*([-*_]))*\s*$`)
+	markdownFencePattern   = regexp.MustCompile("^\\s*(```+|~~~+)\\s*([\\w+#.-]*)")
+	markdownEmailPattern   = regexp.MustCompile(`^[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	markdownOpenIDPattern  = regexp.MustCompile(`^ou_[A-Za-z0-9]+`)
+)
+
+// markdownToPost converts Markdown into post paragraphs. A leading level-1
+// heading becomes the post title; other headings are rendered bold since post
+// has no heading element. Inline code is kept verbatim with its backticks
+// because post text has no monospace style.
+func markdownToPost(src string) markdownPost {
+	post := markdownPost{Content: [][]postElement{}}
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	blank := false
+	addParagraph := func(paragraph []postElement) {
+		if blank && len(post.Content) > 0 {
+			post.Content = append(post.Content, []postElement{{Tag: "text", Text: ""}})
+		}
+		blank = false
+		post.Content = append(post.Content, splitPostImages(paragraph)...)
+	}
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			blank = true
+			continue
+		}
+		if match := markdownFencePattern.FindStringSubmatch(line); match != nil {
+			fence := match[1]
+			code := make([]string, 0)
+			for i++; i < len(lines); i++ {
+				if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			addParagraph([]postElement{{Tag: "code_block", Language: strings.ToUpper(match[2]), Text: strings.Join(code, "\n") + "\n"}})
+			continue
+		}
+		if match := markdownHeadingPattern.FindStringSubmatch(line); match != nil {
+			if len(match[1]) == 1 && post.Title == "" && len(post.Content) == 0 {
+				post.Title = match[2]
+				blank = false
+				continue
+			}
+			addParagraph(parseMarkdownInline(match[2], []string{"bold"}))
+			continue
+		}
+		if markdownRulePattern.MatchString(line) && strings.Count(strings.ReplaceAll(line, " ", ""), string(strings.TrimSpace(line)[0])) >= 3 {
+			addParagraph([]postElement{{Tag: "hr"}})
+			continue
+		}
+		if match := markdownListPattern.FindStringSubmatch(line); match != nil {
+			indent := strings.Repeat("  ", len(strings.ReplaceAll(match[1], "\t", "    "))/2)
+			marker := "• "
+			if unicode.IsDigit(rune(match[2][0])) {
+				marker = strings.TrimRight(match[2], ".)") + ". "
+			}
+			paragraph := []postElement{{Tag: "text", Text: indent + marker}}
+			addParagraph(append(paragraph, parseMarkdownInline(match[3], nil)...))
+			continue
+		}
+		if quoted, ok := strings.CutPrefix(strings.TrimSpace(line), ">"); ok {
+			paragraph := []postElement{{Tag: "text", Text: "| "}}
+			addParagraph(append(paragraph, parseMarkdownInline(strings.TrimSpace(quoted), []string{"italic"})...))
+			continue
+		}
+		addParagraph(parseMarkdownInline(strings.TrimSpace(line), nil))
+	}
+	return post
+}
+
+// splitPostImages moves images into paragraphs of their own, which is how
+// post messages lay out img elements.
+func splitPostImages(paragraph []postElement) [][]postElement {
+	out := make([][]postElement, 0, 1)
+	current := make([]postElement, 0, len(paragraph))
+	for _, element := range paragraph {
+		if element.Tag != "img" {
+			current = append(current, element)
+			continue
+		}
+		if len(current) > 0 {
+			out = append(out, current)
+			current = make([]postElement, 0)
+		}
+		out = append(out, []postElement{element})
+	}
+	if len(current) > 0 || len(out) == 0 {
+		out = append(out, current)
+	}
+	return out
+}
+
+// parseMarkdownInline converts emphasis, links, images, inline code and
+// mentions on one line into post elements. base styles apply to all text.
+func parseMarkdownInline(line string, base []string) []postElement {
+	elements := make([]postElement, 0)
+	active := map[string]bool{}
+	var text strings.Builder
+	styles := func() []string {
+		out := append([]string(nil), base...)
+		for _, style := range []string{"bold", "italic", "lineThrough"} {
+			if active[style] {
+				out = append(out, style)
+			}
+		}
+		return out
+	}
+	flush := func() {
+		if text.Len() == 0 {
+			return
+		}
+		elements = append(elements, postElement{Tag: "text", Text: text.String(), Style: styles()})
+		text.Reset()
+	}
+	toggle := func(style, marker, rest string) bool {
+		if !active[style] && !strings.Contains(rest, marker) {
+			return false
+		}
+		flush()
+		active[style] = !active[style]
+		return true
+	}
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		rest := string(runes[i:])
+		prev := rune(' ')
+		if i > 0 {
+			prev = runes[i-1]
+		}
+		switch {
+		case runes[i] == '\\' && i+1 < len(runes) && (unicode.IsPunct(runes[i+1]) || unicode.IsSymbol(runes[i+1])):
+			text.WriteRune(runes[i+1])
+			i++
+		case runes[i] == '`':
+			end := strings.Index(rest[1:], "`")
+			if end < 0 {
+				text.WriteRune('`')
+				continue
+			}
+			code := rest[:end+2]
+			text.WriteString(code)
+			i += len([]rune(code)) - 1
+		case strings.HasPrefix(rest, "!["):
+			_, src, n, ok := parseMarkdownLink(rest[1:])
+			if !ok {
+				text.WriteRune(runes[i])
+				continue
+			}
+			flush()
+			element := postElement{Tag: "img", imagePath: src}
+			if strings.HasPrefix(src, "img_") {
+				element = postElement{Tag: "img", ImageKey: src}
+			}
+			elements = append(elements, element)
+			i += n
+		case runes[i] == '[':
+			label, href, n, ok := parseMarkdownLink(rest)
+			if !ok {
+				text.WriteRune(runes[i])
+				continue
+			}
+			flush()
+			elements = append(elements, postElement{Tag: "a", Text: label, Href: href, Style: styles()})
+			i += n - 1
+		case runes[i] == '<' && (strings.HasPrefix(rest, "<http://") || strings.HasPrefix(rest, "<https://")) && strings.Contains(rest, ">"):
+			href := rest[1:strings.Index(rest, ">")]
+			flush()
+			elements = append(elements, postElement{Tag: "a", Text: href, Href: href, Style: styles()})
+			i += len([]rune(href)) + 1
+		case runes[i] == '@' && !isMarkdownWordRune(prev):
+			mention := rest[1:]
+			switch {
+			case strings.HasPrefix(mention, "all") && (len(mention) == 3 || !isMarkdownWordRune([]rune(mention)[3])):
+				flush()
+				elements = append(elements, postElement{Tag: "at", UserID: "all"})
+				i += 3
+			case markdownOpenIDPattern.MatchString(mention):
+				openID := markdownOpenIDPattern.FindString(mention)
+				flush()
+				elements = append(elements, postElement{Tag: "at", UserID: openID})
+				i += len(openID)
+			case markdownEmailPattern.MatchString(mention):
+				email := strings.TrimRight(markdownEmailPattern.FindString(mention), ".")
+				flush()
+				elements = append(elements, postElement{Tag: "at", email: email})
+				i += len([]rune(email))
+			default:
+				text.WriteRune('@')
+			}
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if !toggle("bold", rest[:2], rest[2:]) {
+				text.WriteString(rest[:2])
+			}
+			i++
+		case strings.HasPrefix(rest, "~~"):
+			if !toggle("lineThrough", "~~", rest[2:]) {
+				text.WriteString("~~")
+			}
+			i++
+		case runes[i] == '*':
+			if !toggle("italic", "*", rest[1:]) {
+				text.WriteRune('*')
+			}
+		case runes[i] == '_':
+			// Underscores inside words (snake_case) are literal.
+			next := rune(' ')
+			if i+1 < len(runes) {
+				next = runes[i+1]
+			}
+			if (active["italic"] && isMarkdownWordRune(next)) || (!active["italic"] && isMarkdownWordRune(prev)) || !toggle("italic", "_", rest[1:]) {
+				text.WriteRune('_')
+			}
+		default:
+			text.WriteRune(runes[i])
+		}
+	}
+	flush()
+	if len(elements) == 0 {
+		elements = append(elements, postElement{Tag: "text", Text: ""})
+	}
+	return elements
+}
+
+// parseMarkdownLink parses "[label](target)" at the start of s and returns
+// the number of runes consumed.
+func parseMarkdownLink(s string) (string, string, int, bool) {
+	if !strings.HasPrefix(s, "[") {
+		return "", "", 0, false
+	}
+	closeLabel := strings.Index(s, "](")
+	if closeLabel < 0 {
+		return "", "", 0, false
+	}
+	closeTarget := strings.Index(s[closeLabel+2:], ")")
+	if closeTarget < 0 {
+		return "", "", 0, false
+	}
+	label := s[1:closeLabel]
+	target := strings.TrimSpace(s[closeLabel+2 : closeLabel+2+closeTarget])
+	if target == "" {
+		return "", "", 0, false
+	}
+	consumed := len([]rune(s[:closeLabel+2+closeTarget+1]))
+	return label, target, consumed, true
+}
+
+func isMarkdownWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// readMarkdownSource reads --markdown: inline text, or @path / @- for a file
+// or stdin. The returned dir resolves relative image paths.
+func readMarkdownSource(value string) (string, string, error) {
+	path, ok := strings.CutPrefix(value, "@")
+	if !ok {
+		return value, ".", nil
+	}
+	data, err := readInputFile(path)
+	if err != nil {
+		return "", "", fmt.Errorf("read markdown: %w", err)
+	}
+	dir := "."
+	if strings.TrimSpace(path) != "-" {
+		dir = filepath.Dir(path)
+	}
+	return string(data), dir, nil
+}
+
+// resolveMarkdownPost uploads referenced images and looks up mentioned
+// emails so every element carries the key the API expects.
+func resolveMarkdownPost(ctx context.Context, state *appState, token string, post *markdownPost, dir string) error {
+	emails := make([]string, 0)
+	for _, paragraph := range post.Content {
+		for _, element := range paragraph {
+			if element.email != "" {
+				emails = append(emails, element.email)
+			}
+		}
+	}
+	openIDs := map[string]string{}
+	if len(emails) > 0 {
+		resolved, missing, err := resolveEmailOpenIDs(ctx, state, token, emails)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("no user found for %s", strings.Join(missing, ", "))
+		}
+		openIDs = resolved
+	}
+	imageKeys := map[string]string{}
+	for _, paragraph := range post.Content {
+		for i := range paragraph {
+			element := &paragraph[i]
+			if element.email != "" {
+				element.UserID = openIDs[element.email]
+			}
+			if element.imagePath == "" {
+				continue
+			}
+			key, ok := imageKeys[element.imagePath]
+			if !ok {
+				var err error
+				key, err = uploadMarkdownImage(ctx, state, token, element.imagePath, dir)
+				if err != nil {
+					return fmt.Errorf("image %s: %w", element.imagePath, err)
+				}
+				imageKeys[element.imagePath] = key
+			}
+			element.ImageKey = key
+		}
+	}
+	return nil
+}
+
+func uploadMarkdownImage(ctx context.Context, state *appState, token, src, dir string) (string, error) {
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+		if err != nil {
+			return "", err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("download failed: %s", resp.Status)
+		}
+		if resp.ContentLength > larksdk.MessageImageMaxSize {
+			return "", fmt.Errorf("image exceeds %d bytes", larksdk.MessageImageMaxSize)
+		}
+		data, err := io.ReadAll(io.LimitReader(resp.Body, larksdk.MessageImageMaxSize+1))
+		if err != nil {
+			return "", err
+		}
+		if int64(len(data)) > larksdk.MessageImageMaxSize {
+			return "", fmt.Errorf("image exceeds %d bytes", larksdk.MessageImageMaxSize)
+		}
+		return state.SDK.UploadMessageImage(ctx, token, bytes.NewReader(data))
+	}
+	path := src
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	if info.Size() > larksdk.MessageImageMaxSize {
+		return "", fmt.Errorf("image exceeds %d bytes", larksdk.MessageImageMaxSize)
+	}
+	return state.SDK.UploadMessageImage(ctx, token, file)
+}
+
+// postContent wraps the post in its locale key.
+func (p markdownPost) postContent(lang string) (string, error) {
+	raw, err := json.Marshal(map[string]markdownPost{lang: p})
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+func normalizePostLanguage(lang string) (string, error) {
+	value := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(lang), "-", "_"))
+	if value == "" {
+		return "zh_cn", nil
+	}
+	for _, allowed := range postLanguages {
+		if value == allowed {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("lang must be one of %s", strings.Join(postLanguages, ", "))
+}
+
+// markdownMessage is a --markdown post waiting for its images and mentions
+// to be resolved.
+type markdownMessage struct {
+	post markdownPost
+	dir  string
+	lang string
+}
+
+// parseMarkdownMessage validates --markdown and converts it before any API
+// call is made. It returns nil when --markdown is not set.
+func parseMarkdownMessage(opts messageContentOptions) (*markdownMessage, error) {
+	if strings.TrimSpace(opts.Markdown) == "" {
+		return nil, nil
+	}
+	other := opts
+	other.Markdown = ""
+	if messageContentProvided(other) {
+		return nil, errors.New("please provide only one of text/post/markdown/image-key/file-key/media-key/content")
+	}
+	if msgType := strings.TrimSpace(opts.MsgType); msgType != "" && msgType != "post" {
+		return nil, fmt.Errorf("markdown requires msg_type=post (got %q)", msgType)
+	}
+	lang, err := normalizePostLanguage(opts.Lang)
+	if err != nil {
+		return nil, err
+	}
+	src, dir, err := readMarkdownSource(opts.Markdown)
+	if err != nil {
+		return nil, err
+	}
+	return &markdownMessage{post: markdownToPost(src), dir: dir, lang: lang}, nil
+}
+
+// content uploads images, resolves mentions and returns the post content JSON.
+func (m *markdownMessage) content(ctx context.Context, state *appState, token string) (string, error) {
+	if err := resolveMarkdownPost(ctx, state, token, &m.post, m.dir); err != nil {
+		return "", err
+	}
+	return m.post.postContent(m.lang)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"lark/internal/larksdk"
+)
+
+func TestMarkdownToPost(t *testing.T) {
+	src := strings.Join([]string{
+		"# Release notes",
+		"Ship **v2** with *care* and ~~bugs~~, see [docs](https://example.com) or `make test`.",
+		"## Changes",
+		"- fix snake_case names",
+		"2. ping @ou_abc and @all",
+		"",
+		"```go",
+		"fmt.Println(1)",
+		"```",
+		"---",
+		"![chart](img_v2_key)",
+	}, "\n")
+
+	post := markdownToPost(src)
+	if post.Title != "Release notes" {
+		t.Fatalf("unexpected title: %q", post.Title)
+	}
+	want := [][]postElement{
+		{
+			{Tag: "text", Text: "Ship "},
+			{Tag: "text", Text: "v2", Style: []string{"bold"}},
+			{Tag: "text", Text: " with "},
+			{Tag: "text", Text: "care", Style: []string{"italic"}},
+			{Tag: "text", Text: " and "},
+			{Tag: "text", Text: "bugs", Style: []string{"lineThrough"}},
+			{Tag: "text", Text: ", see "},
+			{Tag: "a", Text: "docs", Href: "https://example.com"},
+			{Tag: "text", Text: " or `make test`."},
+		},
+		{{Tag: "text", Text: "Changes", Style: []string{"bold"}}},
+		{{Tag: "text", Text: "• "}, {Tag: "text", Text: "fix snake_case names"}},
+		{{Tag: "text", Text: "2. "}, {Tag: "text", Text: "ping "}, {Tag: "at", UserID: "ou_abc"}, {Tag: "text", Text: " and "}, {Tag: "at", UserID: "all"}},
+		{{Tag: "text", Text: ""}},
+		{{Tag: "code_block", Language: "GO", Text: "fmt.Println(1)\n"}},
+		{{Tag: "hr"}},
+		{{Tag: "img", ImageKey: "img_v2_key"}},
+	}
+	if !reflect.DeepEqual(post.Content, want) {
+		got, _ := json.Marshal(post.Content)
+		t.Fatalf("unexpected content: %s", got)
+	}
+}
+
+func TestMsgSendMarkdownUploadsImagesAndResolvesMentions(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "chart.png"), []byte("png"), 0o600); err != nil {
+		t.Fatalf("write image: %v", err)
+	}
+	notes := filepath.Join(dir, "notes.md")
+	if err := os.WriteFile(notes, []byte("Hi @Ada@example.com\n![chart](chart.png)\n![again](chart.png)\n"), 0o600); err != nil {
+		t.Fatalf("write markdown: %v", err)
+	}
+
+	uploads := 0
+	var sent map[string]string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/open-apis/contact/v3/users/batch_get_id":
+			var body struct {
+				Emails []string `json:"emails"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode batch_get_id: %v", err)
+			}
+			if strings.Join(body.Emails, ",") != "ada@example.com" {
+				t.Fatalf("unexpected emails: %v", body.Emails)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"user_list": []map[string]any{{"user_id": "ou_ada", "email": "ada@example.com"}},
+			}})
+		case "/open-apis/im/v1/images":
+			uploads++
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"image_key": "img_chart"}})
+		case "/open-apis/im/v1/messages":
+			if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+				t.Fatalf("decode message: %v", err)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"message_id": "om_1"}})
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	})
+	state, _ := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"send", "oc_1", "--markdown", "@" + notes, "--lang", "en_us"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("send error: %v", err)
+	}
+	if uploads != 1 {
+		t.Fatalf("expected one image upload, got %d", uploads)
+	}
+	if sent["msg_type"] != "post" {
+		t.Fatalf("unexpected msg_type: %q", sent["msg_type"])
+	}
+	want := `{"en_us":{"title":"","content":[[{"tag":"text","text":"Hi "},{"tag":"at","user_id":"ou_ada"}],[{"tag":"img","image_key":"img_chart"}],[{"tag":"img","image_key":"img_chart"}]]}}`
+	if sent["content"] != want {
+		t.Fatalf("unexpected content:\n%s", sent["content"])
+	}
+}
+
+func TestMsgSendMarkdownRejectsOtherContent(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s", r.URL.Path)
+	})
+	state, _ := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"send", "oc_1", "--markdown", "**hi**", "--text", "hi"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "only one of") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+}
+
+func TestUploadMarkdownImageRejectsOversizedDownload(t *testing.T) {
+	image := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Flushing before the body is complete forces a chunked response
+		// without Content-Length.
+		chunk := make([]byte, 1<<20)
+		for written := int64(0); written <= larksdk.MessageImageMaxSize; written += int64(len(chunk)) {
+			_, _ = w.Write(chunk)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer image.Close()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s", r.URL.Path)
+	})
+	state, _ := newTestState(t, handler, true)
+
+	_, err := uploadMarkdownImage(context.Background(), state, "token", image.URL+"/big.png", "")
+	if err == nil || !strings.Contains(err.Error(), "image exceeds") {
+		t.Fatalf("expected size error, got %v", err)
+	}
+
+	dir := t.TempDir()
+	file, err := os.Create(filepath.Join(dir, "big.png"))
+	if err != nil {
+		t.Fatalf("create image: %v", err)
+	}
+	if err := file.Truncate(larksdk.MessageImageMaxSize + 1); err != nil {
+		t.Fatalf("truncate image: %v", err)
+	}
+	file.Close()
+	_, err = uploadMarkdownImage(context.Background(), state, "token", "big.png", dir)
+	if err == nil || !strings.Contains(err.Error(), "image exceeds") {
+		t.Fatalf("expected size error for local file, got %v", err)
+	}
+}
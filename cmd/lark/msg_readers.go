@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+const (
+	maxReadUsersPageSize = 100
+	// urgentBatchSize keeps each nudge request to a modest number of users.
+	urgentBatchSize = 100
+)
+
+// messageReader is a chat member with their read time, if they have read the
+// message.
+type messageReader struct {
+	UserID   string `json:"user_id"`
+	Name     string `json:"name,omitempty"`
+	ReadTime string `json:"read_time,omitempty"`
+}
+
+func newMsgReadersCmd(state *appState) *cobra.Command {
+	var messageID string
+	var unread bool
+	var nudge bool
+	var userIDType string
+
+	cmd := &cobra.Command{
+		Use:   "readers <message-id>",
+		Short: "Show who has (or has not) read a message",
+		Long: `List the users who have read a message the app sent in the last 7 days.
+
+With --unread, the chat's members are listed instead and compared against
+the readers, showing everyone who has not read it yet. --nudge sends those
+members an in-app urgent (buzz) notification; it implies --unread and asks
+for confirmation unless --force is set.`,
+		Example: `  lark messages readers <MESSAGE_ID>
+  lark messages readers <MESSAGE_ID> --unread
+  lark messages readers <MESSAGE_ID> --nudge --force`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			messageID = strings.TrimSpace(args[0])
+			if messageID == "" {
+				return argsUsageError(cmd, errors.New("message-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch userIDType {
+			case "open_id", "union_id", "user_id":
+			default:
+				return flagUsage(cmd, "user-id-type must be one of open_id, union_id, user_id")
+			}
+			if nudge {
+				unread = true
+			}
+			if _, err := requireSDK(state); err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			token, err := tokenFor(ctx, state, tokenTypesTenant)
+			if err != nil {
+				return err
+			}
+			readUsers, err := listMessageReadUsers(ctx, state, token, messageID, userIDType)
+			if err != nil {
+				return err
+			}
+			if !unread {
+				readers := make([]messageReader, 0, len(readUsers))
+				lines := make([]string, 0, len(readUsers))
+				for _, user := range readUsers {
+					readers = append(readers, messageReader{UserID: user.UserID, ReadTime: user.Timestamp})
+					lines = append(lines, fmt.Sprintf("%s\t%s", user.UserID, formatMessageTime(user.Timestamp)))
+				}
+				payload := map[string]any{"message_id": messageID, "read_count": len(readers), "readers": readers}
+				return state.Printer.Print(payload, tableText([]string{"user_id", "read_at"}, lines, "no one has read this message yet"))
+			}
+
+			message, err := state.SDK.GetMessageWithToken(ctx, token, larksdk.AccessTokenTenant, messageID, "")
+			if err != nil {
+				return err
+			}
+			if message.ChatID == "" {
+				return fmt.Errorf("message %s has no chat_id", messageID)
+			}
+			members, err := listAllChatMembers(ctx, state, token, message.ChatID, userIDType)
+			if err != nil {
+				return err
+			}
+			read := make(map[string]bool, len(readUsers))
+			for _, user := range readUsers {
+				read[user.UserID] = true
+			}
+			pending := make([]messageReader, 0)
+			for _, member := range members {
+				if member.MemberID == "" || read[member.MemberID] {
+					continue
+				}
+				pending = append(pending, messageReader{UserID: member.MemberID, Name: member.Name})
+			}
+			payload := map[string]any{
+				"message_id": messageID,
+				"chat_id":    message.ChatID,
+				"members":    len(members),
+				"read_count": len(readUsers),
+				"unread":     pending,
+			}
+			lines := make([]string, 0, len(pending))
+			for _, reader := range pending {
+				lines = append(lines, fmt.Sprintf("%s\t%s", reader.UserID, reader.Name))
+			}
+			text := fmt.Sprintf("%d of %d members have read %s\n", len(members)-len(pending), len(members), messageID) +
+				tableText([]string{"user_id", "name"}, lines, "everyone has read this message")
+			if !nudge || len(pending) == 0 {
+				return state.Printer.Print(payload, text)
+			}
+
+			if err := confirmDestructive(cmd, state, fmt.Sprintf("buzz %d members who have not read %s", len(pending), messageID)); err != nil {
+				return err
+			}
+			ids := make([]string, 0, len(pending))
+			for _, reader := range pending {
+				ids = append(ids, reader.UserID)
+			}
+			var invalid []string
+			for start := 0; start < len(ids); start += urgentBatchSize {
+				batch := ids[start:min(start+urgentBatchSize, len(ids))]
+				rejected, err := state.SDK.UrgentMessage(ctx, token, larksdk.UrgentMessageRequest{
+					MessageID:  messageID,
+					Mode:       "app",
+					UserIDType: userIDType,
+					UserIDs:    batch,
+				})
+				if err != nil {
+					if start > 0 {
+						return fmt.Errorf("nudge stopped after %d of %d members: %w", start, len(ids), err)
+					}
+					return err
+				}
+				invalid = append(invalid, rejected...)
+			}
+			payload["nudged"] = len(ids) - len(invalid)
+			payload["invalid_user_ids"] = invalid
+			text += fmt.Sprintf("\nnudged %d members", len(ids)-len(invalid))
+			if err := state.Printer.Print(payload, text); err != nil {
+				return err
+			}
+			if len(invalid) > 0 {
+				return fmt.Errorf("%d of %d members could not be nudged", len(invalid), len(ids))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&unread, "unread", false, "list chat members who have not read the message")
+	cmd.Flags().BoolVar(&nudge, "nudge", false, "buzz members who have not read the message (implies --unread)")
+	cmd.Flags().StringVar(&userIDType, "user-id-type", "open_id", "user ID type in output (open_id, union_id, user_id)")
+	return cmd
+}
+
+func listMessageReadUsers(ctx context.Context, state *appState, token, messageID, userIDType string) ([]larksdk.MessageReadUser, error) {
+	pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.MessageReadUser], error) {
+		result, err := state.SDK.ListMessageReadUsers(ctx, token, larksdk.ListMessageReadUsersRequest{
+			MessageID:  messageID,
+			UserIDType: userIDType,
+			PageSize:   maxReadUsersPageSize,
+			PageToken:  pageToken,
+		})
+		return larksdk.Page[larksdk.MessageReadUser]{Items: result.Items, PageToken: result.PageToken, HasMore: result.HasMore}, err
+	})
+	var users []larksdk.MessageReadUser
+	for !pager.Done() {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, page...)
+	}
+	return users, nil
+}
+
+func listAllChatMembers(ctx context.Context, state *appState, token, chatID, memberIDType string) ([]larksdk.ChatMember, error) {
+	pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.ChatMember], error) {
+		result, err := state.SDK.ListChatMembers(ctx, token, larksdk.ListChatMembersRequest{
+			ChatID:       chatID,
+			MemberIDType: memberIDType,
+			PageSize:     maxChatMembersPageSize,
+			PageToken:    pageToken,
+		})
+		return larksdk.Page[larksdk.ChatMember]{Items: result.Items, PageToken: result.PageToken, HasMore: result.HasMore}, err
+	})
+	var members []larksdk.ChatMember
+	for !pager.Done() {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, page...)
+	}
+	return members, nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMsgReadersListsAllPages(t *testing.T) {
+	var pages []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/open-apis/im/v1/messages/om_1/read_users" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		query := r.URL.Query()
+		if query.Get("user_id_type") != "open_id" {
+			t.Fatalf("unexpected query: %s", r.URL.RawQuery)
+		}
+		pages = append(pages, query.Get("page_token"))
+		if query.Get("page_token") == "" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": true, "page_token": "p2", "items": []map[string]any{
+				{"user_id_type": "open_id", "user_id": "ou_a", "timestamp": "1700000000000"},
+			}}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+			{"user_id_type": "open_id", "user_id": "ou_b", "timestamp": "1700000060000"},
+		}}})
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"readers", "om_1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("readers error: %v", err)
+	}
+	if strings.Join(pages, ",") != ",p2" {
+		t.Fatalf("unexpected pages: %q", pages)
+	}
+	var payload struct {
+		ReadCount int             `json:"read_count"`
+		Readers   []messageReader `json:"readers"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if payload.ReadCount != 2 || payload.Readers[1].UserID != "ou_b" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestMsgReadersNudgesUnread(t *testing.T) {
+	var requests, urgent []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/open-apis/im/v1/messages/om_1/read_users":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+				{"user_id_type": "open_id", "user_id": "ou_a", "timestamp": "1700000000000"},
+				{"user_id_type": "open_id", "user_id": "ou_b", "timestamp": "1700000060000"},
+			}}})
+		case "/open-apis/im/v1/messages/om_1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"items": []map[string]any{
+				{"message_id": "om_1", "chat_id": "oc_1", "msg_type": "text"},
+			}}})
+		case "/open-apis/im/v1/chats/oc_1/members":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "member_total": 4, "items": []map[string]any{
+				{"member_id_type": "open_id", "member_id": "ou_a", "name": "Ada"},
+				{"member_id_type": "open_id", "member_id": "ou_b", "name": "Bob"},
+				{"member_id_type": "open_id", "member_id": "ou_c", "name": "Cy"},
+				{"member_id_type": "open_id", "member_id": "ou_d", "name": "Di"},
+			}}})
+		case "/open-apis/im/v1/messages/om_1/urgent_app":
+			var body struct {
+				UserIDList []string `json:"user_id_list"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode urgent: %v", err)
+			}
+			urgent = append(urgent, body.UserIDList...)
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"invalid_user_id_list": []string{}}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+	state.Force = true
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"readers", "om_1", "--nudge"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("readers error: %v", err)
+	}
+	want := "GET /open-apis/im/v1/messages/om_1/read_users,GET /open-apis/im/v1/messages/om_1,GET /open-apis/im/v1/chats/oc_1/members,PATCH /open-apis/im/v1/messages/om_1/urgent_app"
+	if strings.Join(requests, ",") != want {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	if strings.Join(urgent, ",") != "ou_c,ou_d" {
+		t.Fatalf("unexpected nudged users: %v", urgent)
+	}
+	var payload struct {
+		Members int             `json:"members"`
+		Unread  []messageReader `json:"unread"`
+		Nudged  int             `json:"nudged"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if payload.Members != 4 || len(payload.Unread) != 2 || payload.Unread[0].Name != "Cy" || payload.Nudged != 2 {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestMsgReadersNudgeRequiresConfirmation(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/open-apis/im/v1/messages/om_1/read_users":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{}}})
+		case "/open-apis/im/v1/messages/om_1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"items": []map[string]any{
+				{"message_id": "om_1", "chat_id": "oc_1", "msg_type": "text"},
+			}}})
+		case "/open-apis/im/v1/chats/oc_1/members":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+				{"member_id_type": "open_id", "member_id": "ou_a", "name": "Ada"},
+			}}})
+		default:
+			t.Fatalf("nobody should be nudged without confirmation: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, _ := newTestState(t, handler, true)
+	state.NoInput = true
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"readers", "om_1", "--nudge"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected confirmation error, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+func newMsgRecallCmd(state *appState) *cobra.Command {
+	var messageID string
+
+	cmd := &cobra.Command{
+		Use:   "recall <message-id>",
+		Short: "Recall (delete) a sent message",
+		Long: `Recall a message for everyone in the chat.
+
+Bots can recall messages they sent; group owners and admins can also recall
+other members' messages. Use --force to skip the confirmation prompt.`,
+		Example: `  lark messages recall <MESSAGE_ID>
+  lark messages recall <MESSAGE_ID> --force`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			messageID = strings.TrimSpace(args[0])
+			if messageID == "" {
+				return argsUsageError(cmd, errors.New("message-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := confirmDestructive(cmd, state, fmt.Sprintf("recall message %s", messageID)); err != nil {
+				return err
+			}
+			return runWithToken(cmd, state, tokenTypesTenantOrUser, nil, func(ctx context.Context, sdk *larksdk.Client, token string, tokenType tokenType) (any, string, error) {
+				if err := sdk.RecallMessage(ctx, token, larksdk.AccessTokenType(tokenType), messageID); err != nil {
+					return nil, "", err
+				}
+				payload := map[string]any{"message_id": messageID, "recalled": true}
+				return payload, fmt.Sprintf("recalled: %s", messageID), nil
+			})
+		},
+	}
+	return cmd
+}
+
+func newMsgEditCmd(state *appState) *cobra.Command {
+	var messageID string
+	var text string
+	var post string
+
+	cmd := &cobra.Command{
+		Use:   "edit <message-id>",
+		Short: "Edit the content of a sent text or post message",
+		Long: `Replace the content of a text or rich-text (post) message.
+
+A message can only be edited by its sender, and a text message stays text
+(and a post stays a post). Update cards with messages update --card.`,
+		Example: `  lark messages edit <MESSAGE_ID> --text "fixed typo"
+  lark messages edit <MESSAGE_ID> --post '{"zh_cn":{"title":"Update","content":[[{"tag":"text","text":"done"}]]}}'`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			messageID = strings.TrimSpace(args[0])
+			if messageID == "" {
+				return argsUsageError(cmd, errors.New("message-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(text) == "" && strings.TrimSpace(post) == "" {
+				return usageError(cmd, "--text or --post is required", "")
+			}
+			msgType, content, err := resolveMessageContent(messageContentOptions{Text: text, Post: post})
+			if err != nil {
+				return flagUsage(cmd, err.Error())
+			}
+			return runWithToken(cmd, state, tokenTypesTenantOrUser, nil, func(ctx context.Context, sdk *larksdk.Client, token string, tokenType tokenType) (any, string, error) {
+				if err := sdk.EditMessage(ctx, token, larksdk.AccessTokenType(tokenType), messageID, msgType, content); err != nil {
+					return nil, "", err
+				}
+				payload := map[string]any{"message_id": messageID, "msg_type": msgType, "edited": true}
+				return payload, fmt.Sprintf("edited: %s", messageID), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&text, "text", "", "new text content")
+	cmd.Flags().StringVar(&post, "post", "", "new post (rich text) JSON content")
+	cmd.MarkFlagsMutuallyExclusive("text", "post")
+	return cmd
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMsgRecallRequiresConfirmation(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+	})
+	state, _ := newTestState(t, handler, true)
+	state.NoInput = true
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"recall", "om_1"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected confirmation error, got %v", err)
+	}
+}
+
+func TestMsgRecallAndEdit(t *testing.T) {
+	var requests []string
+	var edit map[string]string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPut {
+			if err := json.NewDecoder(r.Body).Decode(&edit); err != nil {
+				t.Fatalf("decode edit: %v", err)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{}})
+	})
+	state, buf := newTestState(t, handler, true)
+	state.Force = true
+
+	for _, args := range [][]string{{"recall", "om_1"}, {"edit", "om_2", "--text", "fixed"}} {
+		cmd := newMsgCmd(state)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("%s error: %v", args[0], err)
+		}
+	}
+	if strings.Join(requests, ",") != "DELETE /open-apis/im/v1/messages/om_1,PUT /open-apis/im/v1/messages/om_2" {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	if edit["msg_type"] != "text" || edit["content"] != `{"text":"fixed"}` {
+		t.Fatalf("unexpected edit body: %v", edit)
+	}
+	if !strings.Contains(buf.String(), `"recalled": true`) || !strings.Contains(buf.String(), `"edited": true`) {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestMsgEditRejectsMissingContent(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s", r.URL.Path)
+	})
+	state, _ := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"edit", "om_1"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--text or --post") {
+		t.Fatalf("expected usage error, got %v", err)
+	}
+}
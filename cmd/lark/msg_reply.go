@@ -35,14 +35,28 @@ func newMsgReplyCmd(state *appState) *cobra.Command {
 			if _, err := requireSDK(state); err != nil {
 				return err
 			}
-			msgType, content, err := resolveMessageContent(contentOpts)
+			markdown, err := parseMarkdownMessage(contentOpts)
 			if err != nil {
 				return err
 			}
+			msgType := "post"
+			var content string
+			if markdown == nil {
+				msgType, content, err = resolveMessageContent(contentOpts)
+				if err != nil {
+					return err
+				}
+			}
 			token, err := tokenFor(cmd.Context(), state, tokenTypesTenantOrUser)
 			if err != nil {
 				return err
 			}
+			if markdown != nil {
+				content, err = markdown.content(cmd.Context(), state, token)
+				if err != nil {
+					return err
+				}
+			}
 			replyID, err := state.SDK.ReplyMessage(cmd.Context(), token, larksdk.ReplyMessageRequest{
 				MessageID:     messageID,
 				MsgType:       msgType,
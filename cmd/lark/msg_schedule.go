@@ -0,0 +1,383 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+const (
+	scheduleQueueFileName = "scheduled_messages.json"
+	// A lock older than this is left over from a crashed run.
+	scheduleLockStaleAfter = time.Hour
+	// scheduleMaxAttempts is how many runs may try a message before it is
+	// marked failed.
+	scheduleMaxAttempts = 5
+)
+
+// schedulePermanentErrorCodes are send errors that no retry can fix: bad
+// content, a receiver the bot cannot reach, or missing permissions.
+var schedulePermanentErrorCodes = map[int]bool{
+	230001:   true, // invalid request parameter
+	230002:   true, // bot is not in the chat
+	230006:   true, // bot ability is not activated
+	230013:   true, // bot is not available to the user
+	230017:   true, // bot does not own the resource
+	230018:   true, // not allowed by the chat settings
+	230019:   true, // topic does not exist
+	230025:   true, // content is too long
+	230027:   true, // missing permissions
+	230028:   true, // message did not pass review
+	230034:   true, // chat has been dissolved
+	230035:   true, // no permission to send
+	230099:   true, // card content could not be created
+	99992402: true, // field validation failed
+}
+
+// scheduledMessage is one queued send. Content is resolved when the message is
+// queued, so dispatching only needs a token.
+type scheduledMessage struct {
+	ID            string `json:"id"`
+	SendAt        int64  `json:"send_at"`
+	ReceiveID     string `json:"receive_id"`
+	ReceiveIDType string `json:"receive_id_type"`
+	MsgType       string `json:"msg_type"`
+	Content       string `json:"content"`
+	UUID          string `json:"uuid"`
+	CreatedAt     string `json:"created_at"`
+	Attempts      int    `json:"attempts,omitempty"`
+	LastError     string `json:"last_error,omitempty"`
+	// Failed entries are no longer sent; they stay queued until canceled.
+	Failed bool `json:"failed,omitempty"`
+}
+
+type scheduleQueue struct {
+	Messages []scheduledMessage `json:"messages"`
+}
+
+// scheduleDispatch is the outcome of one send attempted by schedule run.
+type scheduleDispatch struct {
+	ID        string `json:"id"`
+	ReceiveID string `json:"receive_id"`
+	MessageID string `json:"message_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+func newMsgScheduleCmd(state *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Manage messages queued with messages send --at",
+		Long: `messages send --at queues a message in a local file next to the config
+(scheduled_messages.json). Nothing is sent until schedule run dispatches the
+messages that are due, so run it periodically, for example from cron:
+
+  * * * * * lark messages schedule run`,
+	}
+	cmd.AddCommand(newMsgScheduleListCmd(state))
+	cmd.AddCommand(newMsgScheduleCancelCmd(state))
+	cmd.AddCommand(newMsgScheduleRunCmd(state))
+	return cmd
+}
+
+func newMsgScheduleListCmd(state *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List queued messages",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.NoArgs(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := scheduleQueuePath(state)
+			if err != nil {
+				return err
+			}
+			queue, err := loadScheduleQueue(path)
+			if err != nil {
+				return err
+			}
+			lines := make([]string, 0, len(queue.Messages))
+			for _, message := range queue.Messages {
+				status := "pending"
+				switch {
+				case message.Failed:
+					status = fmt.Sprintf("failed %dx: %s", message.Attempts, message.LastError)
+				case message.LastError != "":
+					status = fmt.Sprintf("retrying, %dx: %s", message.Attempts, message.LastError)
+				}
+				lines = append(lines, fmt.Sprintf("%s\t%s\t%s:%s\t%s\t%s", message.ID, formatUnixTime(message.SendAt), message.ReceiveIDType, message.ReceiveID, message.MsgType, status))
+			}
+			payload := map[string]any{"messages": queue.Messages}
+			return state.Printer.Print(payload, tableText([]string{"id", "send_at", "receiver", "msg_type", "status"}, lines, "no scheduled messages"))
+		},
+	}
+	return cmd
+}
+
+func newMsgScheduleCancelCmd(state *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cancel <id>...",
+		Short: "Remove queued messages",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.MinimumNArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := scheduleQueuePath(state)
+			if err != nil {
+				return err
+			}
+			var canceled []string
+			err = withScheduleQueue(path, func(queue *scheduleQueue, save func() error) error {
+				cancel := map[string]bool{}
+				for _, arg := range args {
+					cancel[strings.TrimSpace(arg)] = true
+				}
+				kept := queue.Messages[:0]
+				for _, message := range queue.Messages {
+					if cancel[message.ID] {
+						canceled = append(canceled, message.ID)
+						delete(cancel, message.ID)
+						continue
+					}
+					kept = append(kept, message)
+				}
+				if len(cancel) > 0 {
+					missing := make([]string, 0, len(cancel))
+					for id := range cancel {
+						missing = append(missing, id)
+					}
+					sort.Strings(missing)
+					return fmt.Errorf("no scheduled message with id %s", strings.Join(missing, ", "))
+				}
+				queue.Messages = kept
+				return save()
+			})
+			if err != nil {
+				return err
+			}
+			payload := map[string]any{"canceled": canceled}
+			return state.Printer.Print(payload, fmt.Sprintf("canceled: %s", strings.Join(canceled, ", ")))
+		},
+	}
+	return cmd
+}
+
+func newMsgScheduleRunCmd(state *appState) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Send every queued message that is due",
+		Long: `Send every queued message whose time has come and remove it from the queue.
+
+A message that fails stays queued with its error and is retried on the next
+run, up to 5 attempts. Errors that a retry cannot fix (an unreachable receiver,
+invalid content, missing permissions) mark it failed at once. Failed messages
+are listed by schedule list until they are canceled. Each message is sent with a fixed request UUID, so a retry after a crash
+within the API's one-hour idempotency window is not delivered twice.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.NoArgs(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := scheduleQueuePath(state)
+			if err != nil {
+				return err
+			}
+			dispatched := make([]scheduleDispatch, 0)
+			failed := 0
+			err = withScheduleQueue(path, func(queue *scheduleQueue, save func() error) error {
+				now := time.Now().Unix()
+				due := 0
+				for _, message := range queue.Messages {
+					if message.SendAt <= now && !message.Failed {
+						due++
+					}
+				}
+				if due == 0 {
+					return nil
+				}
+				if _, err := requireSDK(state); err != nil {
+					return err
+				}
+				token, err := tokenFor(cmd.Context(), state, tokenTypesTenantOrUser)
+				if err != nil {
+					return err
+				}
+				for i := 0; i < len(queue.Messages); i++ {
+					message := &queue.Messages[i]
+					if message.SendAt > now || message.Failed {
+						continue
+					}
+					if err := cmd.Context().Err(); err != nil {
+						state.interrupted = fmt.Errorf("interrupted: %w", err)
+						return nil
+					}
+					result := scheduleDispatch{ID: message.ID, ReceiveID: message.ReceiveID}
+					result.MessageID, err = state.SDK.SendMessage(cmd.Context(), token, larksdk.MessageRequest{
+						ReceiveID:     message.ReceiveID,
+						ReceiveIDType: message.ReceiveIDType,
+						MsgType:       message.MsgType,
+						Content:       message.Content,
+						UUID:          message.UUID,
+					})
+					if err != nil {
+						result.Error = err.Error()
+						message.Attempts++
+						message.LastError = err.Error()
+						var sendErr *larksdk.SendMessageError
+						if message.Attempts >= scheduleMaxAttempts || (errors.As(err, &sendErr) && schedulePermanentErrorCodes[sendErr.Code]) {
+							message.Failed = true
+						}
+						failed++
+					} else {
+						queue.Messages = append(queue.Messages[:i], queue.Messages[i+1:]...)
+						i--
+					}
+					dispatched = append(dispatched, result)
+					if err := save(); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			lines := make([]string, 0, len(dispatched))
+			for _, result := range dispatched {
+				status := result.MessageID
+				if result.Error != "" {
+					status = "error: " + result.Error
+				}
+				lines = append(lines, fmt.Sprintf("%s\t%s\t%s", result.ID, result.ReceiveID, status))
+			}
+			payload := map[string]any{"dispatched": dispatched}
+			if err := state.Printer.Print(payload, tableText([]string{"id", "receive_id", "message_id"}, lines, "no messages due")); err != nil {
+				return err
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d scheduled messages failed", failed, len(dispatched))
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+// enqueueScheduledMessage adds a message to the queue and returns it with its
+// ID filled in.
+func enqueueScheduledMessage(state *appState, message scheduledMessage) (scheduledMessage, error) {
+	path, err := scheduleQueuePath(state)
+	if err != nil {
+		return scheduledMessage{}, err
+	}
+	id, err := newScheduleID()
+	if err != nil {
+		return scheduledMessage{}, err
+	}
+	message.ID = id
+	if message.UUID == "" {
+		message.UUID = "lark-" + id
+	}
+	message.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+	err = withScheduleQueue(path, func(queue *scheduleQueue, save func() error) error {
+		queue.Messages = append(queue.Messages, message)
+		sort.SliceStable(queue.Messages, func(i, j int) bool {
+			return queue.Messages[i].SendAt < queue.Messages[j].SendAt
+		})
+		return save()
+	})
+	return message, err
+}
+
+func scheduleQueuePath(state *appState) (string, error) {
+	if state == nil || state.ConfigPath == "" {
+		return "", errors.New("config path is required for scheduled messages")
+	}
+	return filepath.Join(filepath.Dir(state.ConfigPath), scheduleQueueFileName), nil
+}
+
+// withScheduleQueue loads the queue under a lock file so an overlapping
+// schedule run (or send --at) cannot lose or double-send messages.
+func withScheduleQueue(path string, fn func(queue *scheduleQueue, save func() error) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	lockPath := path + ".lock"
+	lock, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if errors.Is(err, os.ErrExist) {
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > scheduleLockStaleAfter {
+			_ = os.Remove(lockPath)
+			lock, err = os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		}
+	}
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("scheduled message queue is locked by another run (remove %s if it is stale)", lockPath)
+		}
+		return err
+	}
+	_ = lock.Close()
+	defer os.Remove(lockPath)
+
+	queue, err := loadScheduleQueue(path)
+	if err != nil {
+		return err
+	}
+	return fn(queue, func() error { return saveScheduleQueue(path, queue) })
+}
+
+func loadScheduleQueue(path string) (*scheduleQueue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &scheduleQueue{Messages: []scheduledMessage{}}, nil
+		}
+		return nil, err
+	}
+	var queue scheduleQueue
+	if err := json.Unmarshal(data, &queue); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if queue.Messages == nil {
+		queue.Messages = []scheduledMessage{}
+	}
+	return &queue, nil
+}
+
+func saveScheduleQueue(path string, queue *scheduleQueue) error {
+	data, err := json.MarshalIndent(queue, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func newScheduleID() (string, error) {
+	var buf [4]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", err
+	}
+	return "sch_" + hex.EncodeToString(buf[:]), nil
+}
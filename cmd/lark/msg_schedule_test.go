@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMsgScheduleSendListRun(t *testing.T) {
+	var sent []map[string]string
+	var uuids []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/open-apis/im/v1/messages" {
+			t.Fatalf("unexpected request: %s", r.URL.Path)
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		sent = append(sent, body)
+		uuids = append(uuids, body["uuid"])
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"message_id": "om_sent"}})
+	})
+	state, buf := newTestState(t, handler, true)
+	state.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+
+	for _, args := range [][]string{
+		{"send", "oc_1", "--text", "standup", "--at", "+1h"},
+		{"send", "oc_2", "--text", "retro", "--at", "+2h"},
+	} {
+		cmd := newMsgCmd(state)
+		cmd.SetArgs(args)
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("send --at error: %v", err)
+		}
+	}
+	if len(sent) != 0 {
+		t.Fatalf("send --at should not send immediately")
+	}
+
+	queuePath := filepath.Join(filepath.Dir(state.ConfigPath), scheduleQueueFileName)
+	queue, err := loadScheduleQueue(queuePath)
+	if err != nil || len(queue.Messages) != 2 {
+		t.Fatalf("unexpected queue: %+v %v", queue, err)
+	}
+	if queue.Messages[0].ReceiveID != "oc_1" || queue.Messages[0].Content != `{"text":"standup"}` {
+		t.Fatalf("unexpected queued message: %+v", queue.Messages[0])
+	}
+	// Make the first message due.
+	queue.Messages[0].SendAt = time.Now().Add(-time.Minute).Unix()
+	if err := saveScheduleQueue(queuePath, queue); err != nil {
+		t.Fatalf("save queue: %v", err)
+	}
+
+	buf.Reset()
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"schedule", "run"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("schedule run error: %v", err)
+	}
+	if len(sent) != 1 || sent[0]["receive_id"] != "oc_1" || uuids[0] != "lark-"+queue.Messages[0].ID {
+		t.Fatalf("unexpected sends: %v %v", sent, uuids)
+	}
+	if !strings.Contains(buf.String(), "om_sent") {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+
+	remaining, _ := loadScheduleQueue(queuePath)
+	if len(remaining.Messages) != 1 || remaining.Messages[0].ReceiveID != "oc_2" {
+		t.Fatalf("unexpected remaining queue: %+v", remaining.Messages)
+	}
+	cmd = newMsgCmd(state)
+	cmd.SetArgs([]string{"schedule", "cancel", remaining.Messages[0].ID})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("cancel error: %v", err)
+	}
+	if final, _ := loadScheduleQueue(queuePath); len(final.Messages) != 0 {
+		t.Fatalf("queue should be empty: %+v", final.Messages)
+	}
+	if _, err := os.Stat(queuePath + ".lock"); !os.IsNotExist(err) {
+		t.Fatalf("lock file should be removed: %v", err)
+	}
+}
+
+func TestMsgScheduleRejectsPastTimeAndAttachments(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s", r.URL.Path)
+	})
+	state, _ := newTestState(t, handler, true)
+	state.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+
+	for args, want := range map[string]string{
+		"send oc_1 --text hi --at -1h":           "must be in the future",
+		"send oc_1 --file ./report.pdf --at +1h": "--at cannot be combined",
+		"schedule cancel sch_missing":            "no scheduled message with id sch_missing",
+	} {
+		cmd := newMsgCmd(state)
+		cmd.SetArgs(strings.Fields(args))
+		if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: expected %q, got %v", args, want, err)
+		}
+	}
+}
+
+func TestMsgScheduleRunStopsRetryingFailedMessages(t *testing.T) {
+	var sent []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/open-apis/im/v1/messages" {
+			t.Fatalf("unexpected request: %s", r.URL.Path)
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		sent = append(sent, body["receive_id"])
+		w.Header().Set("Content-Type", "application/json")
+		if body["receive_id"] == "oc_gone" {
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 230002, "msg": "Bot is not in the chat"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 230020, "msg": "frequency limit"})
+	})
+	state, buf := newTestState(t, handler, true)
+	state.ConfigPath = filepath.Join(t.TempDir(), "config.json")
+
+	due := time.Now().Add(-time.Minute).Unix()
+	queuePath := filepath.Join(filepath.Dir(state.ConfigPath), scheduleQueueFileName)
+	if err := saveScheduleQueue(queuePath, &scheduleQueue{Messages: []scheduledMessage{
+		{ID: "sch_gone", SendAt: due, ReceiveID: "oc_gone", ReceiveIDType: "chat_id", MsgType: "text", Content: `{"text":"a"}`},
+		{ID: "sch_last", SendAt: due, ReceiveID: "oc_last", ReceiveIDType: "chat_id", MsgType: "text", Content: `{"text":"b"}`, Attempts: scheduleMaxAttempts - 1},
+		{ID: "sch_busy", SendAt: due, ReceiveID: "oc_busy", ReceiveIDType: "chat_id", MsgType: "text", Content: `{"text":"c"}`},
+	}}); err != nil {
+		t.Fatalf("save queue: %v", err)
+	}
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"schedule", "run"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "3 of 3 scheduled messages failed") {
+		t.Fatalf("expected failures, got %v", err)
+	}
+	queue, _ := loadScheduleQueue(queuePath)
+	if len(queue.Messages) != 3 || !queue.Messages[0].Failed || !queue.Messages[1].Failed || queue.Messages[2].Failed || queue.Messages[2].Attempts != 1 {
+		t.Fatalf("unexpected queue: %+v", queue.Messages)
+	}
+
+	sent = nil
+	cmd = newMsgCmd(state)
+	cmd.SetArgs([]string{"schedule", "run"})
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected the retry to fail again")
+	}
+	if strings.Join(sent, ",") != "oc_busy" {
+		t.Fatalf("failed messages should not be sent again: %v", sent)
+	}
+
+	buf.Reset()
+	state.Printer.JSON = false
+	cmd = newMsgCmd(state)
+	cmd.SetArgs([]string{"schedule", "list"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("schedule list error: %v", err)
+	}
+	for _, want := range []string{"failed 1x: send message failed: Bot is not in the chat", "failed 5x", "retrying, 2x"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("missing %q in list output:\n%s", want, buf.String())
+		}
+	}
+}
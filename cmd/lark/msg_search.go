@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"os"
 	"strings"
 	"time"
@@ -23,9 +24,8 @@ func newMsgSearchCmd(state *appState) *cobra.Command {
 	var chatType string
 	var startTime string
 	var endTime string
-	var limit int
+	var paging listPaging
 	var pageSize int
-	var pages int
 	var userIDType string
 	var userAccessToken string
 
@@ -49,15 +49,12 @@ func newMsgSearchCmd(state *appState) *cobra.Command {
 			return nil
 		},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				return flagUsage(cmd, "limit must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			if pageSize < 0 {
 				return flagUsage(cmd, "page-size must be greater than or equal to 0")
 			}
-			if pages <= 0 {
-				return flagUsage(cmd, "pages must be greater than 0")
-			}
 			if _, err := requireSDK(state); err != nil {
 				return err
 			}
@@ -97,29 +94,15 @@ func newMsgSearchCmd(state *appState) *cobra.Command {
 				}
 			}
 
-			items := make([]string, 0, limit)
-			pageToken := ""
-			pageCount := 0
-			remaining := limit
-			for {
-				if pageCount >= pages {
-					break
-				}
-				pageCount++
-				size := remaining
+			// Search returns IDs only; they are buffered and then resolved to
+			// full messages, which are streamed one by one with --output ndjson.
+			ids := &listCollector[string]{state: state, limit: paging.itemLimit()}
+			pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[string], error) {
+				size := maxMessageSearchPageSize
 				if pageSize > 0 {
-					size = pageSize
-				}
-				if size > maxMessageSearchPageSize {
-					size = maxMessageSearchPageSize
+					size = min(pageSize, maxMessageSearchPageSize)
 				}
-				if size > remaining {
-					size = remaining
-				}
-				if size <= 0 {
-					break
-				}
-				result, err := state.SDK.SearchMessages(cmd.Context(), token, larksdk.MessageSearchRequest{
+				result, err := state.SDK.SearchMessages(ctx, token, larksdk.MessageSearchRequest{
 					Query:        query,
 					FromIDs:      fromIDs,
 					ChatIDs:      chatIDs,
@@ -129,35 +112,38 @@ func newMsgSearchCmd(state *appState) *cobra.Command {
 					ChatType:     chatType,
 					StartTime:    startTime,
 					EndTime:      endTime,
-					PageSize:     size,
+					PageSize:     ids.pageSize(size),
 					PageToken:    pageToken,
 					UserIDType:   userIDType,
 				})
 				if err != nil {
-					return withUserScopeHintForCommand(state, err)
+					return larksdk.Page[string]{}, withUserScopeHintForCommand(state, err)
 				}
-				items = append(items, result.Items...)
-				if len(items) >= limit || !result.HasMore {
-					break
-				}
-				remaining = limit - len(items)
-				pageToken = result.PageToken
-				if strings.TrimSpace(pageToken) == "" {
-					break
-				}
-			}
-			if len(items) > limit {
-				items = items[:limit]
+				return larksdk.Page[string]{Items: result.Items, PageToken: result.PageToken, HasMore: result.HasMore}, nil
+			})
+			if err := drainPages(cmd.Context(), state, ids, pager, paging.pageLimit()); err != nil {
+				return err
 			}
+			items := ids.items
 
+			streaming := state.Printer.Streaming()
 			messages := make([]larksdk.Message, 0, len(items))
 			for _, messageID := range items {
 				message, err := state.SDK.GetMessage(cmd.Context(), token, messageID, userIDType)
 				if err != nil {
 					return withUserScopeHintForCommand(state, err)
 				}
+				if streaming {
+					if err := state.Printer.PrintItems([]larksdk.Message{message}); err != nil {
+						return err
+					}
+					continue
+				}
 				messages = append(messages, message)
 			}
+			if streaming {
+				return nil
+			}
 
 			payload := map[string]any{
 				"message_ids": items,
@@ -189,9 +175,10 @@ func newMsgSearchCmd(state *appState) *cobra.Command {
 	cmd.Flags().StringVar(&chatType, "chat-type", "", "chat type (group_chat or p2p_chat)")
 	cmd.Flags().StringVar(&startTime, "start-time", "", "start time (unix seconds, RFC3339, or relative like -24h/-7d)")
 	cmd.Flags().StringVar(&endTime, "end-time", "", "end time (unix seconds, RFC3339, or relative like -24h/-7d)")
-	cmd.Flags().IntVar(&limit, "limit", 50, "max number of message IDs to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 50, "max number of message IDs to return")
 	cmd.Flags().IntVar(&pageSize, "page-size", 0, "page size per request (default: auto)")
-	cmd.Flags().IntVar(&pages, "pages", 1, "max number of pages to fetch")
+	cmd.Flags().IntVar(&paging.pages, "pages", 1, "max number of pages to fetch")
+	addPagingFlags(cmd, &paging)
 	cmd.Flags().StringVar(&userIDType, "user-id-type", "open_id", "user id type (open_id, union_id, user_id)")
 	cmd.Flags().StringVar(&userAccessToken, "user-access-token", "", "user access token (OAuth)")
 	return cmd
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+	"lark/internal/output"
+)
+
+// threadedMessage is a message with its thread replies nested beneath it.
+// Without replies it marshals exactly like larksdk.Message.
+type threadedMessage struct {
+	larksdk.Message
+	Replies []larksdk.Message `json:"replies,omitempty"`
+}
+
+func unthreadedMessages(messages []larksdk.Message) []threadedMessage {
+	out := make([]threadedMessage, 0, len(messages))
+	for _, message := range messages {
+		out = append(out, threadedMessage{Message: message})
+	}
+	return out
+}
+
+func flattenThreadedMessages(messages []threadedMessage) []larksdk.Message {
+	out := make([]larksdk.Message, 0, len(messages))
+	for _, message := range messages {
+		out = append(out, message.Message)
+		out = append(out, message.Replies...)
+	}
+	return out
+}
+
+// threadNester nests thread replies under their root page by page. Each thread
+// is expanded where its first message shows up, whether that is the root or a
+// reply (with ByCreateTimeDesc or a thread spanning pages the root can come
+// later). Every other message of an expanded thread is dropped from the top
+// level so it is not shown twice.
+type threadNester struct {
+	state    *appState
+	token    string
+	expanded map[string]bool
+}
+
+func newThreadNester(state *appState, token string) *threadNester {
+	return &threadNester{state: state, token: token, expanded: map[string]bool{}}
+}
+
+func (n *threadNester) nest(ctx context.Context, messages []larksdk.Message) ([]threadedMessage, error) {
+	out := make([]threadedMessage, 0, len(messages))
+	for _, message := range messages {
+		if message.ThreadID == "" {
+			out = append(out, threadedMessage{Message: message})
+			continue
+		}
+		if n.expanded[message.ThreadID] {
+			continue
+		}
+		n.expanded[message.ThreadID] = true
+		item, err := n.expand(ctx, message)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+// expand lists the thread of message and splits it into its root and replies.
+// When the root is not in the thread listing, message stands in for it.
+func (n *threadNester) expand(ctx context.Context, message larksdk.Message) (threadedMessage, error) {
+	messages, err := listAllMessages(ctx, n.state, n.token, larksdk.ListMessagesRequest{ContainerIDType: "thread", ContainerID: message.ThreadID})
+	if err != nil {
+		return threadedMessage{}, fmt.Errorf("thread %s: %w", message.ThreadID, err)
+	}
+	root := message
+	if !isThreadRoot(message) {
+		for _, candidate := range messages {
+			if candidate.MessageID == message.RootID || (message.RootID == "" && isThreadRoot(candidate)) {
+				root = candidate
+				break
+			}
+		}
+	}
+	item := threadedMessage{Message: root}
+	for _, candidate := range messages {
+		if candidate.MessageID != root.MessageID {
+			item.Replies = append(item.Replies, candidate)
+		}
+	}
+	return item, nil
+}
+
+func isThreadRoot(message larksdk.Message) bool {
+	return message.ThreadID != "" && (message.RootID == "" || message.RootID == message.MessageID)
+}
+
+// listThreadReplies lists a thread oldest first, leaving out its root.
+func listThreadReplies(ctx context.Context, state *appState, token, threadID, rootID string) ([]larksdk.Message, error) {
+	messages, err := listAllMessages(ctx, state, token, larksdk.ListMessagesRequest{ContainerIDType: "thread", ContainerID: threadID})
+	if err != nil {
+		return nil, fmt.Errorf("thread %s: %w", threadID, err)
+	}
+	replies := make([]larksdk.Message, 0, len(messages))
+	for _, message := range messages {
+		if message.MessageID != rootID {
+			replies = append(replies, message)
+		}
+	}
+	return replies, nil
+}
+
+func newMsgThreadCmd(state *appState) *cobra.Command {
+	var messageID string
+
+	cmd := &cobra.Command{
+		Use:   "thread <message-id>",
+		Short: "Show a message's thread: the root and all replies",
+		Long: `Show the conversation a message belongs to. The message may be the thread
+root or any reply; its thread_id (or root_id) is resolved and the root is
+printed followed by every reply, oldest first.`,
+		Example: `  lark messages thread <MESSAGE_ID>`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			messageID = strings.TrimSpace(args[0])
+			if messageID == "" {
+				return argsUsageError(cmd, errors.New("message-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWithToken(cmd, state, tokenTypesTenantOrUser, nil, func(ctx context.Context, sdk *larksdk.Client, token string, tokenType tokenType) (any, string, error) {
+				thread, err := resolveMessageThread(ctx, state, token, larksdk.AccessTokenType(tokenType), messageID)
+				if err != nil {
+					return nil, "", err
+				}
+				payload := map[string]any{"thread_id": thread.ThreadID, "root": thread.Message, "replies": thread.Replies}
+				if thread.Replies == nil {
+					payload["replies"] = []larksdk.Message{}
+				}
+				var senderNames map[string]string
+				if !state.JSON && tokenType == tokenTypeTenant {
+					senderNames = resolveMessageSenderNames(ctx, state, token, flattenThreadedMessages([]threadedMessage{thread}))
+				}
+				styles := newMessageFormatStyles(state.Printer.Styled)
+				displays := []messageDisplay{buildMessageDisplay(thread.Message, styles, senderNames)}
+				for _, reply := range thread.Replies {
+					display := buildMessageDisplay(reply, styles, senderNames)
+					display.reply = true
+					displays = append(displays, display)
+				}
+				text := renderMessageTable(displays, styles)
+				if len(thread.Replies) == 0 {
+					text += "\n" + output.Notice(output.NoticeInfo, "no replies", nil)
+				}
+				return payload, text, nil
+			})
+		},
+	}
+	return cmd
+}
+
+// resolveMessageThread finds the root of the thread a message belongs to and
+// its replies. A reply outside a thread only has a root_id, so the root is
+// fetched and the reply itself is the only reply that can be listed.
+func resolveMessageThread(ctx context.Context, state *appState, token string, tokenType larksdk.AccessTokenType, messageID string) (threadedMessage, error) {
+	message, err := state.SDK.GetMessageWithToken(ctx, token, tokenType, messageID, "")
+	if err != nil {
+		return threadedMessage{}, err
+	}
+	root := message
+	if message.RootID != "" && message.RootID != message.MessageID {
+		root, err = state.SDK.GetMessageWithToken(ctx, token, tokenType, message.RootID, "")
+		if err != nil {
+			return threadedMessage{}, fmt.Errorf("root message %s: %w", message.RootID, err)
+		}
+	}
+	threadID := root.ThreadID
+	if threadID == "" {
+		threadID = message.ThreadID
+	}
+	if threadID == "" {
+		thread := threadedMessage{Message: root}
+		if root.MessageID != message.MessageID {
+			thread.Replies = []larksdk.Message{message}
+		}
+		return thread, nil
+	}
+	root.ThreadID = threadID
+	replies, err := listThreadReplies(ctx, state, token, threadID, root.MessageID)
+	if err != nil {
+		return threadedMessage{}, err
+	}
+	return threadedMessage{Message: root, Replies: replies}, nil
+}
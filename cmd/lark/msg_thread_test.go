@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMsgListWithThreadsNestsReplies(t *testing.T) {
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query()
+		requests = append(requests, query.Get("container_id_type")+":"+query.Get("container_id"))
+		switch {
+		case r.URL.Path == "/open-apis/im/v1/messages" && query.Get("container_id_type") == "chat":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+				{"message_id": "om_root", "thread_id": "omt_1", "msg_type": "text", "body": map[string]any{"content": `{"text":"deploy?"}`}},
+				{"message_id": "om_r1", "root_id": "om_root", "thread_id": "omt_1", "msg_type": "text", "body": map[string]any{"content": `{"text":"yes"}`}},
+				{"message_id": "om_plain", "msg_type": "text", "body": map[string]any{"content": `{"text":"lunch"}`}},
+			}}})
+		case r.URL.Path == "/open-apis/im/v1/messages" && query.Get("container_id_type") == "thread":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+				{"message_id": "om_root", "thread_id": "omt_1", "msg_type": "text", "body": map[string]any{"content": `{"text":"deploy?"}`}},
+				{"message_id": "om_r1", "root_id": "om_root", "thread_id": "omt_1", "msg_type": "text", "body": map[string]any{"content": `{"text":"yes"}`}},
+				{"message_id": "om_r2", "root_id": "om_root", "thread_id": "omt_1", "msg_type": "text", "body": map[string]any{"content": `{"text":"shipped"}`}},
+			}}})
+		default:
+			t.Fatalf("unexpected request: %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"list", "oc_1", "--with-threads"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("list error: %v", err)
+	}
+	if strings.Join(requests, " ") != "chat:oc_1 thread:omt_1" {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	var payload struct {
+		Messages []threadedMessage `json:"messages"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(payload.Messages) != 2 || payload.Messages[0].MessageID != "om_root" || payload.Messages[1].MessageID != "om_plain" {
+		t.Fatalf("unexpected top-level messages: %+v", payload.Messages)
+	}
+	replies := payload.Messages[0].Replies
+	if len(replies) != 2 || replies[0].MessageID != "om_r1" || replies[1].MessageID != "om_r2" {
+		t.Fatalf("unexpected replies: %+v", replies)
+	}
+	if payload.Messages[1].Replies != nil {
+		t.Fatalf("plain message should have no replies")
+	}
+}
+
+func TestMsgListWithThreadsStyledOutput(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query()
+		switch {
+		case r.URL.Path == "/open-apis/im/v1/messages" && query.Get("container_id_type") == "chat":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+				{"message_id": "om_root", "thread_id": "omt_1", "msg_type": "text", "body": map[string]any{"content": `{"text":"deploy?"}`}},
+				{"message_id": "om_plain", "msg_type": "text", "body": map[string]any{"content": `{"text":"lunch"}`}},
+			}}})
+		case r.URL.Path == "/open-apis/im/v1/messages" && query.Get("container_id") == "omt_1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+				{"message_id": "om_root", "thread_id": "omt_1", "msg_type": "text", "body": map[string]any{"content": `{"text":"deploy?"}`}},
+				{"message_id": "om_r1", "root_id": "om_root", "thread_id": "omt_1", "msg_type": "text", "body": map[string]any{"content": `{"text":"shipped"}`}},
+			}}})
+		default:
+			t.Fatalf("unexpected request: %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+	})
+	state, buf := newTestState(t, handler, false)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"list", "oc_1", "--with-threads"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("list error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"thread: omt_1", "↳ ", "shipped"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Index(out, "shipped") > strings.Index(out, "lunch") {
+		t.Fatalf("replies should be listed under their root:\n%s", out)
+	}
+}
+
+func TestMsgThreadFromReply(t *testing.T) {
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		requests = append(requests, r.URL.Path)
+		switch r.URL.Path {
+		case "/open-apis/im/v1/messages/om_r2":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"items": []map[string]any{
+				{"message_id": "om_r2", "root_id": "om_root", "thread_id": "omt_1", "msg_type": "text", "body": map[string]any{"content": `{"text":"shipped"}`}},
+			}}})
+		case "/open-apis/im/v1/messages/om_root":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"items": []map[string]any{
+				{"message_id": "om_root", "thread_id": "omt_1", "msg_type": "text", "body": map[string]any{"content": `{"text":"deploy?"}`}},
+			}}})
+		case "/open-apis/im/v1/messages":
+			if r.URL.Query().Get("container_id") != "omt_1" {
+				t.Fatalf("unexpected thread: %s", r.URL.RawQuery)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+				{"message_id": "om_root", "thread_id": "omt_1", "msg_type": "text", "body": map[string]any{"content": `{"text":"deploy?"}`}},
+				{"message_id": "om_r1", "root_id": "om_root", "thread_id": "omt_1", "msg_type": "text", "body": map[string]any{"content": `{"text":"yes"}`}},
+				{"message_id": "om_r2", "root_id": "om_root", "thread_id": "omt_1", "msg_type": "text", "body": map[string]any{"content": `{"text":"shipped"}`}},
+			}}})
+		default:
+			t.Fatalf("unexpected request: %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"thread", "om_r2"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("thread error: %v", err)
+	}
+	if strings.Join(requests, " ") != "/open-apis/im/v1/messages/om_r2 /open-apis/im/v1/messages/om_root /open-apis/im/v1/messages" {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	var payload struct {
+		ThreadID string `json:"thread_id"`
+		Root     struct {
+			MessageID string `json:"message_id"`
+		} `json:"root"`
+		Replies []struct {
+			MessageID string `json:"message_id"`
+		} `json:"replies"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if payload.ThreadID != "omt_1" || payload.Root.MessageID != "om_root" || len(payload.Replies) != 2 || payload.Replies[1].MessageID != "om_r2" {
+		t.Fatalf("unexpected payload: %s", buf.String())
+	}
+}
+
+func TestMsgListWithThreadsDescAcrossPages(t *testing.T) {
+	root := map[string]any{"message_id": "om_root", "thread_id": "omt_1", "msg_type": "text", "body": map[string]any{"content": `{"text":"deploy?"}`}}
+	r1 := map[string]any{"message_id": "om_r1", "root_id": "om_root", "thread_id": "omt_1", "msg_type": "text", "body": map[string]any{"content": `{"text":"yes"}`}}
+	r2 := map[string]any{"message_id": "om_r2", "root_id": "om_root", "thread_id": "omt_1", "msg_type": "text", "body": map[string]any{"content": `{"text":"shipped"}`}}
+	plain := map[string]any{"message_id": "om_plain", "msg_type": "text", "body": map[string]any{"content": `{"text":"lunch"}`}}
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query()
+		requests = append(requests, query.Get("container_id_type")+":"+query.Get("page_token"))
+		switch {
+		case r.URL.Path == "/open-apis/im/v1/messages" && query.Get("container_id_type") == "chat":
+			if query.Get("sort_type") != "ByCreateTimeDesc" {
+				t.Fatalf("unexpected sort: %s", r.URL.RawQuery)
+			}
+			// Newest first: the thread's replies arrive a page before its root.
+			if query.Get("page_token") == "" {
+				_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": true, "page_token": "p2", "items": []map[string]any{r2, plain}}})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{r1, root}}})
+		case r.URL.Path == "/open-apis/im/v1/messages" && query.Get("container_id_type") == "thread":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{root, r1, r2}}})
+		default:
+			t.Fatalf("unexpected request: %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"list", "oc_1", "--with-threads", "--sort", "ByCreateTimeDesc", "--page-size", "2"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("list error: %v", err)
+	}
+	if strings.Join(requests, " ") != "chat: thread: chat:p2" {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	var payload struct {
+		Messages []threadedMessage `json:"messages"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(payload.Messages) != 2 || payload.Messages[0].MessageID != "om_root" || payload.Messages[1].MessageID != "om_plain" {
+		t.Fatalf("unexpected top-level messages: %+v", payload.Messages)
+	}
+	replies := payload.Messages[0].Replies
+	if len(replies) != 2 || replies[0].MessageID != "om_r1" || replies[1].MessageID != "om_r2" {
+		t.Fatalf("unexpected replies: %+v", replies)
+	}
+}
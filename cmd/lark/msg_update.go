@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newMsgUpdateCmd(state *appState) *cobra.Command {
+	var messageID string
+	var cardOpts cardContentOptions
+
+	cmd := &cobra.Command{
+		Use:   "update <message-id>",
+		Short: "Update a sent card in place",
+		Long: `Replace the content of an interactive card message.
+
+Only cards sent by this app can be updated. For everyone in a group to see
+the update, the card must have been sent with config.update_multi=true (the
+cards YAML shorthand sets it by default).`,
+		Example: `  lark messages update <MESSAGE_ID> --card ./status.yaml
+  lark messages update <MESSAGE_ID> --template-id AAqk1234 --var stage=deploy`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			messageID = strings.TrimSpace(args[0])
+			if messageID == "" {
+				return argsUsageError(cmd, errors.New("message-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			content, err := resolveCardContent(cardOpts)
+			if err != nil {
+				return err
+			}
+			if _, err := requireSDK(state); err != nil {
+				return err
+			}
+			token, err := tokenFor(cmd.Context(), state, tokenTypesTenantOrUser)
+			if err != nil {
+				return err
+			}
+			if err := state.SDK.PatchMessageCard(cmd.Context(), token, messageID, content); err != nil {
+				return err
+			}
+			payload := map[string]any{"message_id": messageID, "updated": true}
+			return state.Printer.Print(payload, fmt.Sprintf("updated: %s", messageID))
+		},
+	}
+
+	addCardContentFlags(cmd, &cardOpts)
+	return cmd
+}
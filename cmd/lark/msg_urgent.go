@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+var urgentModes = []string{"app", "sms", "phone"}
+
+func newMsgUrgentCmd(state *appState) *cobra.Command {
+	var messageID string
+	var userIDs []string
+	var userIDType string
+	var mode string
+
+	cmd := &cobra.Command{
+		Use:   "urgent <message-id>",
+		Short: "Buzz users about a message (in-app, SMS, or phone call)",
+		Long: `Send an urgent ("buzz") notification for a message the app sent.
+
+- app: in-app buzz.
+- sms: in-app buzz plus an SMS.
+- phone: in-app buzz plus a phone call.
+
+The users must be members of the chat the message was sent in. SMS and phone
+buzzes use the tenant's urgent quota.`,
+		Example: `  lark messages urgent <MESSAGE_ID> --user-id ou_xxx
+  lark messages urgent <MESSAGE_ID> --user-id ou_xxx,ou_yyy --mode phone`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			messageID = strings.TrimSpace(args[0])
+			if messageID == "" {
+				return argsUsageError(cmd, errors.New("message-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mode = strings.ToLower(strings.TrimSpace(mode))
+			if !containsString(urgentModes, mode) {
+				return flagUsage(cmd, "mode must be one of app, sms, phone")
+			}
+			switch userIDType {
+			case "open_id", "union_id", "user_id":
+			default:
+				return flagUsage(cmd, "user-id-type must be one of open_id, union_id, user_id")
+			}
+			ids := make([]string, 0, len(userIDs))
+			for _, id := range userIDs {
+				if id = strings.TrimSpace(id); id != "" {
+					ids = append(ids, id)
+				}
+			}
+			if len(ids) == 0 {
+				return usageError(cmd, "--user-id is required", "")
+			}
+			var invalid []string
+			err := runWithToken(cmd, state, tokenTypesTenant, nil, func(ctx context.Context, sdk *larksdk.Client, token string, _ tokenType) (any, string, error) {
+				var err error
+				invalid, err = sdk.UrgentMessage(ctx, token, larksdk.UrgentMessageRequest{
+					MessageID:  messageID,
+					Mode:       mode,
+					UserIDType: userIDType,
+					UserIDs:    ids,
+				})
+				if err != nil {
+					return nil, "", err
+				}
+				payload := map[string]any{"message_id": messageID, "mode": mode, "user_ids": ids, "invalid_user_ids": invalid}
+				text := fmt.Sprintf("buzzed %d users (%s) about %s", len(ids)-len(invalid), mode, messageID)
+				if len(invalid) > 0 {
+					text += "\ninvalid: " + strings.Join(invalid, ", ")
+				}
+				return payload, text, nil
+			})
+			if err != nil {
+				return err
+			}
+			if len(invalid) > 0 {
+				return fmt.Errorf("%d of %d users could not be buzzed", len(invalid), len(ids))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&userIDs, "user-id", nil, "users to buzz (repeatable or comma-separated)")
+	cmd.Flags().StringVar(&userIDType, "user-id-type", "open_id", "user ID type for --user-id (open_id, union_id, user_id)")
+	cmd.Flags().StringVar(&mode, "mode", "app", "how to buzz (app, sms, phone)")
+	registerEnumCompletion(cmd, "mode", urgentModes)
+	return cmd
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMsgUrgentPhone(t *testing.T) {
+	var body struct {
+		UserIDList []string `json:"user_id_list"`
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/open-apis/im/v1/messages/om_1/urgent_phone" || r.URL.Query().Get("user_id_type") != "user_id" {
+			t.Fatalf("unexpected request: %s %s?%s", r.Method, r.URL.Path, r.URL.RawQuery)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"invalid_user_id_list": []string{}}})
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"urgent", "om_1", "--user-id", "u1,u2", "--user-id-type", "user_id", "--mode", "phone"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("urgent error: %v", err)
+	}
+	if strings.Join(body.UserIDList, ",") != "u1,u2" {
+		t.Fatalf("unexpected body: %+v", body)
+	}
+	if !strings.Contains(buf.String(), `"mode": "phone"`) {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestMsgUrgentRejectsUnknownMode(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s", r.URL.Path)
+	})
+	state, _ := newTestState(t, handler, true)
+
+	cmd := newMsgCmd(state)
+	cmd.SetArgs([]string{"urgent", "om_1", "--user-id", "u1", "--mode", "pager"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "mode must be one of") {
+		t.Fatalf("expected mode error, got %v", err)
+	}
+}
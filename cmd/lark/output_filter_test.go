@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"lark/internal/output"
+)
+
+type filterTestChat struct {
+	ChatID string `json:"chat_id"`
+	Name   string `json:"name"`
+	Owner  struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"owner"`
+	MemberCount int64 `json:"member_count"`
+}
+
+func filterTestPayload() map[string]any {
+	a := filterTestChat{ChatID: "oc_1", Name: "Alpha", MemberCount: 1234567890123}
+	a.Owner.ID = "ou_1"
+	a.Owner.Name = "Ada"
+	b := filterTestChat{ChatID: "oc_2", Name: "Beta", MemberCount: 2}
+	b.Owner.ID = "ou_2"
+	return map[string]any{"chats": []filterTestChat{a, b}}
+}
+
+func printFiltered(t *testing.T, fields []string, jq, tmpl string) string {
+	t.Helper()
+	filter, err := output.NewFilter(fields, jq, tmpl)
+	if err != nil {
+		t.Fatalf("filter error: %v", err)
+	}
+	var buf bytes.Buffer
+	printer := output.Printer{Writer: &buf, Filter: filter}
+	if err := printer.Print(filterTestPayload(), "text output"); err != nil {
+		t.Fatalf("print error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestOutputFilterFieldsDescendIntoLists(t *testing.T) {
+	got := printFiltered(t, []string{"chat_id,owner.name"}, "", "")
+	var payload struct {
+		Chats []map[string]any `json:"chats"`
+	}
+	if err := json.Unmarshal([]byte(got), &payload); err != nil {
+		t.Fatalf("invalid json: %v\n%s", err, got)
+	}
+	if len(payload.Chats) != 2 || len(payload.Chats[0]) != 2 {
+		t.Fatalf("unexpected selection: %s", got)
+	}
+	owner := payload.Chats[0]["owner"].(map[string]any)
+	if payload.Chats[0]["chat_id"] != "oc_1" || owner["name"] != "Ada" || owner["id"] != nil {
+		t.Fatalf("unexpected selection: %s", got)
+	}
+}
+
+func TestOutputFilterJQ(t *testing.T) {
+	got := printFiltered(t, nil, ".chats[] | .name", "")
+	if got != "Alpha\nBeta\n" {
+		t.Fatalf("unexpected jq output: %q", got)
+	}
+	got = printFiltered(t, nil, ".chats[0].member_count", "")
+	if got != "1234567890123\n" {
+		t.Fatalf("integers should stay exact: %q", got)
+	}
+	got = printFiltered(t, []string{"chat_id"}, "[.chats[].chat_id]", "")
+	if strings.Join(strings.Fields(got), "") != `["oc_1","oc_2"]` {
+		t.Fatalf("unexpected jq output: %q", got)
+	}
+}
+
+func TestOutputFilterTemplate(t *testing.T) {
+	got := printFiltered(t, nil, ".chats[]", "{{.chat_id}} {{.name}} ({{.owner.id}})")
+	if got != "oc_1 Alpha (ou_1)\noc_2 Beta (ou_2)\n" {
+		t.Fatalf("unexpected template output: %q", got)
+	}
+	got = printFiltered(t, nil, "", `{{range .chats}}{{.chat_id}}={{.member_count}};{{end}}`)
+	if got != "oc_1=1234567890123;oc_2=2;\n" {
+		t.Fatalf("unexpected template output: %q", got)
+	}
+}
+
+func TestOutputFilterInvalidJQ(t *testing.T) {
+	if _, err := output.NewFilter(nil, ".chats[", ""); err == nil {
+		t.Fatalf("expected parse error")
+	}
+	filter, err := output.NewFilter(nil, "", "")
+	if err != nil || filter != nil {
+		t.Fatalf("expected nil filter, got %v %v", filter, err)
+	}
+}
+
+func TestAPICommandWithJQFilter(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"items": []any{map[string]any{"chat_id": "oc_1"}}}})
+	})
+	state, buf := newTestState(t, handler, true)
+	filter, err := output.NewFilter(nil, ".data.items[].chat_id", "")
+	if err != nil {
+		t.Fatalf("filter error: %v", err)
+	}
+	state.Printer.Filter = filter
+
+	cmd := newAPICmd(state)
+	cmd.SetArgs([]string{"GET", "im/v1/chats"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("api error: %v", err)
+	}
+	if buf.String() != "oc_1\n" {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestRootRejectsInvalidJQ(t *testing.T) {
+	cmd := newRootCmd()
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"--jq", ".[", "version"})
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "invalid --jq expression") {
+		t.Fatalf("expected jq usage error, got %v", err)
+	}
+}
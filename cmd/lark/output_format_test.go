@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"lark/internal/output"
+)
+
+func TestDriveListStreamsNDJSON(t *testing.T) {
+	var out bytes.Buffer
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page_token") {
+		case "":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"files":           []map[string]any{{"token": "f1", "name": "One", "type": "docx"}, {"token": "f2", "name": "Two, \"quoted\"", "type": "sheet"}},
+				"has_more":        true,
+				"next_page_token": "p2",
+			}})
+		case "p2":
+			if !strings.Contains(out.String(), `"token":"f2"`) {
+				t.Fatalf("first page should be written before the second page is fetched, got %q", out.String())
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"files":    []map[string]any{{"token": "f3", "name": "Three", "type": "file"}},
+				"has_more": false,
+			}})
+		default:
+			t.Fatalf("unexpected page token: %s", r.URL.RawQuery)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+	state.Printer.Writer = &out
+	state.Printer.Format = output.FormatNDJSON
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"list", "--limit", "10"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("drive list error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("unexpected buffered output: %q", buf.String())
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 ndjson lines, got %q", out.String())
+	}
+	var file map[string]any
+	if err := json.Unmarshal([]byte(lines[2]), &file); err != nil || file["token"] != "f3" {
+		t.Fatalf("unexpected last line %q: %v", lines[2], err)
+	}
+}
+
+func TestDriveListLimitAppliesWhenStreaming(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page_token") {
+		case "":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"files":           []map[string]any{{"token": "f1", "name": "One", "type": "docx"}, {"token": "f2", "name": "Two, \"quoted\"", "type": "sheet"}},
+				"has_more":        true,
+				"next_page_token": "p2",
+			}})
+		case "p2":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"files":    []map[string]any{{"token": "f3", "name": "Three", "type": "file"}},
+				"has_more": false,
+			}})
+		default:
+			t.Fatalf("unexpected page token: %s", r.URL.RawQuery)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+	state.Printer.Format = output.FormatNDJSON
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"list", "--limit", "1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("drive list error: %v", err)
+	}
+	if strings.Count(buf.String(), "\n") != 1 || !strings.Contains(buf.String(), `"token":"f1"`) {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestDriveListCSV(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page_token") {
+		case "":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"files":           []map[string]any{{"token": "f1", "name": "One", "type": "docx"}, {"token": "f2", "name": "Two, \"quoted\"", "type": "sheet"}},
+				"has_more":        true,
+				"next_page_token": "p2",
+			}})
+		case "p2":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"files":    []map[string]any{{"token": "f3", "name": "Three", "type": "file"}},
+				"has_more": false,
+			}})
+		default:
+			t.Fatalf("unexpected page token: %s", r.URL.RawQuery)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+	state.Printer.Format = output.FormatCSV
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"list", "--limit", "10"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("drive list error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "token,name,type") {
+		t.Fatalf("unexpected csv: %q", buf.String())
+	}
+	if !strings.HasPrefix(lines[2], `f2,"Two, ""quoted""",sheet`) {
+		t.Fatalf("csv should quote cells: %q", lines[2])
+	}
+}
+
+func TestPrinterFormats(t *testing.T) {
+	payload := map[string]any{
+		"calendar_id": "cal",
+		"events": []map[string]any{
+			{"event_id": "e1", "start": map[string]any{"timestamp": "1"}, "attendees": []string{"a", "b"}},
+		},
+	}
+	cases := map[string]string{
+		output.FormatTSV:    "attendees\tevent_id\tstart.timestamp\n[\"a\",\"b\"]\te1\t1\n",
+		output.FormatNDJSON: `{"attendees":["a","b"],"event_id":"e1","start":{"timestamp":"1"}}` + "\n",
+		output.FormatYAML:   "calendar_id: cal\nevents:\n  - attendees:\n      - a\n      - b\n    event_id: e1\n    start:\n      timestamp: \"1\"\n",
+	}
+	for format, want := range cases {
+		var buf bytes.Buffer
+		printer := output.Printer{Writer: &buf, JSON: true, Format: format}
+		if err := printer.Print(payload, "text"); err != nil {
+			t.Fatalf("%s: print error: %v", format, err)
+		}
+		if got := buf.String(); got != want {
+			t.Fatalf("%s: got %q, want %q", format, got, want)
+		}
+	}
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	if got, err := output.ParseFormat(" NDJSON "); err != nil || got != output.FormatNDJSON {
+		t.Fatalf("unexpected result: %q %v", got, err)
+	}
+	if _, err := output.ParseFormat("xml"); err == nil {
+		t.Fatalf("expected error for unknown format")
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+	"lark/internal/output"
+)
+
+// listPaging is the pagination contract shared by list/search commands:
+// --limit caps the items returned, --pages caps the requests made, and --all
+// lifts both so the listing is drained (bounded only by --max-items).
+type listPaging struct {
+	limit    int
+	pages    int
+	all      bool
+	maxItems int
+}
+
+// addPagingFlags registers --all and --max-items. Commands keep their own
+// --limit/--pages flags bound to paging.limit and paging.pages.
+func addPagingFlags(cmd *cobra.Command, paging *listPaging) {
+	cmd.Flags().BoolVar(&paging.all, "all", false, "fetch every page (ignores --limit and --pages)")
+	cmd.Flags().IntVar(&paging.maxItems, "max-items", 0, "safety cap on items fetched, including with --all (0 = no cap)")
+}
+
+func (p listPaging) validate(cmd *cobra.Command) error {
+	if p.maxItems < 0 {
+		return flagUsage(cmd, "max-items must be >= 0")
+	}
+	if p.all {
+		return nil
+	}
+	if p.limit <= 0 {
+		return flagUsage(cmd, "limit must be greater than 0")
+	}
+	if cmd.Flags().Lookup("pages") != nil && p.pages <= 0 {
+		return flagUsage(cmd, "pages must be greater than 0")
+	}
+	return nil
+}
+
+// itemLimit is the number of items to collect; 0 means no limit.
+func (p listPaging) itemLimit() int {
+	limit := p.limit
+	if p.all {
+		limit = 0
+	}
+	if p.maxItems > 0 && (limit <= 0 || p.maxItems < limit) {
+		limit = p.maxItems
+	}
+	return limit
+}
+
+// pageLimit is the number of requests allowed; 0 means no limit.
+func (p listPaging) pageLimit() int {
+	if p.all {
+		return 0
+	}
+	return p.pages
+}
+
+// listCollector accumulates paginated list items up to a limit. When the
+// printer streams (--output ndjson), each page is written as it arrives and
+// nothing is buffered, so large listings can be piped into other tools.
+type listCollector[T any] struct {
+	state     *appState
+	limit     int
+	streaming bool
+	items     []T
+	count     int
+}
+
+func newListCollector[T any](state *appState, limit int) *listCollector[T] {
+	c := &listCollector[T]{state: state, limit: limit, streaming: state.Printer.Streaming()}
+	if !c.streaming {
+		c.items = make([]T, 0, min(max(limit, 0), 1000))
+	}
+	return c
+}
+
+// add records one page, dropping anything beyond the limit.
+func (c *listCollector[T]) add(page []T) error {
+	if c.limit > 0 && len(page) > c.remaining() {
+		page = page[:c.remaining()]
+	}
+	c.count += len(page)
+	if c.streaming {
+		if len(page) == 0 {
+			return nil
+		}
+		return c.state.Printer.PrintItems(page)
+	}
+	c.items = append(c.items, page...)
+	return nil
+}
+
+func (c *listCollector[T]) remaining() int {
+	return max(c.limit-c.count, 0)
+}
+
+// pageSize is the page size to request next: what is still needed, capped by
+// the API maximum.
+func (c *listCollector[T]) pageSize(apiMax int) int {
+	if c.limit <= 0 {
+		return apiMax
+	}
+	return min(c.remaining(), apiMax)
+}
+
+func (c *listCollector[T]) full() bool {
+	return c.limit > 0 && c.count >= c.limit
+}
+
+// drainPages feeds pager into items until the item or page limit is reached
+// or the listing is exhausted. Progress is shown on stderr when it is a
+// terminal. On Ctrl-C the items fetched so far are kept: a warning is printed,
+// the command still renders its partial output, and the process exits
+// non-zero.
+func drainPages[T any](ctx context.Context, state *appState, items *listCollector[T], pager *larksdk.Paginator[T], maxPages int) error {
+	progress := newPageProgress(state)
+	defer progress.clear()
+	for !pager.Done() && !items.full() {
+		if maxPages > 0 && pager.Pages() >= maxPages {
+			break
+		}
+		page, err := pager.Next(ctx)
+		if err != nil {
+			if ctx.Err() != nil && pager.Pages() > 0 {
+				progress.clear()
+				fmt.Fprintf(errWriter(state), "WARNING: interrupted after %d pages; output has the %d items fetched so far\n", pager.Pages(), items.count)
+				state.interrupted = fmt.Errorf("interrupted: %w", ctx.Err())
+				return nil
+			}
+			return err
+		}
+		if err := items.add(page); err != nil {
+			return err
+		}
+		progress.update(pager.Pages(), items.count)
+	}
+	return nil
+}
+
+// pageProgress renders a single self-overwriting status line on stderr.
+type pageProgress struct {
+	state   *appState
+	enabled bool
+	shown   bool
+}
+
+func newPageProgress(state *appState) *pageProgress {
+	return &pageProgress{state: state, enabled: output.AutoStyle(errWriter(state))}
+}
+
+func (p *pageProgress) update(pages, items int) {
+	debugf(p.state, "fetched page %d (%d items)\n", pages, items)
+	if !p.enabled || p.state.Verbose {
+		return
+	}
+	fmt.Fprintf(errWriter(p.state), "\r\033[Kfetching: %d pages, %d items", pages, items)
+	p.shown = true
+}
+
+func (p *pageProgress) clear() {
+	if !p.shown {
+		return
+	}
+	fmt.Fprint(errWriter(p.state), "\r\033[K")
+	p.shown = false
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDriveListAllIgnoresLimit(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page_token") {
+		case "":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"files":           []map[string]any{{"token": "f1", "name": "One", "type": "docx"}, {"token": "f2", "name": "Two, \"quoted\"", "type": "sheet"}},
+				"has_more":        true,
+				"next_page_token": "p2",
+			}})
+		case "p2":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"files":    []map[string]any{{"token": "f3", "name": "Three", "type": "file"}},
+				"has_more": false,
+			}})
+		default:
+			t.Fatalf("unexpected page token: %s", r.URL.RawQuery)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"list", "--limit", "1", "--all"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("drive list error: %v", err)
+	}
+	var payload struct {
+		Files   []map[string]any `json:"files"`
+		HasMore bool             `json:"has_more"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(payload.Files) != 3 || payload.HasMore {
+		t.Fatalf("expected all 3 files, got %+v", payload)
+	}
+}
+
+func TestDriveListMaxItemsCapsAll(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page_token") {
+		case "":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"files":           []map[string]any{{"token": "f1", "name": "One", "type": "docx"}, {"token": "f2", "name": "Two, \"quoted\"", "type": "sheet"}},
+				"has_more":        true,
+				"next_page_token": "p2",
+			}})
+		case "p2":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"files":    []map[string]any{{"token": "f3", "name": "Three", "type": "file"}},
+				"has_more": false,
+			}})
+		default:
+			t.Fatalf("unexpected page token: %s", r.URL.RawQuery)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"list", "--all", "--max-items", "2"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("drive list error: %v", err)
+	}
+	var payload struct {
+		Files []map[string]any `json:"files"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if len(payload.Files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(payload.Files))
+	}
+}
+
+func TestDriveListRejectsNegativeMaxItems(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+	})
+	state, _ := newTestState(t, handler, true)
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"list", "--max-items", "-1"})
+	cmd.SilenceUsage = true
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "max-items") {
+		t.Fatalf("expected max-items error, got %v", err)
+	}
+}
+
+func TestDriveListInterruptKeepsPartialOutput(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page_token") {
+		case "":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"files":           []map[string]any{{"token": "f1", "name": "One", "type": "docx"}},
+				"has_more":        true,
+				"next_page_token": "p2",
+			}})
+		case "p2":
+			// Simulate Ctrl-C while the second page is in flight.
+			cancel()
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"files":           []map[string]any{{"token": "f2", "name": "Two", "type": "docx"}},
+				"has_more":        true,
+				"next_page_token": "p3",
+			}})
+		default:
+			t.Fatalf("page after interrupt should not be fetched: %s", r.URL.RawQuery)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+	var stderr bytes.Buffer
+	state.ErrWriter = &stderr
+
+	cmd := newDriveCmd(state)
+	cmd.SetArgs([]string{"list", "--all"})
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		t.Fatalf("drive list error: %v", err)
+	}
+	if state.interrupted == nil {
+		t.Fatalf("expected interrupted state")
+	}
+	if !strings.Contains(stderr.String(), "interrupted after 2 pages") {
+		t.Fatalf("unexpected stderr: %q", stderr.String())
+	}
+	if !strings.Contains(buf.String(), `"f2"`) {
+		t.Fatalf("expected partial output, got %q", buf.String())
+	}
+}
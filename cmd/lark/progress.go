@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"lark/internal/output"
+)
+
+const progressBarWidth = 30
+
+// progressBar renders a single-line progress indicator on stderr. It is a
+// no-op unless stderr is a terminal, so scripted runs keep clean output.
+type progressBar struct {
+	mu       sync.Mutex
+	writer   io.Writer
+	label    string
+	total    int64
+	current  int64
+	enabled  bool
+	lastDraw time.Time
+}
+
+func newProgressBar(state *appState, label string, total int64) *progressBar {
+	w := errWriter(state)
+	return &progressBar{
+		writer:  w,
+		label:   label,
+		total:   total,
+		enabled: output.AutoStyle(w),
+	}
+}
+
+func (p *progressBar) Add(n int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current += n
+	if !p.enabled {
+		return
+	}
+	if p.current < p.total && time.Since(p.lastDraw) < 100*time.Millisecond {
+		return
+	}
+	p.draw()
+}
+
+// Finish clears the progress line.
+func (p *progressBar) Finish() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.enabled || p.lastDraw.IsZero() {
+		return
+	}
+	fmt.Fprint(p.writer, "\r\033[K")
+}
+
+func (p *progressBar) draw() {
+	p.lastDraw = time.Now()
+	if p.total <= 0 {
+		fmt.Fprintf(p.writer, "\r\033[K%s %s", p.label, formatBytes(p.current))
+		return
+	}
+	current := p.current
+	if current > p.total {
+		current = p.total
+	}
+	filled := int(current * progressBarWidth / p.total)
+	bar := strings.Repeat("#", filled) + strings.Repeat("-", progressBarWidth-filled)
+	percent := current * 100 / p.total
+	fmt.Fprintf(p.writer, "\r\033[K%s [%s] %3d%% %s/%s", p.label, bar, percent, formatBytes(current), formatBytes(p.total))
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for value := n / unit; value >= unit; value /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -5,8 +5,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,6 +26,10 @@ type appState struct {
 	Profile        string
 	JSON           bool
 	Plain          bool
+	Output         string
+	JQ             string
+	Fields         []string
+	Template       string
 	Color          string
 	Verbose        bool
 	Force          bool
@@ -37,6 +43,14 @@ type appState struct {
 	Platform       string
 	BaseURL        string
 	baseURLPersist string
+	MaxRetries     int
+	maxRetriesSet  bool
+	Record         string
+	Replay         string
+	httpClient     *http.Client
+	// interrupted is set when a paginated listing was cut short by Ctrl-C;
+	// the command prints its partial output and the process exits non-zero.
+	interrupted error
 
 	// Command is the invoked command path (space-separated, excluding the root
 	// binary name). Example: "mail send".
@@ -62,11 +76,26 @@ func newRootCmd() *cobra.Command {
 				return usageErrorWithUsage(cmd, err.Error(), "", cmd.UsageString())
 			}
 			plain := state.Plain
+			filter, err := output.NewFilter(state.Fields, state.JQ, state.Template)
+			if err != nil {
+				return flagUsage(cmd, err.Error())
+			}
+			format, err := output.ParseFormat(state.Output)
+			if err != nil {
+				return flagUsage(cmd, err.Error())
+			}
+			if filter != nil || (format != "" && format != output.FormatTable) {
+				// Filters and machine formats render the payload rather than
+				// the text view, so they imply --json.
+				state.JSON = true
+			}
 			styled := resolveStyledOutput(out, state.JSON, plain, colorMode)
 			state.Printer = output.Printer{
 				Writer: out,
 				JSON:   state.JSON,
 				Styled: styled,
+				Format: format,
+				Filter: filter,
 			}
 			tablePlain = plain
 			if shouldSkipConfigLoad(state.Command) {
@@ -100,8 +129,15 @@ func newRootCmd() *cobra.Command {
 			if err := hydrateAppSecretFromKeyring(state); err != nil {
 				return err
 			}
+			if err := configureCassettes(cmd, state); err != nil {
+				return err
+			}
+			state.maxRetriesSet = cmd.Flags().Changed("max-retries")
+			if state.maxRetriesSet && state.MaxRetries < 0 {
+				return flagUsage(cmd, "max-retries must be >= 0")
+			}
 			handleAutoUpdate(state)
-			sdkClient, err := larksdk.New(cfg)
+			sdkClient, err := larksdk.New(cfg, sdkOptions(state)...)
 			if err == nil {
 				state.SDK = sdkClient
 			} else {
@@ -113,6 +149,9 @@ func newRootCmd() *cobra.Command {
 			return nil
 		},
 	}
+	cmd.PersistentPostRunE = func(cmd *cobra.Command, args []string) error {
+		return state.interrupted
+	}
 	cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
 		if errors.Is(err, pflag.ErrHelp) {
 			return err
@@ -128,6 +167,10 @@ func newRootCmd() *cobra.Command {
 	cmd.PersistentFlags().StringVar(&state.Profile, "profile", "", "config profile (env: LARK_PROFILE)")
 	cmd.PersistentFlags().BoolVar(&state.JSON, "json", false, "output JSON")
 	cmd.PersistentFlags().BoolVar(&state.Plain, "plain", false, "output plain TSV (no styles)")
+	cmd.PersistentFlags().StringVar(&state.Output, "output", "", "output format (json|ndjson|csv|tsv|table|yaml)")
+	cmd.PersistentFlags().StringVar(&state.JQ, "jq", "", "filter JSON output with a jq expression")
+	cmd.PersistentFlags().StringSliceVar(&state.Fields, "fields", nil, "keep only these JSON fields (comma-separated, dotted paths)")
+	cmd.PersistentFlags().StringVar(&state.Template, "template", "", "render JSON output with a Go text/template")
 	cmd.PersistentFlags().StringVar(&state.Color, "color", "auto", "color mode (auto|always|never)")
 	cmd.PersistentFlags().BoolVar(&state.Verbose, "verbose", false, "verbose output")
 	cmd.PersistentFlags().BoolVar(&state.Force, "force", false, "skip confirmation prompts")
@@ -136,7 +179,15 @@ func newRootCmd() *cobra.Command {
 	cmd.PersistentFlags().StringVar(&state.UserAccount, "account", "", "user account label (default: config default or LARK_ACCOUNT)")
 	cmd.PersistentFlags().StringVar(&state.Platform, "platform", "", "platform (feishu|lark)")
 	cmd.PersistentFlags().StringVar(&state.BaseURL, "base-url", "", "base URL override")
+	cmd.PersistentFlags().StringVar(&state.Record, "record", "", "record redacted HTTP cassettes to this directory (env: LARK_RECORD)")
+	cmd.PersistentFlags().StringVar(&state.Replay, "replay", "", "serve HTTP responses from cassettes in this directory instead of the network")
+	cmd.PersistentFlags().IntVar(&state.MaxRetries, "max-retries", larksdk.DefaultMaxRetries, "max retries for rate-limited or failed API requests (env: LARK_MAX_RETRIES; 0 disables)")
 	cmd.MarkFlagsMutuallyExclusive("json", "plain")
+	cmd.MarkFlagsMutuallyExclusive("output", "json")
+	cmd.MarkFlagsMutuallyExclusive("output", "plain")
+	cmd.MarkFlagsMutuallyExclusive("jq", "plain")
+	cmd.MarkFlagsMutuallyExclusive("fields", "plain")
+	cmd.MarkFlagsMutuallyExclusive("template", "plain")
 
 	cmd.AddCommand(newVersionCmd(state))
 	cmd.AddCommand(newUpgradeCmd(state))
@@ -144,6 +195,7 @@ func newRootCmd() *cobra.Command {
 	cmd.AddCommand(newAuthCmd(state))
 	cmd.AddCommand(newWhoamiCmd(state))
 	cmd.AddCommand(newMsgCmd(state))
+	cmd.AddCommand(newCardsCmd(state))
 	cmd.AddCommand(newChatsCmd(state))
 	cmd.AddCommand(newUsersCmd(state))
 	cmd.AddCommand(newDriveCmd(state))
@@ -159,6 +211,7 @@ func newRootCmd() *cobra.Command {
 	cmd.AddCommand(newMailCmd(state))
 	cmd.AddCommand(newBaseCmd(state))
 	cmd.AddCommand(newConfigCmd(state))
+	cmd.AddCommand(newAPICmd(state))
 
 	registerAuthServices(cmd)
 
@@ -257,6 +310,10 @@ func (state *appState) saveConfig() error {
 	if state.Config == nil {
 		return errors.New("config is required")
 	}
+	if state.Replay != "" {
+		// Replayed sessions must not overwrite real cached tokens.
+		return nil
+	}
 	cfg := *state.Config
 	// Runtime overrides (--base-url/--platform) must not mutate persisted config.
 	// Always restore the originally loaded base URL (even if empty).
@@ -269,6 +326,39 @@ func (state *appState) saveConfig() error {
 	return config.Save(state.ConfigPath, &cfg)
 }
 
+// sdkOptions builds the SDK client options shared by every command.
+func sdkOptions(state *appState) []larksdk.Option {
+	var opts []larksdk.Option
+	if state != nil && state.httpClient != nil {
+		opts = append(opts, larksdk.WithHTTPClient(state.httpClient))
+	}
+	maxRetries := resolveMaxRetries(state)
+	if maxRetries <= 0 {
+		return opts
+	}
+	return append(opts, larksdk.WithRetry(larksdk.RetryPolicy{MaxRetries: maxRetries}))
+}
+
+// resolveMaxRetries applies precedence: --max-retries, LARK_MAX_RETRIES,
+// config max_retries, then the built-in default.
+func resolveMaxRetries(state *appState) int {
+	if state == nil {
+		return larksdk.DefaultMaxRetries
+	}
+	if state.maxRetriesSet {
+		return state.MaxRetries
+	}
+	if raw := strings.TrimSpace(os.Getenv("LARK_MAX_RETRIES")); raw != "" {
+		if value, err := strconv.Atoi(raw); err == nil && value >= 0 {
+			return value
+		}
+	}
+	if state.Config != nil && state.Config.MaxRetries != nil && *state.Config.MaxRetries >= 0 {
+		return *state.Config.MaxRetries
+	}
+	return larksdk.DefaultMaxRetries
+}
+
 func requireSDK(state *appState) (*larksdk.Client, error) {
 	if state == nil {
 		return nil, errors.New("sdk client is required")
@@ -288,7 +378,7 @@ func requireSDK(state *appState) (*larksdk.Client, error) {
 		}
 		return nil, fmt.Errorf("init sdk: %w", state.sdkInitErr)
 	}
-	sdk, err := larksdk.New(state.Config)
+	sdk, err := larksdk.New(state.Config, sdkOptions(state)...)
 	if err != nil {
 		if errors.Is(err, larksdk.ErrUnavailable) {
 			return nil, errors.New("missing app credentials: run `lark auth login` or `lark config set --app-id/--app-secret`")
@@ -360,7 +450,7 @@ func ensureTenantToken(ctx context.Context, state *appState) (string, error) {
 	sdk := state.SDK
 	if sdk == nil {
 		var err error
-		sdk, err = larksdk.New(state.Config)
+		sdk, err = larksdk.New(state.Config, sdkOptions(state)...)
 		if err != nil {
 			return "", fmt.Errorf("init sdk: %w", err)
 		}
@@ -404,7 +494,7 @@ func ensureUserToken(ctx context.Context, state *appState) (string, error) {
 	sdk := state.SDK
 	if sdk == nil {
 		var err error
-		sdk, err = larksdk.New(state.Config)
+		sdk, err = larksdk.New(state.Config, sdkOptions(state)...)
 		if err != nil {
 			return "", fmt.Errorf("init sdk: %w", err)
 		}
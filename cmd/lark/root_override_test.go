@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"lark/internal/config"
+	"lark/internal/larksdk"
 )
 
 func TestApplyBaseURLOverridesPrecedence(t *testing.T) {
@@ -152,3 +153,31 @@ func TestRuntimePlatformOverrideDoesNotPersistConfig(t *testing.T) {
 	}
 	_ = before // keep variable for potential future regression debugging
 }
+
+func TestResolveMaxRetriesPrecedence(t *testing.T) {
+	configured := 5
+	state := &appState{Config: &config.Config{MaxRetries: &configured}}
+	t.Setenv("LARK_MAX_RETRIES", "")
+	if got := resolveMaxRetries(state); got != 5 {
+		t.Fatalf("expected config value 5, got %d", got)
+	}
+
+	t.Setenv("LARK_MAX_RETRIES", "2")
+	if got := resolveMaxRetries(state); got != 2 {
+		t.Fatalf("expected env value 2, got %d", got)
+	}
+
+	state.MaxRetries = 0
+	state.maxRetriesSet = true
+	if got := resolveMaxRetries(state); got != 0 {
+		t.Fatalf("expected flag value 0, got %d", got)
+	}
+	if opts := sdkOptions(state); len(opts) != 0 {
+		t.Fatalf("expected no sdk options when retries disabled, got %d", len(opts))
+	}
+
+	t.Setenv("LARK_MAX_RETRIES", "")
+	if got := resolveMaxRetries(&appState{Config: &config.Config{}}); got != larksdk.DefaultMaxRetries {
+		t.Fatalf("expected default %d, got %d", larksdk.DefaultMaxRetries, got)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -11,14 +12,14 @@ import (
 
 func newSheetsListCmd(state *appState) *cobra.Command {
 	var folderID string
-	var limit int
+	var paging listPaging
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List Sheets (spreadsheets) in a Drive folder",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				return flagUsage(cmd, "limit must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			folderID = strings.TrimSpace(folderID)
 			if strings.EqualFold(folderID, "root") {
@@ -32,45 +33,29 @@ func newSheetsListCmd(state *appState) *cobra.Command {
 				return err
 			}
 
-			files := make([]larksdk.DriveFile, 0, limit)
-			pageToken := ""
-			remaining := limit
-			for {
-				pageSize := remaining
-				if pageSize > maxDrivePageSize {
-					pageSize = maxDrivePageSize
-				}
-
+			collected := newListCollector[larksdk.DriveFile](state, paging.itemLimit())
+			pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.DriveFile], error) {
 				// Thin wrapper over drive list: fetch then filter to sheet.
-				result, err := state.SDK.ListDriveFiles(cmd.Context(), token, larksdk.AccessTokenType(tokenTypeValue), larksdk.ListDriveFilesRequest{
+				result, err := state.SDK.ListDriveFiles(ctx, token, larksdk.AccessTokenType(tokenTypeValue), larksdk.ListDriveFilesRequest{
 					FolderToken: folderID,
-					PageSize:    pageSize,
+					PageSize:    collected.pageSize(maxDrivePageSize),
 					PageToken:   pageToken,
 				})
-				if err != nil {
-					return err
-				}
+				matched := make([]larksdk.DriveFile, 0, len(result.Files))
 				for _, file := range result.Files {
 					if file.FileType == "sheet" {
-						files = append(files, file)
-						if len(files) >= limit {
-							break
-						}
+						matched = append(matched, file)
 					}
 				}
-				if len(files) >= limit || !result.HasMore {
-					break
-				}
-				remaining = limit - len(files)
-				pageToken = result.PageToken
-				if pageToken == "" {
-					break
-				}
+				return larksdk.Page[larksdk.DriveFile]{Items: matched, PageToken: result.PageToken, HasMore: result.HasMore}, err
+			})
+			if err := drainPages(cmd.Context(), state, collected, pager, 0); err != nil {
+				return err
 			}
-			if len(files) > limit {
-				files = files[:limit]
+			if collected.streaming {
+				return nil
 			}
-
+			files := collected.items
 			payload := map[string]any{"files": files}
 			lines := make([]string, 0, len(files))
 			for _, file := range files {
@@ -82,6 +67,7 @@ func newSheetsListCmd(state *appState) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&folderID, "folder-id", "", "Drive folder token (default: root)")
-	cmd.Flags().IntVar(&limit, "limit", 50, "max number of files to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 50, "max number of files to return")
+	addPagingFlags(cmd, &paging)
 	return cmd
 }
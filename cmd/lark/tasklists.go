@@ -35,18 +35,18 @@ const (
 )
 
 func newTasklistListCmd(state *appState) *cobra.Command {
-	var limit int
+	var paging listPaging
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List task lists",
 		Args:  cobra.NoArgs,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				return flagUsage(cmd, "limit must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
-			if limit > maxTasklistsPageSize {
-				limit = maxTasklistsPageSize
+			if paging.limit > maxTasklistsPageSize {
+				paging.limit = maxTasklistsPageSize
 			}
 			if _, err := requireSDK(state); err != nil {
 				return err
@@ -59,34 +59,21 @@ func newTasklistListCmd(state *appState) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			tasklists := make([]larksdk.TaskList, 0, limit)
-			pageToken := ""
-			remaining := limit
-			for {
-				pageSize := remaining
-				if pageSize > maxTasklistsPageSize {
-					pageSize = maxTasklistsPageSize
-				}
+			collected := newListCollector[larksdk.TaskList](state, paging.itemLimit())
+			pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.TaskList], error) {
 				result, err := state.SDK.ListTasklists(ctx, token, larksdk.ListTasklistsRequest{
-					PageSize:  pageSize,
+					PageSize:  collected.pageSize(maxTasklistsPageSize),
 					PageToken: pageToken,
 				})
-				if err != nil {
-					return err
-				}
-				tasklists = append(tasklists, result.Items...)
-				if len(tasklists) >= limit || !result.HasMore {
-					break
-				}
-				remaining = limit - len(tasklists)
-				pageToken = result.PageToken
-				if pageToken == "" {
-					break
-				}
+				return larksdk.Page[larksdk.TaskList]{Items: result.Items, PageToken: result.PageToken, HasMore: result.HasMore}, err
+			})
+			if err := drainPages(ctx, state, collected, pager, 0); err != nil {
+				return err
 			}
-			if len(tasklists) > limit {
-				tasklists = tasklists[:limit]
+			if collected.streaming {
+				return nil
 			}
+			tasklists := collected.items
 			payload := map[string]any{"tasklists": tasklists}
 			lines := make([]string, 0, len(tasklists))
 			for _, tasklist := range tasklists {
@@ -101,7 +88,8 @@ func newTasklistListCmd(state *appState) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().IntVar(&limit, "limit", defaultTasklistsLimit, "max number of task lists to return (max 500)")
+	cmd.Flags().IntVar(&paging.limit, "limit", defaultTasklistsLimit, "max number of task lists to return (max 500)")
+	addPagingFlags(cmd, &paging)
 	return cmd
 }
 
@@ -432,7 +432,7 @@ func newTaskDeleteCmd(state *appState) *cobra.Command {
 }
 
 func newTaskListCmd(state *appState) *cobra.Command {
-	var limit int
+	var paging listPaging
 	var pageSize int
 	var completed bool
 	var taskType string
@@ -445,8 +445,8 @@ func newTaskListCmd(state *appState) *cobra.Command {
 			if state.SDK == nil {
 				return errors.New("sdk client is required")
 			}
-			if limit <= 0 {
-				return errors.New("limit must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			if pageSize <= 0 {
 				return errors.New("page-size must be greater than 0")
@@ -462,40 +462,24 @@ func newTaskListCmd(state *appState) *cobra.Command {
 				completedPtr = &v
 			}
 
-			items := make([]larksdk.Task, 0, limit)
-			pageToken := ""
-			remaining := limit
-			for {
-				size := pageSize
-				if size > maxTasksPageSize {
-					size = maxTasksPageSize
-				}
-				if size > remaining {
-					size = remaining
-				}
-				result, err := state.SDK.ListTasks(context.Background(), token, larksdk.ListTasksRequest{
-					PageSize:   size,
-					PageToken:  pageToken,
+			collected := newListCollector[larksdk.Task](state, paging.itemLimit())
+			pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.Task], error) {
+				result, err := state.SDK.ListTasks(ctx, token, larksdk.ListTasksRequest{
+					PageSize:   collected.pageSize(min(pageSize, maxTasksPageSize)),
+					PageToken:  strings.TrimSpace(pageToken),
 					Completed:  completedPtr,
 					Type:       strings.TrimSpace(taskType),
 					UserIDType: strings.TrimSpace(userIDType),
 				})
-				if err != nil {
-					return withUserScopeHintForCommand(state, err)
-				}
-				items = append(items, result.Items...)
-				if len(items) >= limit || !result.HasMore {
-					break
-				}
-				remaining = limit - len(items)
-				pageToken = result.PageToken
-				if strings.TrimSpace(pageToken) == "" {
-					break
-				}
+				return larksdk.Page[larksdk.Task]{Items: result.Items, PageToken: strings.TrimSpace(result.PageToken), HasMore: result.HasMore}, err
+			})
+			if err := drainPages(cmd.Context(), state, collected, pager, 0); err != nil {
+				return withUserScopeHintForCommand(state, err)
 			}
-			if len(items) > limit {
-				items = items[:limit]
+			if collected.streaming {
+				return nil
 			}
+			items := collected.items
 
 			payload := map[string]any{"tasks": items}
 			lines := make([]string, 0, len(items))
@@ -507,8 +491,9 @@ func newTaskListCmd(state *appState) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().IntVar(&limit, "limit", 50, "max number of tasks to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 50, "max number of tasks to return")
 	cmd.Flags().IntVar(&pageSize, "page-size", 50, "page size per request")
+	addPagingFlags(cmd, &paging)
 	cmd.Flags().BoolVar(&completed, "completed", false, "filter by completion status (true/false)")
 	cmd.Flags().StringVar(&taskType, "type", "my_tasks", "task list type (default: my_tasks)")
 	cmd.Flags().StringVar(&userIDType, "user-id-type", "open_id", "user ID type (open_id, union_id, user_id)")
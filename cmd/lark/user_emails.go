@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"lark/internal/larksdk"
+)
+
+// maxBatchGetUserIDEmails is the number of emails accepted per batch_get_id call.
+const maxBatchGetUserIDEmails = 50
+
+// resolveEmailOpenIDs maps emails to open_ids via contact batch_get_id. Emails
+// that do not match a user are returned in missing, in input order.
+func resolveEmailOpenIDs(ctx context.Context, state *appState, token string, emails []string) (map[string]string, []string, error) {
+	resolved := make(map[string]string, len(emails))
+	unique := make([]string, 0, len(emails))
+	seen := map[string]bool{}
+	for _, email := range emails {
+		key := strings.ToLower(strings.TrimSpace(email))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, key)
+	}
+	for start := 0; start < len(unique); start += maxBatchGetUserIDEmails {
+		batch := unique[start:min(start+maxBatchGetUserIDEmails, len(unique))]
+		users, err := state.SDK.BatchGetUserIDs(ctx, token, larksdk.BatchGetUserIDRequest{Emails: batch})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, user := range users {
+			if user.UserID != "" && user.Email != "" {
+				resolved[strings.ToLower(user.Email)] = user.UserID
+			}
+		}
+	}
+	missing := make([]string, 0)
+	out := make(map[string]string, len(emails))
+	for _, email := range emails {
+		trimmed := strings.TrimSpace(email)
+		if openID, ok := resolved[strings.ToLower(trimmed)]; ok {
+			out[trimmed] = openID
+			continue
+		}
+		missing = append(missing, trimmed)
+	}
+	return out, missing, nil
+}
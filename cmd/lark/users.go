@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -30,8 +31,7 @@ Tip: use search to resolve IDs before calling other APIs.`,
 func newUsersSearchCmd(state *appState) *cobra.Command {
 	var query string
 	var email string
-	var limit int
-	var pages int
+	var paging listPaging
 
 	cmd := &cobra.Command{
 		Use:     "search <search_query>",
@@ -70,11 +70,8 @@ func newUsersSearchCmd(state *appState) *cobra.Command {
 			if _, err := requireSDK(state); err != nil {
 				return err
 			}
-			if limit <= 0 {
-				return flagUsage(cmd, "limit must be greater than 0")
-			}
-			if pages <= 0 {
-				return flagUsage(cmd, "pages must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			ctx := cmd.Context()
 			token, err := tokenFor(ctx, state, tokenTypesUser)
@@ -82,55 +79,32 @@ func newUsersSearchCmd(state *appState) *cobra.Command {
 				return err
 			}
 
-			users := make([]larksdk.User, 0, limit)
-			pageToken := ""
-			nextPageToken := ""
-			hasMore := false
-			pageCount := 0
-			remaining := limit
-			for {
-				if pageCount >= pages {
-					break
-				}
-				pageCount++
-				pageSize := remaining
-				if pageSize > maxUserSearchPageSize {
-					pageSize = maxUserSearchPageSize
-				}
-				if pageSize <= 0 {
-					break
-				}
+			users := newListCollector[larksdk.User](state, paging.itemLimit())
+			pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.User], error) {
 				result, err := state.SDK.SearchUsers(ctx, token, larksdk.SearchUsersRequest{
 					Query:     query,
-					PageSize:  pageSize,
+					PageSize:  users.pageSize(maxUserSearchPageSize),
 					PageToken: pageToken,
 				})
 				if err != nil {
-					return withUserScopeHintForCommand(state, err)
-				}
-				nextPageToken = result.PageToken
-				hasMore = result.HasMore
-				users = append(users, result.Users...)
-				if len(users) >= limit || !result.HasMore {
-					break
-				}
-				remaining = limit - len(users)
-				pageToken = result.PageToken
-				if strings.TrimSpace(pageToken) == "" {
-					break
+					return larksdk.Page[larksdk.User]{}, withUserScopeHintForCommand(state, err)
 				}
+				return larksdk.Page[larksdk.User]{Items: result.Users, PageToken: result.PageToken, HasMore: result.HasMore}, nil
+			})
+			if err := drainPages(ctx, state, users, pager, paging.pageLimit()); err != nil {
+				return err
 			}
-			if len(users) > limit {
-				users = users[:limit]
+			if users.streaming {
+				return nil
 			}
 
 			payload := map[string]any{
-				"users":           users,
-				"has_more":        hasMore,
-				"next_page_token": nextPageToken,
+				"users":           users.items,
+				"has_more":        pager.HasMore(),
+				"next_page_token": pager.PageToken(),
 			}
-			lines := make([]string, 0, len(users))
-			for _, user := range users {
+			lines := make([]string, 0, len(users.items))
+			for _, user := range users.items {
 				lines = append(lines, formatUserSearchLine(user))
 			}
 			text := tableText([]string{"user_id", "name", "email", "departments"}, lines, "no users found")
@@ -140,8 +114,9 @@ func newUsersSearchCmd(state *appState) *cobra.Command {
 	annotateAuthServices(cmd, "search-user")
 
 	cmd.Flags().StringVar(&email, "email", "", "search by exact email address")
-	cmd.Flags().IntVar(&limit, "limit", 50, "max number of users to return")
-	cmd.Flags().IntVar(&pages, "pages", 1, "max number of pages to fetch")
+	cmd.Flags().IntVar(&paging.limit, "limit", 50, "max number of users to return")
+	cmd.Flags().IntVar(&paging.pages, "pages", 1, "max number of pages to fetch")
+	addPagingFlags(cmd, &paging)
 
 	return cmd
 }
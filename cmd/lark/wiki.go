@@ -23,6 +23,8 @@ Relationships: space -> nodes (tree) -> Drive objects; members + tasks belong to
 	cmd.AddCommand(newWikiSpaceCmd(state))
 	cmd.AddCommand(newWikiMemberCmd(state))
 	cmd.AddCommand(newWikiTaskCmd(state))
+	cmd.AddCommand(newWikiExportCmd(state))
+	cmd.AddCommand(newWikiImportCmd(state))
 	return cmd
 }
 
@@ -33,6 +35,8 @@ func newWikiNodeCmd(state *appState) *cobra.Command {
 	}
 	cmd.AddCommand(newWikiNodeCreateCmd(state))
 	cmd.AddCommand(newWikiNodeMoveCmd(state))
+	cmd.AddCommand(newWikiNodeCopyCmd(state))
+	cmd.AddCommand(newWikiNodeDeleteCmd(state))
 	cmd.AddCommand(newWikiNodeUpdateTitleCmd(state))
 	cmd.AddCommand(newWikiNodeAttachCmd(state))
 	cmd.AddCommand(newWikiNodeTreeCmd(state))
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+const (
+	wikiExportMetaFileName  = "_meta.json"
+	wikiExportAssetsDirName = "_assets"
+)
+
+var (
+	wikiExportUnsafeName = regexp.MustCompile(`[/\\:*?"<>|\x00-\x1f]+`)
+	wikiExportURLLink    = regexp.MustCompile(`\]\((https?://[^)\s]+)\)`)
+	wikiExportDoubleLink = regexp.MustCompile(`\[\[([^\[\]\n]+)\]\]`)
+)
+
+// wikiExportEntry records where one node was written. Path is relative to the
+// export root and uses forward slashes.
+type wikiExportEntry struct {
+	Path            string `json:"path,omitempty"`
+	NodeToken       string `json:"node_token"`
+	ParentNodeToken string `json:"parent_node_token,omitempty"`
+	ObjToken        string `json:"obj_token,omitempty"`
+	ObjType         string `json:"obj_type,omitempty"`
+	NodeType        string `json:"node_type,omitempty"`
+	OriginNodeToken string `json:"origin_node_token,omitempty"`
+	Title           string `json:"title"`
+	Status          string `json:"status"`
+	Error           string `json:"error,omitempty"`
+}
+
+type wikiExportMeta struct {
+	SpaceID    string            `json:"space_id"`
+	ExportedAt string            `json:"exported_at"`
+	Nodes      []wikiExportEntry `json:"nodes"`
+}
+
+// wikiExportFormats maps exportable non-docx object types to the file
+// extension requested from the Drive export task.
+var wikiExportFormats = map[string]string{
+	"sheet":   "xlsx",
+	"bitable": "xlsx",
+}
+
+func newWikiExportCmd(state *appState) *cobra.Command {
+	var spaceID string
+	var outDir string
+
+	cmd := &cobra.Command{
+		Use:   "export <space-id> --out <dir>",
+		Short: "Export a Wiki space as a Markdown directory tree",
+		Long: `Export every node in a Wiki space into a directory that mirrors the tree.
+
+- docx nodes become <title>.md, with images and attachments in _assets/.
+- sheet and bitable nodes are exported as .xlsx through Drive export tasks.
+- shortcut nodes become a small .md file linking to the original page.
+- a node with children also gets a <title>/ directory holding them.
+- links to pages in the space, and [[Page Title]] references, are rewritten
+  to relative paths.
+
+_meta.json at the root records the node_token, obj_token and title of every
+node. Other object types (mindnote, file, legacy doc) are listed there as
+skipped. Existing files are overwritten, so the same --out can be reused for a
+nightly backup.`,
+		Example: `  lark wiki export <SPACE_ID> --out ./wiki-backup`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			spaceID = strings.TrimSpace(args[0])
+			if spaceID == "" {
+				return argsUsageError(cmd, errors.New("space-id is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outDir = strings.TrimSpace(outDir)
+			if outDir == "" {
+				return usageError(cmd, "--out is required", "")
+			}
+			if info, err := os.Stat(outDir); err == nil && !info.IsDir() {
+				return fmt.Errorf("output path is not a directory: %s", outDir)
+			}
+			if _, err := requireSDK(state); err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			token, tokenType, err := resolveAccessToken(ctx, state, tokenTypesTenantOrUser, nil)
+			if err != nil {
+				return err
+			}
+			builder := &wikiTreeBuilder{
+				sdk:       state.SDK,
+				token:     token,
+				tokenType: tokenType,
+				spaceID:   spaceID,
+				remaining: -1,
+			}
+			tree, err := builder.build(ctx, "", 1)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(outDir, 0o755); err != nil {
+				return err
+			}
+
+			exporter := &wikiExporter{
+				state:     state,
+				token:     token,
+				tokenType: tokenType,
+				outDir:    outDir,
+				byToken:   map[string]string{},
+				byTitle:   map[string]string{},
+			}
+			exporter.plan(tree, "", "")
+			failed := 0
+			for i := range exporter.entries {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				entry := &exporter.entries[i]
+				if err := exporter.export(ctx, entry); err != nil {
+					entry.Status = "error"
+					entry.Error = err.Error()
+					failed++
+					fmt.Fprintf(errWriter(state), "warning: %s not exported: %v\n", entry.NodeToken, err)
+				}
+			}
+
+			meta := wikiExportMeta{
+				SpaceID:    spaceID,
+				ExportedAt: time.Now().UTC().Format(time.RFC3339),
+				Nodes:      exporter.entries,
+			}
+			data, err := json.MarshalIndent(meta, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(outDir, wikiExportMetaFileName), append(data, '\n'), 0o644); err != nil {
+				return err
+			}
+
+			counts := map[string]int{}
+			lines := make([]string, 0, len(exporter.entries))
+			for _, entry := range exporter.entries {
+				counts[entry.Status]++
+				lines = append(lines, fmt.Sprintf("%s\t%s\t%s", entry.Path, entry.ObjType, entry.Status))
+			}
+			payload := map[string]any{
+				"space_id": spaceID,
+				"out":      outDir,
+				"exported": counts["exported"] + counts["shortcut"],
+				"skipped":  counts["skipped"],
+				"failed":   failed,
+				"nodes":    exporter.entries,
+			}
+			if err := state.Printer.Print(payload, tableText([]string{"path", "obj_type", "status"}, lines, "no nodes found")); err != nil {
+				return err
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d nodes failed to export", failed, len(exporter.entries))
+			}
+			return nil
+		},
+	}
+	annotateAuthServices(cmd, "wiki", "docs", "drive-export")
+
+	cmd.Flags().StringVar(&outDir, "out", "", "output directory")
+	_ = cmd.MarkFlagRequired("out")
+	return cmd
+}
+
+type wikiExporter struct {
+	state     *appState
+	token     string
+	tokenType tokenType
+	outDir    string
+	entries   []wikiExportEntry
+	// byToken maps node and obj tokens to the exported file path; byTitle
+	// maps titles to paths for [[Page Title]] references.
+	byToken map[string]string
+	byTitle map[string]string
+}
+
+// plan assigns every node a path before anything is written, so links to
+// pages later in the tree can be rewritten.
+func (e *wikiExporter) plan(nodes []wikiTreeNode, dir, parentToken string) {
+	used := map[string]bool{}
+	for _, item := range nodes {
+		node := item.Node
+		name := wikiExportFileName(node.Title, node.NodeToken)
+		if used[strings.ToLower(name)] {
+			name = name + "-" + node.NodeToken
+		}
+		used[strings.ToLower(name)] = true
+
+		entry := wikiExportEntry{
+			NodeToken:       node.NodeToken,
+			ParentNodeToken: parentToken,
+			ObjToken:        node.ObjToken,
+			ObjType:         node.ObjType,
+			NodeType:        node.NodeType,
+			OriginNodeToken: node.OriginNodeToken,
+			Title:           node.Title,
+		}
+		shortcut := node.NodeType == "shortcut"
+		ext := ""
+		if shortcut || node.ObjType == "docx" {
+			ext = ".md"
+		} else if format, ok := wikiExportFormats[node.ObjType]; ok {
+			ext = "." + format
+		}
+		if ext == "" {
+			entry.Status = "skipped"
+		} else {
+			entry.Path = path.Join(dir, name+ext)
+			e.byToken[node.NodeToken] = entry.Path
+			if node.ObjToken != "" && !shortcut {
+				e.byToken[node.ObjToken] = entry.Path
+			}
+			if title := strings.TrimSpace(node.Title); title != "" {
+				if _, exists := e.byTitle[title]; !exists {
+					e.byTitle[title] = entry.Path
+				}
+			}
+		}
+		e.entries = append(e.entries, entry)
+		if len(item.Children) > 0 {
+			e.plan(item.Children, path.Join(dir, name), node.NodeToken)
+		}
+	}
+}
+
+func (e *wikiExporter) export(ctx context.Context, entry *wikiExportEntry) error {
+	if entry.Status == "skipped" {
+		return nil
+	}
+	target := filepath.Join(e.outDir, filepath.FromSlash(entry.Path))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	accessType := larksdk.AccessTokenType(e.tokenType)
+	switch {
+	case entry.NodeType == "shortcut":
+		link := ""
+		if origin, ok := e.byToken[entry.OriginNodeToken]; ok {
+			link = wikiExportRelativeLink(entry.Path, origin)
+		}
+		if link == "" {
+			link = "wiki node " + entry.OriginNodeToken
+		} else {
+			link = fmt.Sprintf("[%s](%s)", entry.Title, link)
+		}
+		entry.Status = "shortcut"
+		return os.WriteFile(target, []byte(fmt.Sprintf("Shortcut to %s\n", link)), 0o644)
+	case entry.ObjType == "docx":
+		assetsDir := filepath.Join(e.outDir, wikiExportAssetsDirName)
+		export, err := exportDocxMarkdown(ctx, e.state, e.token, accessType, entry.ObjToken, assetsDir, filepath.Dir(target))
+		if err != nil {
+			return err
+		}
+		content := e.rewriteLinks(entry.Path, export.Content)
+		if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+			return err
+		}
+		entry.Status = "exported"
+		return nil
+	default:
+		ticket, err := e.state.SDK.CreateExportTask(ctx, e.token, accessType, larksdk.CreateExportTaskRequest{
+			Token:         entry.ObjToken,
+			Type:          entry.ObjType,
+			FileExtension: wikiExportFormats[entry.ObjType],
+		})
+		if err != nil {
+			return err
+		}
+		result, err := pollExportTask(ctx, e.state.SDK, e.token, accessType, ticket, entry.ObjToken, nil)
+		if err != nil {
+			return err
+		}
+		reader, err := e.state.SDK.DownloadExportedFile(ctx, e.token, accessType, result.FileToken)
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+		file, err := os.Create(target)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(file, reader); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+		entry.Status = "exported"
+		return nil
+	}
+}
+
+// rewriteLinks points links to pages inside the space, and [[Page Title]]
+// references, at the exported files.
+func (e *wikiExporter) rewriteLinks(from, content string) string {
+	content = wikiExportURLLink.ReplaceAllStringFunc(content, func(match string) string {
+		raw := wikiExportURLLink.FindStringSubmatch(match)[1]
+		token, _, err := parseResourceRef(raw)
+		if err != nil {
+			return match
+		}
+		target, ok := e.byToken[token]
+		if !ok {
+			return match
+		}
+		return "](" + wikiExportRelativeLink(from, target) + ")"
+	})
+	return wikiExportDoubleLink.ReplaceAllStringFunc(content, func(match string) string {
+		title := strings.TrimSpace(wikiExportDoubleLink.FindStringSubmatch(match)[1])
+		target, ok := e.byTitle[title]
+		if !ok {
+			return match
+		}
+		return fmt.Sprintf("[%s](%s)", title, wikiExportRelativeLink(from, target))
+	})
+}
+
+func wikiExportFileName(title, nodeToken string) string {
+	name := wikiExportUnsafeName.ReplaceAllString(strings.TrimSpace(title), "-")
+	name = strings.Trim(name, ". ")
+	if name == "" {
+		return nodeToken
+	}
+	return name
+}
+
+// wikiExportRelativeLink returns the link from one exported file to another,
+// escaping spaces so the result stays a valid Markdown link target.
+func wikiExportRelativeLink(from, to string) string {
+	rel, err := filepath.Rel(filepath.Dir(filepath.FromSlash(from)), filepath.FromSlash(to))
+	if err != nil {
+		rel = to
+	}
+	return strings.ReplaceAll(filepath.ToSlash(rel), " ", "%20")
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWikiExportWritesTree(t *testing.T) {
+	var requests []string
+	node := func(token, objToken, objType, title string, extra map[string]any) map[string]any {
+		out := map[string]any{"space_id": "sp1", "node_token": token, "obj_token": objToken, "obj_type": objType, "title": title, "node_type": "origin"}
+		for key, value := range extra {
+			out[key] = value
+		}
+		return out
+	}
+	text := func(elements ...map[string]any) map[string]any {
+		return map[string]any{"elements": elements}
+	}
+	run := func(content string, link string) map[string]any {
+		el := map[string]any{"content": content}
+		if link != "" {
+			el["text_element_style"] = map[string]any{"link": map[string]any{"url": link}}
+		}
+		return map[string]any{"text_run": el}
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		requests = append(requests, r.URL.Path+"?"+r.URL.Query().Get("parent_node_token"))
+		switch r.URL.Path {
+		case "/open-apis/wiki/v2/spaces/sp1/nodes":
+			var items []map[string]any
+			switch r.URL.Query().Get("parent_node_token") {
+			case "":
+				items = []map[string]any{
+					node("n1", "d1", "docx", "Handbook", map[string]any{"has_child": true}),
+					node("n4", "d2", "docx", "On Call", map[string]any{"node_type": "shortcut", "origin_node_token": "n2"}),
+					node("n5", "mm1", "mindnote", "Ideas", nil),
+				}
+			case "n1":
+				items = []map[string]any{
+					node("n2", "d2", "docx", "On Call", nil),
+					node("n3", "sh1", "sheet", "Metrics", nil),
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"items": items, "has_more": false}})
+		case "/open-apis/docx/v1/documents/d1/blocks":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+				{"block_id": "d1", "block_type": 1, "page": text(run("Handbook", "")), "children": []string{"p1"}},
+				{"block_id": "p1", "parent_id": "d1", "block_type": 2, "text": text(
+					run("See ", ""),
+					run("on call", "https%3A%2F%2Fexample.feishu.cn%2Fwiki%2Fn2"),
+					run(" and [[Metrics]].", ""),
+				)},
+			}}})
+		case "/open-apis/docx/v1/documents/d2/blocks":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{
+				{"block_id": "d2", "block_type": 1, "page": text(run("On Call", "")), "children": []string{"p2"}},
+				{"block_id": "p2", "parent_id": "d2", "block_type": 2, "text": text(run("Back to [[Handbook]]", ""))},
+			}}})
+		case "/open-apis/drive/v1/export_tasks":
+			var payload map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode payload: %v", err)
+			}
+			if payload["token"] != "sh1" || payload["type"] != "sheet" || payload["file_extension"] != "xlsx" {
+				t.Fatalf("unexpected export task: %v", payload)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"ticket": "t1"}})
+		case "/open-apis/drive/v1/export_tasks/t1":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"result": map[string]any{
+				"file_token": "xf1", "file_extension": "xlsx", "type": "sheet", "job_status": 0,
+			}}})
+		case "/open-apis/drive/v1/export_tasks/file/xf1/download":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write([]byte("xlsx bytes"))
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+	out := filepath.Join(t.TempDir(), "backup")
+
+	cmd := newWikiCmd(state)
+	cmd.SetArgs([]string{"export", "sp1", "--out", out})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("wiki export error: %v", err)
+	}
+
+	// The tree is listed first; the shortcut's target is exported only once.
+	want := []string{
+		"/open-apis/wiki/v2/spaces/sp1/nodes?",
+		"/open-apis/wiki/v2/spaces/sp1/nodes?n1",
+		"/open-apis/wiki/v2/spaces/sp1/nodes?n2",
+		"/open-apis/wiki/v2/spaces/sp1/nodes?n3",
+		"/open-apis/wiki/v2/spaces/sp1/nodes?n4",
+		"/open-apis/wiki/v2/spaces/sp1/nodes?n5",
+		"/open-apis/docx/v1/documents/d1/blocks?",
+		"/open-apis/docx/v1/documents/d2/blocks?",
+		"/open-apis/drive/v1/export_tasks?",
+		"/open-apis/drive/v1/export_tasks/t1?",
+		"/open-apis/drive/v1/export_tasks/file/xf1/download?",
+	}
+	if strings.Join(requests, " ") != strings.Join(want, " ") {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+	handbook, err := os.ReadFile(filepath.Join(out, "Handbook.md"))
+	if err != nil {
+		t.Fatalf("read Handbook.md: %v", err)
+	}
+	for _, want := range []string{"[on call](Handbook/On%20Call.md)", "[Metrics](Handbook/Metrics.xlsx)"} {
+		if !strings.Contains(string(handbook), want) {
+			t.Fatalf("Handbook.md missing %q:\n%s", want, handbook)
+		}
+	}
+	onCall, err := os.ReadFile(filepath.Join(out, "Handbook", "On Call.md"))
+	if err != nil || !strings.Contains(string(onCall), "[Handbook](../Handbook.md)") {
+		t.Fatalf("unexpected On Call.md: %q %v", onCall, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(out, "Handbook", "Metrics.xlsx")); err != nil || string(data) != "xlsx bytes" {
+		t.Fatalf("unexpected Metrics.xlsx: %q %v", data, err)
+	}
+	shortcut, err := os.ReadFile(filepath.Join(out, "On Call.md"))
+	if err != nil || !strings.Contains(string(shortcut), "(Handbook/On%20Call.md)") {
+		t.Fatalf("unexpected shortcut file: %q %v", shortcut, err)
+	}
+
+	var meta wikiExportMeta
+	data, err := os.ReadFile(filepath.Join(out, wikiExportMetaFileName))
+	if err != nil {
+		t.Fatalf("read meta: %v", err)
+	}
+	if err := json.Unmarshal(data, &meta); err != nil {
+		t.Fatalf("invalid meta: %v", err)
+	}
+	if meta.SpaceID != "sp1" || len(meta.Nodes) != 5 {
+		t.Fatalf("unexpected meta: %+v", meta)
+	}
+	statuses := map[string]string{}
+	for _, entry := range meta.Nodes {
+		statuses[entry.NodeToken] = entry.Status
+	}
+	if statuses["n1"] != "exported" || statuses["n3"] != "exported" || statuses["n4"] != "shortcut" || statuses["n5"] != "skipped" {
+		t.Fatalf("unexpected statuses: %v", statuses)
+	}
+	if !strings.Contains(buf.String(), `"skipped": 1`) {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestWikiExportRequiresOut(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s", r.URL.Path)
+	})
+	state, _ := newTestState(t, handler, true)
+
+	cmd := newWikiCmd(state)
+	cmd.SetArgs([]string{"export", "sp1"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "out") {
+		t.Fatalf("expected --out error, got %v", err)
+	}
+}
@@ -0,0 +1,653 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"lark/internal/larksdk"
+)
+
+const wikiImportStateFileName = ".lark-wiki-import.json"
+
+var wikiImportLink = regexp.MustCompile(`\]\(([^)\s]+)\)`)
+
+// wikiImportPage is one page in the import plan. Path is relative to the
+// import directory; a directory without a matching .md file becomes an empty
+// container page whose Path ends in "/".
+type wikiImportPage struct {
+	Path      string `json:"path"`
+	Title     string `json:"title"`
+	Action    string `json:"action"`
+	MatchedBy string `json:"matched_by,omitempty"`
+	NodeToken string `json:"node_token,omitempty"`
+	ObjToken  string `json:"obj_token,omitempty"`
+	Error     string `json:"error,omitempty"`
+
+	parent    int
+	file      string
+	body      string
+	hash      string
+	frontNode string
+	// links maps the imported files this page links to onto the nodes the
+	// links were rewritten to.
+	links map[string]string
+	// fresh is set when the node was created in this run, so it has no
+	// children to match titles against.
+	fresh bool
+}
+
+type wikiImportFrontMatter struct {
+	NodeToken string `yaml:"node_token"`
+	Title     string `yaml:"title"`
+}
+
+// wikiImportState maps file paths to the nodes they were published to, so a
+// re-run updates the same pages and skips unchanged ones.
+type wikiImportState struct {
+	SpaceID         string                         `json:"space_id"`
+	ParentNodeToken string                         `json:"parent_node_token,omitempty"`
+	Pages           map[string]wikiImportStatePage `json:"pages"`
+}
+
+type wikiImportStatePage struct {
+	NodeToken string            `json:"node_token"`
+	ObjToken  string            `json:"obj_token"`
+	Title     string            `json:"title"`
+	Hash      string            `json:"hash,omitempty"`
+	Links     map[string]string `json:"links,omitempty"`
+}
+
+func newWikiImportCmd(state *appState) *cobra.Command {
+	var dir string
+	var spaceID string
+	var parentNodeToken string
+	var stateFile string
+	var siteURL string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "import <dir> --space-id <SPACE_ID>",
+		Short: "Publish a Markdown directory to a Wiki space",
+		Long: `Publish a directory of Markdown files as docx pages, mirroring its layout.
+
+- a.md becomes page "a"; files in a/ become its children. A directory without
+  a matching .md file becomes an empty page holding its children.
+- Files and directories starting with "." or "_" are ignored, so a wiki
+  export directory can be imported back.
+- An existing page is updated instead of created when the file's front matter
+  has a node_token, when the state file recorded it, or when a docx page with
+  the same title already exists under the same parent.
+- Front matter can also set the title (default: the file name).
+- Links between .md files are rewritten to wiki URLs. The site URL is read
+  from the first published document unless --site-url is set.
+- Local images are uploaded.
+
+The state file (default: .lark-wiki-import.json in <dir>) records the node,
+a content hash and the link targets per file, so re-runs only rewrite pages
+whose content changed or whose links now point somewhere else.
+--dry-run prints the plan without writing anything.`,
+		Example: `  lark wiki import ./handbook --space-id <SPACE_ID> --dry-run
+  lark wiki import ./handbook --space-id <SPACE_ID> --parent-node-token <NODE_TOKEN>`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			dir = strings.TrimSpace(args[0])
+			if dir == "" {
+				return argsUsageError(cmd, errors.New("dir is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spaceID = strings.TrimSpace(spaceID)
+			parentNodeToken = strings.TrimSpace(parentNodeToken)
+			if info, err := os.Stat(dir); err != nil {
+				return err
+			} else if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", dir)
+			}
+			if stateFile == "" {
+				stateFile = filepath.Join(dir, wikiImportStateFileName)
+			}
+			pages, err := scanWikiImportDir(dir)
+			if err != nil {
+				return err
+			}
+			saved, err := loadWikiImportState(stateFile)
+			if err != nil {
+				return err
+			}
+			if saved.SpaceID == "" {
+				saved.SpaceID = spaceID
+				saved.ParentNodeToken = parentNodeToken
+			} else if saved.SpaceID != spaceID || saved.ParentNodeToken != parentNodeToken {
+				return fmt.Errorf("%s was written for space %s (parent %q); use another --state-file", stateFile, saved.SpaceID, saved.ParentNodeToken)
+			}
+
+			if _, err := requireSDK(state); err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			token, tokenType, err := resolveAccessToken(ctx, state, tokenTypesTenantOrUser, nil)
+			if err != nil {
+				return err
+			}
+			importer := &wikiImporter{
+				state:     state,
+				token:     token,
+				tokenType: tokenType,
+				spaceID:   spaceID,
+				parent:    parentNodeToken,
+				siteURL:   strings.TrimRight(strings.TrimSpace(siteURL), "/"),
+				pages:     pages,
+				saved:     saved,
+				dryRun:    dryRun,
+				children:  map[string][]larksdk.WikiNode{},
+			}
+			failed := importer.resolve(ctx)
+			if !dryRun {
+				failed += importer.publish(ctx, stateFile)
+			}
+
+			counts := map[string]int{}
+			lines := make([]string, 0, len(pages))
+			for _, page := range pages {
+				counts[page.Action]++
+				lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s", page.Action, page.Path, page.Title, page.NodeToken))
+			}
+			payload := map[string]any{
+				"space_id":          spaceID,
+				"parent_node_token": parentNodeToken,
+				"dry_run":           dryRun,
+				"created":           counts["create"],
+				"updated":           counts["update"],
+				"unchanged":         counts["unchanged"],
+				"failed":            failed,
+				"pages":             pages,
+			}
+			text := tableText([]string{"action", "path", "title", "node_token"}, lines, "no Markdown files found")
+			if err := state.Printer.Print(payload, text); err != nil {
+				return err
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d pages failed to import", failed, len(pages))
+			}
+			return nil
+		},
+	}
+	annotateAuthServices(cmd, "wiki", "docs")
+
+	cmd.Flags().StringVar(&spaceID, "space-id", "", "Wiki space ID")
+	cmd.Flags().StringVar(&parentNodeToken, "parent-node-token", "", "node to import under (default: space root)")
+	cmd.Flags().StringVar(&stateFile, "state-file", "", "import state file (default: <dir>/"+wikiImportStateFileName+")")
+	cmd.Flags().StringVar(&siteURL, "site-url", "", "site URL for rewritten links (for example https://example.feishu.cn)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "print the plan without creating or updating pages")
+	_ = cmd.MarkFlagRequired("space-id")
+	return cmd
+}
+
+type wikiImporter struct {
+	state     *appState
+	token     string
+	tokenType tokenType
+	spaceID   string
+	parent    string
+	siteURL   string
+	pages     []wikiImportPage
+	saved     *wikiImportState
+	dryRun    bool
+	// children caches the existing nodes under each parent for title matching.
+	children map[string][]larksdk.WikiNode
+}
+
+// resolve finds or creates the node for every page, parents first, and
+// decides whether its content needs writing. Nodes are created empty so that
+// links to pages later in the plan can be rewritten in publish.
+func (im *wikiImporter) resolve(ctx context.Context) int {
+	failed := 0
+	for i := range im.pages {
+		page := &im.pages[i]
+		parentToken, parentFresh := im.parent, false
+		if page.parent >= 0 {
+			parent := im.pages[page.parent]
+			if parent.Action == "error" {
+				page.Action, page.Error = "error", "parent page failed"
+				failed++
+				continue
+			}
+			parentToken, parentFresh = parent.NodeToken, parent.fresh
+		}
+
+		saved, inState := im.saved.Pages[page.Path]
+		switch {
+		case page.frontNode != "":
+			node, err := im.getNode(ctx, page.frontNode)
+			if err != nil {
+				page.Action, page.Error = "error", err.Error()
+				failed++
+				continue
+			}
+			page.NodeToken, page.ObjToken, page.MatchedBy = node.NodeToken, node.ObjToken, "front_matter"
+		case inState:
+			page.NodeToken, page.ObjToken, page.MatchedBy = saved.NodeToken, saved.ObjToken, "state"
+		case !parentFresh:
+			node, err := im.findChild(ctx, parentToken, page.Title)
+			if err != nil {
+				page.Action, page.Error = "error", err.Error()
+				failed++
+				continue
+			}
+			if node != nil {
+				page.NodeToken, page.ObjToken, page.MatchedBy = node.NodeToken, node.ObjToken, "title"
+			}
+		}
+
+		switch {
+		case page.NodeToken == "":
+			page.Action = "create"
+		case inState && saved.NodeToken == page.NodeToken && saved.Hash == page.hash && saved.Title == page.Title:
+			page.Action = "unchanged"
+		case page.file == "" && page.MatchedBy == "title":
+			page.Action = "unchanged"
+		default:
+			page.Action = "update"
+		}
+		if page.Action != "create" || im.dryRun {
+			// A dry run cannot list children of pages it would create.
+			page.fresh = page.Action == "create"
+			continue
+		}
+		node, err := im.createNode(ctx, parentToken, page.Title)
+		if err != nil {
+			page.Action, page.Error = "error", err.Error()
+			failed++
+			continue
+		}
+		page.NodeToken, page.ObjToken, page.fresh = node.NodeToken, node.ObjToken, true
+		if page.file == "" {
+			im.record(page)
+		}
+	}
+	// Links can only be compared once every page has its node: a page whose
+	// content is unchanged still needs writing when a link it carries now
+	// resolves to a different page, for example one created by this run.
+	for i := range im.pages {
+		page := &im.pages[i]
+		if page.Action == "unchanged" && page.file != "" && !maps.Equal(im.linkTargets(page), im.saved.Pages[page.Path].Links) {
+			page.Action = "update"
+		}
+	}
+	return failed
+}
+
+// publish writes the content of every created or changed page and records it
+// in the state file as it goes, so an interrupted run resumes cleanly.
+func (im *wikiImporter) publish(ctx context.Context, stateFile string) int {
+	failed := 0
+	for i := range im.pages {
+		page := &im.pages[i]
+		if page.Action != "create" && page.Action != "update" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			page.Action, page.Error = "error", err.Error()
+			failed++
+			continue
+		}
+		if err := im.publishPage(ctx, page); err != nil {
+			page.Action, page.Error = "error", err.Error()
+			failed++
+			fmt.Fprintf(errWriter(im.state), "warning: %s not imported: %v\n", page.Path, err)
+			continue
+		}
+		im.record(page)
+		if err := saveWikiImportState(stateFile, im.saved); err != nil {
+			page.Action, page.Error = "error", err.Error()
+			failed++
+		}
+	}
+	// Unchanged pages matched by title or front matter are recorded too.
+	for i := range im.pages {
+		if page := &im.pages[i]; page.Action == "unchanged" {
+			if _, ok := im.saved.Pages[page.Path]; !ok {
+				im.record(page)
+			}
+		}
+	}
+	if err := saveWikiImportState(stateFile, im.saved); err != nil {
+		fmt.Fprintf(errWriter(im.state), "warning: state file not saved: %v\n", err)
+	}
+	return failed
+}
+
+func (im *wikiImporter) publishPage(ctx context.Context, page *wikiImportPage) error {
+	if page.MatchedBy != "" {
+		node, err := im.getNode(ctx, page.NodeToken)
+		if err != nil {
+			return err
+		}
+		if node.Title != page.Title {
+			if err := im.updateTitle(ctx, page.NodeToken, page.Title); err != nil {
+				return err
+			}
+		}
+		page.ObjToken = node.ObjToken
+	}
+	if page.file == "" {
+		return nil
+	}
+	content := im.rewriteLinks(ctx, page)
+	if strings.TrimSpace(content) == "" {
+		_, err := clearDocxBlockChildren(ctx, im.state.SDK, im.token, larksdk.AccessTokenType(im.tokenType), page.ObjToken, page.ObjToken)
+		return err
+	}
+	return replaceDocxMarkdown(ctx, im.state, im.token, im.tokenType, page.ObjToken, content, page.file)
+}
+
+func (im *wikiImporter) record(page *wikiImportPage) {
+	im.saved.Pages[page.Path] = wikiImportStatePage{
+		NodeToken: page.NodeToken,
+		ObjToken:  page.ObjToken,
+		Title:     page.Title,
+		Hash:      page.hash,
+		Links:     page.links,
+	}
+}
+
+// rewriteLinks points relative links to other imported .md files at their
+// wiki pages and records the targets it rewrote in page.links.
+func (im *wikiImporter) rewriteLinks(ctx context.Context, page *wikiImportPage) string {
+	targets := im.linkTargets(page)
+	page.links = nil
+	return wikiImportLink.ReplaceAllStringFunc(page.body, func(match string) string {
+		target, fragment, ok := wikiImportLinkTarget(page.Path, wikiImportLink.FindStringSubmatch(match)[1])
+		if !ok || targets[target] == "" {
+			return match
+		}
+		site := im.site(ctx, page.ObjToken)
+		if site == "" {
+			return match
+		}
+		if page.links == nil {
+			page.links = map[string]string{}
+		}
+		page.links[target] = targets[target]
+		link := site + "/wiki/" + targets[target]
+		if fragment != "" {
+			link += "#" + fragment
+		}
+		return "](" + link + ")"
+	})
+}
+
+// linkTargets maps the imported files that page links to onto their node
+// tokens. A file whose page is still to be created maps to "".
+func (im *wikiImporter) linkTargets(page *wikiImportPage) map[string]string {
+	byPath := make(map[string]string, len(im.pages))
+	for _, other := range im.pages {
+		if other.file != "" && (other.NodeToken != "" || other.Action == "create") {
+			byPath[other.Path] = other.NodeToken
+		}
+	}
+	var targets map[string]string
+	for _, match := range wikiImportLink.FindAllStringSubmatch(page.body, -1) {
+		target, _, ok := wikiImportLinkTarget(page.Path, match[1])
+		if !ok {
+			continue
+		}
+		if nodeToken, ok := byPath[target]; ok {
+			if targets == nil {
+				targets = map[string]string{}
+			}
+			targets[target] = nodeToken
+		}
+	}
+	return targets
+}
+
+// wikiImportLinkTarget resolves a Markdown link in the page at pagePath to
+// the import-relative path of the .md file it points at.
+func wikiImportLinkTarget(pagePath, target string) (string, string, bool) {
+	if strings.Contains(target, "://") || strings.HasPrefix(target, "#") {
+		return "", "", false
+	}
+	target, fragment, _ := strings.Cut(target, "#")
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	if !strings.HasSuffix(strings.ToLower(target), ".md") {
+		return "", "", false
+	}
+	return path.Join(path.Dir(pagePath), target), fragment, true
+}
+
+// site returns the tenant's web origin, taken from the URL of a published
+// document the first time it is needed.
+func (im *wikiImporter) site(ctx context.Context, objToken string) string {
+	if im.siteURL == "-" {
+		return ""
+	}
+	if im.siteURL != "" {
+		return im.siteURL
+	}
+	raw := docxDriveURL(ctx, im.state, im.tokenType, im.token, objToken)
+	parsed, err := url.Parse(raw)
+	if raw == "" || err != nil || parsed.Host == "" {
+		fmt.Fprintln(errWriter(im.state), "warning: could not determine the site URL; links are left unchanged (set --site-url)")
+		im.siteURL = "-"
+		return ""
+	}
+	im.siteURL = parsed.Scheme + "://" + parsed.Host
+	return im.siteURL
+}
+
+func (im *wikiImporter) findChild(ctx context.Context, parentToken, title string) (*larksdk.WikiNode, error) {
+	nodes, ok := im.children[parentToken]
+	if !ok {
+		builder := &wikiTreeBuilder{
+			sdk:       im.state.SDK,
+			token:     im.token,
+			tokenType: im.tokenType,
+			spaceID:   im.spaceID,
+			remaining: -1,
+		}
+		var err error
+		if nodes, err = builder.listNodes(ctx, parentToken); err != nil {
+			return nil, err
+		}
+		im.children[parentToken] = nodes
+	}
+	for i := range nodes {
+		if nodes[i].ObjType == "docx" && nodes[i].NodeType != "shortcut" && strings.TrimSpace(nodes[i].Title) == title {
+			return &nodes[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (im *wikiImporter) getNode(ctx context.Context, nodeToken string) (larksdk.WikiNode, error) {
+	return getWikiNode(ctx, im.state.SDK, im.token, im.tokenType, nodeToken)
+}
+
+func (im *wikiImporter) createNode(ctx context.Context, parentToken, title string) (larksdk.WikiNode, error) {
+	req := larksdk.CreateWikiNodeRequest{
+		SpaceID:         im.spaceID,
+		ObjType:         "docx",
+		ParentNodeToken: parentToken,
+		NodeType:        "origin",
+		Title:           title,
+	}
+	switch im.tokenType {
+	case tokenTypeTenant:
+		return im.state.SDK.CreateWikiNodeV2(ctx, im.token, req)
+	case tokenTypeUser:
+		return im.state.SDK.CreateWikiNodeV2WithUserToken(ctx, im.token, req)
+	default:
+		return larksdk.WikiNode{}, fmt.Errorf("unsupported token type %s", im.tokenType)
+	}
+}
+
+func (im *wikiImporter) updateTitle(ctx context.Context, nodeToken, title string) error {
+	req := larksdk.UpdateWikiNodeTitleRequest{SpaceID: im.spaceID, NodeToken: nodeToken, Title: title}
+	switch im.tokenType {
+	case tokenTypeTenant:
+		return im.state.SDK.UpdateWikiNodeTitleV2(ctx, im.token, req)
+	case tokenTypeUser:
+		return im.state.SDK.UpdateWikiNodeTitleV2WithUserToken(ctx, im.token, req)
+	default:
+		return fmt.Errorf("unsupported token type %s", im.tokenType)
+	}
+}
+
+// scanWikiImportDir lists the pages under dir, each parent before its
+// children.
+func scanWikiImportDir(dir string) ([]wikiImportPage, error) {
+	var pages []wikiImportPage
+	var walk func(rel string, parent int) error
+	walk = func(rel string, parent int) error {
+		entries, err := os.ReadDir(filepath.Join(dir, filepath.FromSlash(rel)))
+		if err != nil {
+			return err
+		}
+		files := map[string]bool{}
+		var dirs []string
+		for _, entry := range entries {
+			name := entry.Name()
+			if strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				continue
+			}
+			if entry.IsDir() {
+				dirs = append(dirs, name)
+			} else if strings.EqualFold(filepath.Ext(name), ".md") {
+				files[name] = true
+			}
+		}
+		names := make([]string, 0, len(files))
+		for name := range files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			page, err := readWikiImportPage(dir, path.Join(rel, name))
+			if err != nil {
+				return err
+			}
+			page.parent = parent
+			pages = append(pages, page)
+			stem := strings.TrimSuffix(name, filepath.Ext(name))
+			if containsString(dirs, stem) {
+				if err := walk(path.Join(rel, stem), len(pages)-1); err != nil {
+					return err
+				}
+			}
+		}
+		for _, name := range dirs {
+			if files[name+".md"] || files[name+".MD"] {
+				continue
+			}
+			pages = append(pages, wikiImportPage{Path: path.Join(rel, name) + "/", Title: name, parent: parent})
+			if err := walk(path.Join(rel, name), len(pages)-1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk("", -1); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+func readWikiImportPage(dir, rel string) (wikiImportPage, error) {
+	file := filepath.Join(dir, filepath.FromSlash(rel))
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return wikiImportPage{}, err
+	}
+	front, body, err := splitWikiImportFrontMatter(string(data))
+	if err != nil {
+		return wikiImportPage{}, fmt.Errorf("%s: %w", rel, err)
+	}
+	title := strings.TrimSpace(front.Title)
+	if title == "" {
+		title = strings.TrimSuffix(path.Base(rel), path.Ext(rel))
+	}
+	sum := sha256.Sum256(data)
+	return wikiImportPage{
+		Path:      rel,
+		Title:     title,
+		file:      file,
+		body:      body,
+		hash:      hex.EncodeToString(sum[:]),
+		frontNode: strings.TrimSpace(front.NodeToken),
+	}, nil
+}
+
+func splitWikiImportFrontMatter(content string) (wikiImportFrontMatter, string, error) {
+	var front wikiImportFrontMatter
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return front, content, nil
+	}
+	rest := normalized[len("---\n"):]
+	end := strings.Index(rest, "\n---\n")
+	body := ""
+	if end < 0 {
+		if !strings.HasSuffix(rest, "\n---") {
+			return front, content, nil
+		}
+		end = len(rest) - len("\n---")
+	} else {
+		body = rest[end+len("\n---\n"):]
+	}
+	if err := yaml.Unmarshal([]byte(rest[:end]), &front); err != nil {
+		return front, "", fmt.Errorf("invalid front matter: %w", err)
+	}
+	return front, body, nil
+}
+
+func loadWikiImportState(path string) (*wikiImportState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &wikiImportState{Pages: map[string]wikiImportStatePage{}}, nil
+		}
+		return nil, err
+	}
+	var saved wikiImportState
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if saved.Pages == nil {
+		saved.Pages = map[string]wikiImportStatePage{}
+	}
+	return &saved, nil
+}
+
+func saveWikiImportState(path string, saved *wikiImportState) error {
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(bytes.TrimSpace(data), '\n'), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
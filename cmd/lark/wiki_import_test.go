@@ -0,0 +1,293 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWikiImportCreatesPagesAndIsIdempotent(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"Guide.md":          "---\ntitle: User Guide\n---\nSee [setup](Guide/Setup.md#install).\n",
+		"Guide/Setup.md":    "Back to [guide](../Guide.md), see ![](https://example.com/a.png).\n",
+		"Notes/Todo.md":     "- ship it\n",
+		"_assets/image.png": "png",
+		".hidden.md":        "ignored",
+	} {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	var requests, converted []string
+	created := map[string][2]string{"User Guide": {"n1", "d1"}, "Setup": {"n2", "d2"}, "Todo": {"n3", "d3"}}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/wiki/v2/spaces/sp1/nodes":
+			var items []map[string]any
+			if r.URL.Query().Get("parent_node_token") == "p0" {
+				items = []map[string]any{
+					{"space_id": "sp1", "node_token": "n9", "obj_token": "d9", "obj_type": "docx", "node_type": "origin", "title": "Notes"},
+				}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"items": items, "has_more": false}})
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/wiki/v2/spaces/sp1/nodes":
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			title, _ := body["title"].(string)
+			ids, ok := created[title]
+			if !ok || body["obj_type"] != "docx" || body["obj_token"] != nil {
+				t.Fatalf("unexpected create body: %v", body)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"node": map[string]any{
+				"space_id": "sp1", "node_token": ids[0], "obj_token": ids[1], "obj_type": "docx", "title": title,
+				"parent_node_token": body["parent_node_token"],
+			}}})
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/docx/v1/documents/blocks/convert":
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			content, _ := body["content"].(string)
+			converted = append(converted, content)
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"first_level_block_ids": []string{"tmp1"},
+				"blocks": []map[string]any{
+					{"block_id": "tmp1", "block_type": 2, "text": map[string]any{"elements": []map[string]any{{"text_run": map[string]any{"content": content}}}}},
+				},
+			}})
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/children"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"items": []map[string]any{}, "has_more": false}})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/descendant"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"document_revision_id": 2}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newWikiCmd(state)
+	cmd.SetArgs([]string{"import", dir, "--space-id", "sp1", "--parent-node-token", "p0", "--site-url", "https://example.feishu.cn/"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("wiki import error: %v", err)
+	}
+	var payload struct {
+		Created   int              `json:"created"`
+		Unchanged int              `json:"unchanged"`
+		Pages     []wikiImportPage `json:"pages"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if payload.Created != 3 || payload.Unchanged != 1 || len(payload.Pages) != 4 {
+		t.Fatalf("unexpected payload: %s", buf.String())
+	}
+	if notes := payload.Pages[2]; notes.Path != "Notes/" || notes.NodeToken != "n9" || notes.MatchedBy != "title" {
+		t.Fatalf("unexpected container page: %+v", notes)
+	}
+	if len(converted) != 3 {
+		t.Fatalf("unexpected converts: %q", converted)
+	}
+	if converted[0] != "See [setup](https://example.feishu.cn/wiki/n2#install).\n" {
+		t.Fatalf("link not rewritten: %q", converted[0])
+	}
+	if !strings.Contains(converted[1], "[guide](https://example.feishu.cn/wiki/n1)") || !strings.Contains(converted[1], "![](https://example.com/a.png)") {
+		t.Fatalf("unexpected setup content: %q", converted[1])
+	}
+	// Pages are created top down before any content is written, so links can
+	// point at every new node. The matched Notes page is only listed.
+	want := []string{
+		"GET /open-apis/wiki/v2/spaces/sp1/nodes",
+		"POST /open-apis/wiki/v2/spaces/sp1/nodes",
+		"POST /open-apis/wiki/v2/spaces/sp1/nodes",
+		"GET /open-apis/wiki/v2/spaces/sp1/nodes",
+		"POST /open-apis/wiki/v2/spaces/sp1/nodes",
+	}
+	for _, doc := range []string{"d1", "d2", "d3"} {
+		want = append(want,
+			"POST /open-apis/docx/v1/documents/blocks/convert",
+			"GET /open-apis/docx/v1/documents/"+doc+"/blocks/"+doc+"/children",
+			"POST /open-apis/docx/v1/documents/"+doc+"/blocks/"+doc+"/descendant",
+		)
+	}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected requests: %v", requests)
+	}
+
+	saved, err := loadWikiImportState(filepath.Join(dir, wikiImportStateFileName))
+	if err != nil || len(saved.Pages) != 4 || saved.Pages["Guide/Setup.md"].NodeToken != "n2" {
+		t.Fatalf("unexpected state: %+v %v", saved, err)
+	}
+
+	requests = nil
+	buf.Reset()
+	cmd = newWikiCmd(state)
+	cmd.SetArgs([]string{"import", dir, "--space-id", "sp1", "--parent-node-token", "p0", "--site-url", "https://example.feishu.cn"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("second wiki import error: %v", err)
+	}
+	if len(requests) != 0 || !strings.Contains(buf.String(), `"unchanged": 4`) {
+		t.Fatalf("re-run should be a no-op: %v\n%s", requests, buf.String())
+	}
+}
+
+func TestWikiImportUpdatesUnchangedPageWhenLinkResolves(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Guide.md"), []byte("See [setup](Setup.md).\n"), 0o644); err != nil {
+		t.Fatalf("write Guide.md: %v", err)
+	}
+	guide, err := readWikiImportPage(dir, "Guide.md")
+	if err != nil {
+		t.Fatalf("read Guide.md: %v", err)
+	}
+	// The first run published Guide.md before Setup.md existed.
+	if err := saveWikiImportState(filepath.Join(dir, wikiImportStateFileName), &wikiImportState{
+		SpaceID:         "sp1",
+		ParentNodeToken: "p0",
+		Pages:           map[string]wikiImportStatePage{"Guide.md": {NodeToken: "n1", ObjToken: "d1", Title: "Guide", Hash: guide.hash}},
+	}); err != nil {
+		t.Fatalf("save state: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Setup.md"), []byte("Install it.\n"), 0o644); err != nil {
+		t.Fatalf("write Setup.md: %v", err)
+	}
+	var requests, converted []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/wiki/v2/spaces/sp1/nodes":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"items": []map[string]any{}, "has_more": false}})
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/wiki/v2/spaces/sp1/nodes":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"node": map[string]any{
+				"space_id": "sp1", "node_token": "n2", "obj_token": "d2", "obj_type": "docx", "title": "Setup",
+			}}})
+		case r.Method == http.MethodGet && r.URL.Path == "/open-apis/wiki/v2/spaces/get_node":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"node": map[string]any{
+				"space_id": "sp1", "node_token": "n1", "obj_token": "d1", "obj_type": "docx", "title": "Guide",
+			}}})
+		case r.Method == http.MethodPost && r.URL.Path == "/open-apis/docx/v1/documents/blocks/convert":
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			content, _ := body["content"].(string)
+			converted = append(converted, content)
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{
+				"first_level_block_ids": []string{"tmp1"},
+				"blocks": []map[string]any{
+					{"block_id": "tmp1", "block_type": 2, "text": map[string]any{"elements": []map[string]any{{"text_run": map[string]any{"content": content}}}}},
+				},
+			}})
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/children"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"items": []map[string]any{}, "has_more": false}})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/descendant"):
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"document_revision_id": 2}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, false)
+
+	cmd := newWikiCmd(state)
+	cmd.SetArgs([]string{"import", dir, "--space-id", "sp1", "--parent-node-token", "p0", "--site-url", "https://example.feishu.cn"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("wiki import error: %v", err)
+	}
+	for _, want := range []string{"update\tGuide.md\tGuide\tn1", "create\tSetup.md\tSetup\tn2"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+	if len(converted) != 2 || converted[0] != "See [setup](https://example.feishu.cn/wiki/n2).\n" {
+		t.Fatalf("link not rewritten: %q", converted)
+	}
+	saved, err := loadWikiImportState(filepath.Join(dir, wikiImportStateFileName))
+	if err != nil || saved.Pages["Guide.md"].Links["Setup.md"] != "n2" {
+		t.Fatalf("unexpected state: %+v %v", saved, err)
+	}
+
+	requests = nil
+	buf.Reset()
+	cmd = newWikiCmd(state)
+	cmd.SetArgs([]string{"import", dir, "--space-id", "sp1", "--parent-node-token", "p0", "--site-url", "https://example.feishu.cn"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("second wiki import error: %v", err)
+	}
+	if len(requests) != 0 {
+		t.Fatalf("re-run should be a no-op: %v\n%s", requests, buf.String())
+	}
+}
+
+func TestWikiImportDryRun(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"Guide.md":      "---\ntitle: User Guide\n---\nSee [setup](Guide/Setup.md).\n",
+		"Notes/Todo.md": "- ship it\n",
+	} {
+		target := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(target, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method != http.MethodGet || r.URL.Path != "/open-apis/wiki/v2/spaces/sp1/nodes" {
+			t.Fatalf("dry run should only list nodes: %s %s", r.Method, r.URL.Path)
+		}
+		var items []map[string]any
+		if r.URL.Query().Get("parent_node_token") == "p0" {
+			items = []map[string]any{
+				{"space_id": "sp1", "node_token": "n9", "obj_token": "d9", "obj_type": "docx", "node_type": "origin", "title": "Notes"},
+			}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"items": items, "has_more": false}})
+	})
+	state, buf := newTestState(t, handler, false)
+
+	cmd := newWikiCmd(state)
+	cmd.SetArgs([]string{"import", dir, "--space-id", "sp1", "--parent-node-token", "p0", "--dry-run"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("wiki import error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, wikiImportStateFileName)); !os.IsNotExist(err) {
+		t.Fatalf("dry run should not write the state file: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"create\tGuide.md\tUser Guide", "unchanged\tNotes/\tNotes\tn9", "create\tNotes/Todo.md\tTodo"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWikiImportRejectsStateForAnotherSpace(t *testing.T) {
+	dir := t.TempDir()
+	if err := saveWikiImportState(filepath.Join(dir, wikiImportStateFileName), &wikiImportState{SpaceID: "sp2"}); err != nil {
+		t.Fatalf("save state: %v", err)
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s", r.URL.Path)
+	})
+	state, _ := newTestState(t, handler, true)
+
+	cmd := newWikiCmd(state)
+	cmd.SetArgs([]string{"import", dir, "--space-id", "sp1"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "space sp2") {
+		t.Fatalf("expected state mismatch error, got %v", err)
+	}
+}
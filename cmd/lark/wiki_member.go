@@ -24,7 +24,7 @@ func newWikiMemberCmd(state *appState) *cobra.Command {
 
 func newWikiMemberListCmd(state *appState) *cobra.Command {
 	var spaceID string
-	var limit int
+	var paging listPaging
 	var pageSize int
 
 	cmd := &cobra.Command{
@@ -32,21 +32,19 @@ func newWikiMemberListCmd(state *appState) *cobra.Command {
 		Short: "List Wiki space members (v2)",
 		Args:  cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				limit = 50
+			if paging.limit <= 0 {
+				paging.limit = 50
+			}
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			spaceID = strings.TrimSpace(spaceID)
 			return runWithToken(cmd, state, nil, nil, func(ctx context.Context, sdk *larksdk.Client, token string, tokenType tokenType) (any, string, error) {
-				items := make([]larksdk.WikiSpaceMember, 0, limit)
-				pageToken := ""
-				remaining := limit
-				for {
+				collected := newListCollector[larksdk.WikiSpaceMember](state, paging.itemLimit())
+				pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.WikiSpaceMember], error) {
 					ps := pageSize
 					if ps <= 0 {
-						ps = remaining
-						if ps > 200 {
-							ps = 200
-						}
+						ps = collected.pageSize(200)
 					}
 					req := larksdk.ListWikiSpaceMembersRequest{
 						SpaceID:   spaceID,
@@ -61,24 +59,17 @@ func newWikiMemberListCmd(state *appState) *cobra.Command {
 					case tokenTypeUser:
 						result, err = sdk.ListWikiSpaceMembersV2WithUserToken(ctx, token, req)
 					default:
-						return nil, "", fmt.Errorf("unsupported token type %s", tokenType)
-					}
-					if err != nil {
-						return nil, "", err
-					}
-					items = append(items, result.Members...)
-					if len(items) >= limit || !result.HasMore {
-						break
-					}
-					remaining = limit - len(items)
-					pageToken = result.PageToken
-					if pageToken == "" {
-						break
+						err = fmt.Errorf("unsupported token type %s", tokenType)
 					}
+					return larksdk.Page[larksdk.WikiSpaceMember]{Items: result.Members, PageToken: result.PageToken, HasMore: result.HasMore}, err
+				})
+				if err := drainPages(ctx, state, collected, pager, 0); err != nil {
+					return nil, "", err
 				}
-				if len(items) > limit {
-					items = items[:limit]
+				if collected.streaming {
+					return nil, "", nil
 				}
+				items := collected.items
 
 				payload := map[string]any{"members": items}
 				lines := make([]string, 0, len(items))
@@ -92,8 +83,9 @@ func newWikiMemberListCmd(state *appState) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&spaceID, "space-id", "", "Wiki space ID")
-	cmd.Flags().IntVar(&limit, "limit", 50, "max number of members to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 50, "max number of members to return")
 	cmd.Flags().IntVar(&pageSize, "page-size", 0, "page size (default: auto)")
+	addPagingFlags(cmd, &paging)
 	_ = cmd.MarkFlagRequired("space-id")
 	return cmd
 }
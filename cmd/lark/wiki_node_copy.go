@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+// wikiNodeCopy pairs a source node with its copy.
+type wikiNodeCopy struct {
+	SourceNodeToken string `json:"source_node_token"`
+	NodeToken       string `json:"node_token,omitempty"`
+	ObjToken        string `json:"obj_token,omitempty"`
+	ObjType         string `json:"obj_type,omitempty"`
+	Title           string `json:"title"`
+	Error           string `json:"error,omitempty"`
+}
+
+func newWikiNodeCopyCmd(state *appState) *cobra.Command {
+	var nodeToken string
+	var targetParentNodeToken string
+	var targetSpaceID string
+	var title string
+	var recursive bool
+
+	cmd := &cobra.Command{
+		Use:   "copy <node-token>",
+		Short: "Copy a Wiki node, optionally with its subtree (v2)",
+		Long: `Copy a Wiki node under a target parent or to the root of a target space.
+
+The copy API copies a single node. With --recursive the source subtree is
+listed first and every descendant is copied under its parent's copy, so a
+template page tree can be stamped into a new project space. --title only
+renames the top-level copy.`,
+		Example: `  lark wiki node copy <NODE_TOKEN> --target-space-id <SPACE_ID>
+  lark wiki node copy <NODE_TOKEN> --target-parent <NODE_TOKEN> --title "Project X" --recursive`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			nodeToken = strings.TrimSpace(args[0])
+			if nodeToken == "" {
+				return argsUsageError(cmd, errors.New("node-token is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targetParentNodeToken = strings.TrimSpace(targetParentNodeToken)
+			targetSpaceID = strings.TrimSpace(targetSpaceID)
+			if targetParentNodeToken == "" && targetSpaceID == "" {
+				return usageError(cmd, "--target-parent or --target-space-id is required", "")
+			}
+			if _, err := requireSDK(state); err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+			token, tokenType, err := resolveAccessToken(ctx, state, tokenTypesTenantOrUser, nil)
+			if err != nil {
+				return err
+			}
+			source, err := getWikiNode(ctx, state.SDK, token, tokenType, nodeToken)
+			if err != nil {
+				return err
+			}
+			var children []wikiTreeNode
+			if recursive && source.HasChild {
+				// List the whole subtree before copying so a copy placed inside
+				// the source is not copied again.
+				builder := &wikiTreeBuilder{
+					sdk:       state.SDK,
+					token:     token,
+					tokenType: tokenType,
+					spaceID:   source.SpaceID,
+					remaining: -1,
+				}
+				if children, err = builder.build(ctx, source.NodeToken, 1); err != nil {
+					return err
+				}
+			}
+
+			copier := &wikiNodeCopier{sdk: state.SDK, token: token, tokenType: tokenType}
+			root, err := copier.copy(ctx, source, larksdk.CopyWikiNodeRequest{
+				SpaceID:               source.SpaceID,
+				NodeToken:             source.NodeToken,
+				TargetParentNodeToken: targetParentNodeToken,
+				TargetSpaceID:         targetSpaceID,
+				Title:                 title,
+			})
+			if err != nil {
+				return err
+			}
+			if root.SpaceID == "" {
+				root.SpaceID = targetSpaceID
+			}
+			copier.copyChildren(ctx, children, root)
+
+			lines := make([]string, 0, len(copier.copies))
+			for _, item := range copier.copies {
+				lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s", item.SourceNodeToken, item.NodeToken, item.ObjType, item.Title))
+			}
+			payload := map[string]any{
+				"node":   root,
+				"copied": len(copier.copies) - copier.failed,
+				"failed": copier.failed,
+				"nodes":  copier.copies,
+			}
+			if err := state.Printer.Print(payload, tableText([]string{"source_node_token", "node_token", "obj_type", "title"}, lines, "no nodes copied")); err != nil {
+				return err
+			}
+			if copier.failed > 0 {
+				return fmt.Errorf("%d of %d nodes failed to copy", copier.failed, len(copier.copies))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&targetParentNodeToken, "target-parent", "", "target parent node token")
+	cmd.Flags().StringVar(&targetSpaceID, "target-space-id", "", "target space ID (default: the target parent's space)")
+	cmd.Flags().StringVar(&title, "title", "", "title of the top-level copy (default: source title)")
+	cmd.Flags().BoolVar(&recursive, "recursive", false, "copy the node's descendants too")
+	return cmd
+}
+
+type wikiNodeCopier struct {
+	sdk       *larksdk.Client
+	token     string
+	tokenType tokenType
+	copies    []wikiNodeCopy
+	failed    int
+}
+
+func (c *wikiNodeCopier) copy(ctx context.Context, source larksdk.WikiNode, req larksdk.CopyWikiNodeRequest) (larksdk.WikiNode, error) {
+	var node larksdk.WikiNode
+	var err error
+	switch c.tokenType {
+	case tokenTypeTenant:
+		node, err = c.sdk.CopyWikiNodeV2(ctx, c.token, req)
+	case tokenTypeUser:
+		node, err = c.sdk.CopyWikiNodeV2WithUserToken(ctx, c.token, req)
+	default:
+		err = fmt.Errorf("unsupported token type %s", c.tokenType)
+	}
+	item := wikiNodeCopy{SourceNodeToken: source.NodeToken, Title: source.Title, ObjType: source.ObjType}
+	if err != nil {
+		item.Error = err.Error()
+		c.failed++
+	} else {
+		item.NodeToken, item.ObjToken, item.Title = node.NodeToken, node.ObjToken, node.Title
+	}
+	c.copies = append(c.copies, item)
+	return node, err
+}
+
+// copyChildren copies nodes under parent, depth first. A failed node is
+// recorded and its subtree skipped.
+func (c *wikiNodeCopier) copyChildren(ctx context.Context, nodes []wikiTreeNode, parent larksdk.WikiNode) {
+	for _, item := range nodes {
+		if ctx.Err() != nil {
+			return
+		}
+		node, err := c.copy(ctx, item.Node, larksdk.CopyWikiNodeRequest{
+			SpaceID:               item.Node.SpaceID,
+			NodeToken:             item.Node.NodeToken,
+			TargetParentNodeToken: parent.NodeToken,
+			TargetSpaceID:         parent.SpaceID,
+		})
+		if err != nil {
+			continue
+		}
+		if node.SpaceID == "" {
+			node.SpaceID = parent.SpaceID
+		}
+		c.copyChildren(ctx, item.Children, node)
+	}
+}
+
+func getWikiNode(ctx context.Context, sdk *larksdk.Client, token string, tokenType tokenType, nodeToken string) (larksdk.WikiNode, error) {
+	req := larksdk.GetWikiNodeRequest{NodeToken: nodeToken, ObjType: "wiki"}
+	switch tokenType {
+	case tokenTypeTenant:
+		return sdk.GetWikiNodeV2(ctx, token, req)
+	case tokenTypeUser:
+		return sdk.GetWikiNodeV2WithUserToken(ctx, token, req)
+	default:
+		return larksdk.WikiNode{}, fmt.Errorf("unsupported token type %s", tokenType)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWikiNodeCopyRecursive(t *testing.T) {
+	var copies []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/open-apis/wiki/v2/spaces/get_node":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"node": map[string]any{
+				"space_id": "sp1", "node_token": "t1", "obj_token": "d1", "obj_type": "docx", "title": "Template", "has_child": true,
+			}}})
+		case r.URL.Path == "/open-apis/wiki/v2/spaces/sp1/nodes":
+			var items []map[string]any
+			switch r.URL.Query().Get("parent_node_token") {
+			case "t1":
+				items = []map[string]any{
+					{"space_id": "sp1", "node_token": "t2", "obj_token": "d2", "obj_type": "docx", "title": "Plan", "has_child": true},
+					{"space_id": "sp1", "node_token": "t3", "obj_token": "s3", "obj_type": "sheet", "title": "Budget"},
+				}
+			case "t2":
+				items = []map[string]any{{"space_id": "sp1", "node_token": "t4", "obj_token": "d4", "obj_type": "docx", "title": "Risks"}}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"items": items, "has_more": false}})
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/copy"):
+			source := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/open-apis/wiki/v2/spaces/sp1/nodes/"), "/copy")
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+			copies = append(copies, source+"->"+body["target_space_id"].(string)+"/"+body["target_parent_token"].(string))
+			title, _ := body["title"].(string)
+			if source == "t1" && title != "Project X" || source != "t1" && body["title"] != nil {
+				t.Fatalf("unexpected title for %s: %v", source, body)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"node": map[string]any{
+				"space_id": "sp2", "node_token": "c" + source, "obj_token": "o" + source, "obj_type": "docx", "title": title,
+			}}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+
+	cmd := newWikiCmd(state)
+	cmd.SetArgs([]string{"node", "copy", "t1", "--target-space-id", "sp2", "--target-parent", "p2", "--title", "Project X", "--recursive"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("wiki node copy error: %v", err)
+	}
+	want := []string{"t1->sp2/p2", "t2->sp2/ct1", "t4->sp2/ct2", "t3->sp2/ct1"}
+	if strings.Join(copies, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected copies: %v", copies)
+	}
+	if !strings.Contains(buf.String(), `"copied": 4`) || !strings.Contains(buf.String(), `"node_token": "ct1"`) {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestWikiNodeCopyRequiresTarget(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request: %s", r.URL.Path)
+	})
+	state, _ := newTestState(t, handler, true)
+
+	cmd := newWikiCmd(state)
+	cmd.SetArgs([]string{"node", "copy", "t1"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--target-parent") {
+		t.Fatalf("expected target error, got %v", err)
+	}
+}
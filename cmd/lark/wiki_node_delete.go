@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"lark/internal/larksdk"
+)
+
+func newWikiNodeDeleteCmd(state *appState) *cobra.Command {
+	var nodeToken string
+	var recursive bool
+
+	cmd := &cobra.Command{
+		Use:   "delete <node-token>",
+		Short: "Delete a Wiki node and its document",
+		Long: `Delete a Wiki node by deleting its underlying object through Drive.
+
+Nodes with children are refused unless --recursive is set, which deletes the
+descendants first. Shortcut nodes are refused because deleting their object
+would delete the original page.`,
+		Example: `  lark wiki node delete <NODE_TOKEN>
+  lark wiki node delete <NODE_TOKEN> --recursive --force`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return argsUsageError(cmd, err)
+			}
+			nodeToken = strings.TrimSpace(args[0])
+			if nodeToken == "" {
+				return argsUsageError(cmd, errors.New("node-token is required"))
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWithToken(cmd, state, nil, nil, func(ctx context.Context, sdk *larksdk.Client, token string, tokenType tokenType) (any, string, error) {
+				node, err := getWikiNode(ctx, sdk, token, tokenType, nodeToken)
+				if err != nil {
+					return nil, "", err
+				}
+				targets := []larksdk.WikiNode{node}
+				if node.HasChild {
+					if !recursive {
+						return nil, "", usageError(cmd, fmt.Sprintf("node %s has child nodes", nodeToken), "use --recursive to delete them too")
+					}
+					builder := &wikiTreeBuilder{sdk: sdk, token: token, tokenType: tokenType, spaceID: node.SpaceID, remaining: -1}
+					tree, err := builder.build(ctx, node.NodeToken, 1)
+					if err != nil {
+						return nil, "", err
+					}
+					// Children are deleted before their parents.
+					targets = append(wikiNodesPostOrder(tree), node)
+				}
+				for _, target := range targets {
+					if target.NodeType == "shortcut" {
+						return nil, "", fmt.Errorf("node %s is a shortcut to %s; delete the origin node instead", target.NodeToken, target.OriginNodeToken)
+					}
+				}
+
+				action := fmt.Sprintf("delete wiki node %s (%s %s)", node.NodeToken, node.ObjType, node.Title)
+				if len(targets) > 1 {
+					action += fmt.Sprintf(" and %d descendants", len(targets)-1)
+				}
+				if err := confirmDestructive(cmd, state, action); err != nil {
+					return nil, "", err
+				}
+
+				deleted := make([]larksdk.WikiNode, 0, len(targets))
+				for _, target := range targets {
+					switch tokenType {
+					case tokenTypeTenant:
+						_, err = sdk.DeleteDriveFile(ctx, token, target.ObjToken, target.ObjType)
+					case tokenTypeUser:
+						_, err = sdk.DeleteDriveFileWithUserToken(ctx, token, target.ObjToken, target.ObjType)
+					default:
+						err = fmt.Errorf("unsupported token type %s", tokenType)
+					}
+					if err != nil {
+						return nil, "", fmt.Errorf("delete node %s: %w (%d of %d deleted)", target.NodeToken, err, len(deleted), len(targets))
+					}
+					deleted = append(deleted, target)
+				}
+
+				lines := make([]string, 0, len(deleted))
+				for _, item := range deleted {
+					lines = append(lines, fmt.Sprintf("%s\t%s\t%s\t%s", item.NodeToken, item.ObjType, item.ObjToken, item.Title))
+				}
+				payload := map[string]any{"deleted": len(deleted), "nodes": deleted}
+				return payload, tableText([]string{"node_token", "obj_type", "obj_token", "title"}, lines, ""), nil
+			})
+		},
+	}
+	annotateAuthServices(cmd, "wiki", "drive")
+
+	cmd.Flags().BoolVar(&recursive, "recursive", false, "delete descendant nodes too")
+	return cmd
+}
+
+func wikiNodesPostOrder(nodes []wikiTreeNode) []larksdk.WikiNode {
+	var out []larksdk.WikiNode
+	for _, item := range nodes {
+		out = append(out, wikiNodesPostOrder(item.Children)...)
+		out = append(out, item.Node)
+	}
+	return out
+}
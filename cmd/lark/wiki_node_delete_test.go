@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWikiNodeDeleteRecursive(t *testing.T) {
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query()
+		switch {
+		case r.URL.Path == "/open-apis/wiki/v2/spaces/get_node":
+			requests = append(requests, "get:"+query.Get("token"))
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"node": map[string]any{
+				"space_id": "sp1", "node_token": "n1", "obj_token": "d1", "obj_type": "docx", "title": "Project", "has_child": true,
+			}}})
+		case r.URL.Path == "/open-apis/wiki/v2/spaces/sp1/nodes":
+			requests = append(requests, "list:"+query.Get("parent_node_token"))
+			var items []map[string]any
+			switch query.Get("parent_node_token") {
+			case "n1":
+				items = []map[string]any{{"space_id": "sp1", "node_token": "n2", "obj_token": "d2", "obj_type": "docx", "title": "Plan", "has_child": true}}
+			case "n2":
+				items = []map[string]any{{"space_id": "sp1", "node_token": "n3", "obj_token": "s3", "obj_type": "sheet", "title": "Budget"}}
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"items": items, "has_more": false}})
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/open-apis/drive/v1/files/"):
+			requests = append(requests, "delete:"+strings.TrimPrefix(r.URL.Path, "/open-apis/drive/v1/files/")+":"+query.Get("type"))
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{}})
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, buf := newTestState(t, handler, true)
+	state.Force = true
+
+	cmd := newWikiCmd(state)
+	cmd.SetArgs([]string{"node", "delete", "n1", "--recursive"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("wiki node delete error: %v", err)
+	}
+	// The subtree is listed before anything is deleted, then children go first.
+	want := "get:n1 list:n1 list:n2 list:n3 delete:s3:sheet delete:d2:docx delete:d1:docx"
+	if got := strings.Join(requests, " "); got != want {
+		t.Fatalf("unexpected requests: %s", got)
+	}
+	if !strings.Contains(buf.String(), `"deleted": 3`) {
+		t.Fatalf("unexpected output: %s", buf.String())
+	}
+}
+
+func TestWikiNodeDeleteRefusesChildrenAndShortcuts(t *testing.T) {
+	nodes := map[string]map[string]any{
+		"n1": {"space_id": "sp1", "node_token": "n1", "obj_token": "d1", "obj_type": "docx", "title": "Project", "has_child": true},
+		"n4": {"space_id": "sp1", "node_token": "n4", "obj_token": "d4", "obj_type": "docx", "title": "Link", "node_type": "shortcut", "origin_node_token": "n2"},
+	}
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/open-apis/wiki/v2/spaces/get_node" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"node": nodes[r.URL.Query().Get("token")]}})
+	})
+	state, _ := newTestState(t, handler, true)
+	state.Force = true
+
+	cmd := newWikiCmd(state)
+	cmd.SetArgs([]string{"node", "delete", "n1"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "has child nodes") {
+		t.Fatalf("expected child error, got %v", err)
+	}
+	cmd = newWikiCmd(state)
+	cmd.SetArgs([]string{"node", "delete", "n4"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "shortcut") {
+		t.Fatalf("expected shortcut error, got %v", err)
+	}
+}
+
+func TestWikiNodeDeleteRequiresConfirmation(t *testing.T) {
+	var requests []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/open-apis/wiki/v2/spaces/get_node":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"node": map[string]any{
+				"space_id": "sp1", "node_token": "n1", "obj_token": "d1", "obj_type": "docx", "title": "Project", "has_child": true,
+			}}})
+		case "/open-apis/wiki/v2/spaces/sp1/nodes":
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 0, "data": map[string]any{"has_more": false, "items": []map[string]any{}}})
+		default:
+			t.Fatalf("nothing should be deleted without confirmation: %s %s", r.Method, r.URL.Path)
+		}
+	})
+	state, _ := newTestState(t, handler, true)
+	state.NoInput = true
+
+	cmd := newWikiCmd(state)
+	cmd.SetArgs([]string{"node", "delete", "n1", "--recursive"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "confirmation required") {
+		t.Fatalf("expected confirmation error, got %v", err)
+	}
+	if got := strings.Join(requests, ","); got != "GET /open-apis/wiki/v2/spaces/get_node,GET /open-apis/wiki/v2/spaces/sp1/nodes" {
+		t.Fatalf("unexpected requests: %s", got)
+	}
+}
@@ -9,6 +9,8 @@ func TestWikiNodeExtraCommandsRegistered(t *testing.T) {
 	commands := [][]string{
 		{"wiki", "node", "create", "--help"},
 		{"wiki", "node", "move", "--help"},
+		{"wiki", "node", "copy", "--help"},
+		{"wiki", "node", "delete", "--help"},
 		{"wiki", "node", "update-title", "--help"},
 		{"wiki", "node", "attach", "--help"},
 		{"wiki", "node", "tree", "--help"},
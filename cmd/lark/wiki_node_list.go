@@ -14,7 +14,7 @@ import (
 func newWikiNodeListCmd(state *appState) *cobra.Command {
 	var spaceID string
 	var parentNodeToken string
-	var limit int
+	var paging listPaging
 	var pageSize int
 
 	cmd := &cobra.Command{
@@ -24,22 +24,20 @@ func newWikiNodeListCmd(state *appState) *cobra.Command {
 			if strings.TrimSpace(spaceID) == "" {
 				return errors.New("space-id is required")
 			}
-			if limit <= 0 {
-				limit = 50
+			if paging.limit <= 0 {
+				paging.limit = 50
+			}
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			spaceID = strings.TrimSpace(spaceID)
 			parentNodeToken = strings.TrimSpace(parentNodeToken)
 			return runWithToken(cmd, state, nil, nil, func(ctx context.Context, sdk *larksdk.Client, token string, tokenType tokenType) (any, string, error) {
-				items := make([]larksdk.WikiNode, 0, limit)
-				pageToken := ""
-				remaining := limit
-				for {
+				collected := newListCollector[larksdk.WikiNode](state, paging.itemLimit())
+				pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.WikiNode], error) {
 					ps := pageSize
 					if ps <= 0 {
-						ps = remaining
-						if ps > 200 {
-							ps = 200
-						}
+						ps = collected.pageSize(200)
 					}
 					req := larksdk.ListWikiNodesRequest{
 						SpaceID:         spaceID,
@@ -55,24 +53,17 @@ func newWikiNodeListCmd(state *appState) *cobra.Command {
 					case tokenTypeUser:
 						result, err = sdk.ListWikiNodesV2WithUserToken(ctx, token, req)
 					default:
-						return nil, "", fmt.Errorf("unsupported token type %s", tokenType)
-					}
-					if err != nil {
-						return nil, "", err
-					}
-					items = append(items, result.Items...)
-					if len(items) >= limit || !result.HasMore {
-						break
-					}
-					remaining = limit - len(items)
-					pageToken = result.PageToken
-					if pageToken == "" {
-						break
+						err = fmt.Errorf("unsupported token type %s", tokenType)
 					}
+					return larksdk.Page[larksdk.WikiNode]{Items: result.Items, PageToken: result.PageToken, HasMore: result.HasMore}, err
+				})
+				if err := drainPages(ctx, state, collected, pager, 0); err != nil {
+					return nil, "", err
 				}
-				if len(items) > limit {
-					items = items[:limit]
+				if collected.streaming {
+					return nil, "", nil
 				}
+				items := collected.items
 
 				payload := map[string]any{"nodes": items}
 				lines := make([]string, 0, len(items))
@@ -87,8 +78,9 @@ func newWikiNodeListCmd(state *appState) *cobra.Command {
 
 	cmd.Flags().StringVar(&spaceID, "space-id", "", "Wiki space ID")
 	cmd.Flags().StringVar(&parentNodeToken, "parent-node-token", "", "parent node token (optional)")
-	cmd.Flags().IntVar(&limit, "limit", 50, "max number of nodes to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 50, "max number of nodes to return")
 	cmd.Flags().IntVar(&pageSize, "page-size", 0, "page size (default: auto)")
+	addPagingFlags(cmd, &paging)
 	_ = cmd.MarkFlagRequired("space-id")
 	return cmd
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -14,8 +15,7 @@ import (
 func newWikiNodeSearchCmd(state *appState) *cobra.Command {
 	var query string
 	var spaceID string
-	var limit int
-	var pages int
+	var paging listPaging
 	var userAccessToken string
 
 	cmd := &cobra.Command{
@@ -32,11 +32,8 @@ func newWikiNodeSearchCmd(state *appState) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				return errors.New("limit must be greater than 0")
-			}
-			if pages <= 0 {
-				return errors.New("pages must be greater than 0")
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			if _, err := requireSDK(state); err != nil {
 				return err
@@ -63,41 +60,23 @@ func newWikiNodeSearchCmd(state *appState) *cobra.Command {
 				}
 			}
 
-			nodes := make([]larksdk.WikiNodeSearchV1Item, 0, limit)
-			pageToken := ""
-			pageCount := 0
-			remaining := limit
-			for {
-				if pageCount >= pages {
-					break
-				}
-				pageCount++
-				pageSize := remaining
-				if pageSize > 200 {
-					pageSize = 200
-				}
-				result, err := state.SDK.SearchWikiNodesV1(cmd.Context(), token, larksdk.WikiNodeSearchV1Request{
+			collected := newListCollector[larksdk.WikiNodeSearchV1Item](state, paging.itemLimit())
+			pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.WikiNodeSearchV1Item], error) {
+				result, err := state.SDK.SearchWikiNodesV1(ctx, token, larksdk.WikiNodeSearchV1Request{
 					Query:     query,
 					SpaceID:   spaceID,
-					PageSize:  pageSize,
+					PageSize:  collected.pageSize(200),
 					PageToken: pageToken,
 				})
-				if err != nil {
-					return withUserScopeHintForCommand(state, err)
-				}
-				nodes = append(nodes, result.Items...)
-				if len(nodes) >= limit || !result.HasMore {
-					break
-				}
-				remaining = limit - len(nodes)
-				pageToken = result.PageToken
-				if pageToken == "" {
-					break
-				}
+				return larksdk.Page[larksdk.WikiNodeSearchV1Item]{Items: result.Items, PageToken: result.PageToken, HasMore: result.HasMore}, err
+			})
+			if err := drainPages(cmd.Context(), state, collected, pager, paging.pageLimit()); err != nil {
+				return withUserScopeHintForCommand(state, err)
 			}
-			if len(nodes) > limit {
-				nodes = nodes[:limit]
+			if collected.streaming {
+				return nil
 			}
+			nodes := collected.items
 
 			payload := map[string]any{"nodes": nodes}
 			lines := make([]string, 0, len(nodes))
@@ -110,8 +89,9 @@ func newWikiNodeSearchCmd(state *appState) *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&spaceID, "space-id", "", "Wiki space ID")
-	cmd.Flags().IntVar(&limit, "limit", 50, "max number of nodes to return")
-	cmd.Flags().IntVar(&pages, "pages", 1, "max number of pages to fetch")
+	cmd.Flags().IntVar(&paging.limit, "limit", 50, "max number of nodes to return")
+	cmd.Flags().IntVar(&paging.pages, "pages", 1, "max number of pages to fetch")
+	addPagingFlags(cmd, &paging)
 	cmd.Flags().StringVar(&userAccessToken, "user-access-token", "", "user access token (OAuth)")
 
 	return cmd
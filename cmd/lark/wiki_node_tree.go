@@ -61,16 +61,12 @@ func (b *wikiTreeBuilder) build(ctx context.Context, parentToken string, depth i
 
 func (b *wikiTreeBuilder) listNodes(ctx context.Context, parentToken string) ([]larksdk.WikiNode, error) {
 	items := make([]larksdk.WikiNode, 0)
-	pageToken := ""
-	for {
-		if b.remaining == 0 {
-			break
-		}
+	pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.WikiNode], error) {
 		ps := b.pageSize
 		if ps <= 0 {
 			ps = b.remaining
 		}
-		if ps > 50 {
+		if ps <= 0 || ps > 50 {
 			ps = 50
 		}
 		req := larksdk.ListWikiNodesRequest{
@@ -87,22 +83,22 @@ func (b *wikiTreeBuilder) listNodes(ctx context.Context, parentToken string) ([]
 		case tokenTypeUser:
 			result, err = b.sdk.ListWikiNodesV2WithUserToken(ctx, b.token, req)
 		default:
-			return nil, fmt.Errorf("unsupported token type %s", b.tokenType)
+			err = fmt.Errorf("unsupported token type %s", b.tokenType)
 		}
+		return larksdk.Page[larksdk.WikiNode]{Items: result.Items, PageToken: result.PageToken, HasMore: result.HasMore}, err
+	})
+	for b.remaining != 0 && !pager.Done() {
+		page, err := pager.Next(ctx)
 		if err != nil {
 			return nil, err
 		}
-		for _, node := range result.Items {
+		for _, node := range page {
 			if b.remaining == 0 {
 				break
 			}
 			items = append(items, node)
 			b.remaining--
 		}
-		if b.remaining == 0 || !result.HasMore || result.PageToken == "" {
-			break
-		}
-		pageToken = result.PageToken
 	}
 	return items, nil
 }
@@ -112,7 +108,7 @@ func newWikiNodeTreeCmd(state *appState) *cobra.Command {
 	var rootNodeToken string
 	var rootObjType string
 	var depth int
-	var limit int
+	var paging listPaging
 	var pageSize int
 
 	cmd := &cobra.Command{
@@ -131,14 +127,22 @@ func newWikiNodeTreeCmd(state *appState) *cobra.Command {
 			spaceID = strings.TrimSpace(spaceID)
 			rootNodeToken = strings.TrimSpace(rootNodeToken)
 			rootObjType = strings.TrimSpace(rootObjType)
-			if limit <= 0 {
-				limit = 200
+			if paging.limit <= 0 {
+				paging.limit = 200
+			}
+			if err := paging.validate(cmd); err != nil {
+				return err
+			}
+			// remaining < 0 walks the whole tree (--all without --max-items).
+			remaining := paging.itemLimit()
+			if remaining == 0 {
+				remaining = -1
 			}
 			builder := &wikiTreeBuilder{
 				spaceID:   spaceID,
 				pageSize:  pageSize,
 				depth:     depth,
-				remaining: limit,
+				remaining: remaining,
 			}
 
 			return runWithToken(cmd, state, nil, nil, func(ctx context.Context, sdk *larksdk.Client, token string, tokenType tokenType) (any, string, error) {
@@ -204,8 +208,9 @@ func newWikiNodeTreeCmd(state *appState) *cobra.Command {
 	cmd.Flags().StringVar(&rootNodeToken, "root-node-token", "", "root node token (optional)")
 	cmd.Flags().StringVar(&rootObjType, "root-obj-type", "", "root node obj type (optional)")
 	cmd.Flags().IntVar(&depth, "depth", 0, "max depth to traverse (0 = unlimited)")
-	cmd.Flags().IntVar(&limit, "limit", 200, "max number of nodes to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 200, "max number of nodes to return")
 	cmd.Flags().IntVar(&pageSize, "page-size", 0, "page size (default: auto)")
+	addPagingFlags(cmd, &paging)
 	_ = cmd.MarkFlagRequired("space-id")
 	return cmd
 }
@@ -23,60 +23,49 @@ func newWikiSpaceCmd(state *appState) *cobra.Command {
 }
 
 func newWikiSpaceListCmd(state *appState) *cobra.Command {
-	var limit int
+	var paging listPaging
 	var pageSize int
 
 	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List Wiki spaces (v2)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if limit <= 0 {
-				limit = 50
+			if paging.limit <= 0 {
+				paging.limit = 50
+			}
+			if err := paging.validate(cmd); err != nil {
+				return err
 			}
 			return runWithToken(cmd, state, nil, nil, func(ctx context.Context, sdk *larksdk.Client, token string, tokenType tokenType) (any, string, error) {
-				items := make([]larksdk.WikiSpace, 0, limit)
-				pageToken := ""
-				remaining := limit
-				for {
+				collected := newListCollector[larksdk.WikiSpace](state, paging.itemLimit())
+				pager := larksdk.NewPaginator(func(ctx context.Context, pageToken string) (larksdk.Page[larksdk.WikiSpace], error) {
 					ps := pageSize
 					if ps <= 0 {
-						ps = remaining
-						if ps > 200 {
-							ps = 200
-						}
+						ps = collected.pageSize(200)
+					}
+					req := larksdk.ListWikiSpacesRequest{
+						PageSize:  ps,
+						PageToken: pageToken,
 					}
 					var result larksdk.ListWikiSpacesResult
 					var err error
 					switch tokenType {
 					case tokenTypeTenant:
-						result, err = sdk.ListWikiSpacesV2(ctx, token, larksdk.ListWikiSpacesRequest{
-							PageSize:  ps,
-							PageToken: pageToken,
-						})
+						result, err = sdk.ListWikiSpacesV2(ctx, token, req)
 					case tokenTypeUser:
-						result, err = sdk.ListWikiSpacesV2WithUserToken(ctx, token, larksdk.ListWikiSpacesRequest{
-							PageSize:  ps,
-							PageToken: pageToken,
-						})
+						result, err = sdk.ListWikiSpacesV2WithUserToken(ctx, token, req)
 					default:
-						return nil, "", fmt.Errorf("unsupported token type %s", tokenType)
-					}
-					if err != nil {
-						return nil, "", err
-					}
-					items = append(items, result.Items...)
-					if len(items) >= limit || !result.HasMore {
-						break
-					}
-					remaining = limit - len(items)
-					pageToken = result.PageToken
-					if pageToken == "" {
-						break
+						err = fmt.Errorf("unsupported token type %s", tokenType)
 					}
+					return larksdk.Page[larksdk.WikiSpace]{Items: result.Items, PageToken: result.PageToken, HasMore: result.HasMore}, err
+				})
+				if err := drainPages(ctx, state, collected, pager, 0); err != nil {
+					return nil, "", err
 				}
-				if len(items) > limit {
-					items = items[:limit]
+				if collected.streaming {
+					return nil, "", nil
 				}
+				items := collected.items
 
 				payload := map[string]any{"spaces": items}
 				lines := make([]string, 0, len(items))
@@ -89,8 +78,9 @@ func newWikiSpaceListCmd(state *appState) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().IntVar(&limit, "limit", 50, "max number of spaces to return")
+	cmd.Flags().IntVar(&paging.limit, "limit", 50, "max number of spaces to return")
 	cmd.Flags().IntVar(&pageSize, "page-size", 0, "page size (default: auto)")
+	addPagingFlags(cmd, &paging)
 	return cmd
 }
 
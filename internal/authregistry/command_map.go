@@ -10,7 +10,7 @@ var commandServiceMap = map[string][]string{
 	"drive":                 {"drive"},
 	"drive export":          {"drive-export"},
 	"docs":                  {"docs"},
-	"docs export":           {"drive-export"},
+	"docs export":           {"drive-export", "docs"},
 	"sheets":                {"sheets"},
 	"mail":                  {"mail"},
 	"mail send":             {"mail-send"},
@@ -30,6 +30,7 @@ var commandServiceMap = map[string][]string{
 	"tasklists update":      {"tasklist-write"},
 	"tasklists delete":      {"tasklist-write"},
 	"chats":                 {"im"},
+	"cards":                 {"im"},
 	"messages":              {"im"},
 	"msg":                   {"im"},
 	"msg search":            {"search-message"},
@@ -26,6 +26,10 @@ type Config struct {
 	// - auto: prefer keychain when supported; otherwise fall back to file.
 	KeyringBackend string `json:"keyring_backend,omitempty"`
 
+	// MaxRetries is the retry budget for rate-limited and transient API
+	// failures. Nil uses the built-in default; 0 disables retries.
+	MaxRetries *int `json:"max_retries,omitempty"`
+
 	UserScopes                 []string `json:"user_scopes,omitempty"`
 	TenantAccessToken          string   `json:"tenant_access_token"`
 	TenantAccessTokenExpiresAt int64    `json:"tenant_access_token_expires_at"`
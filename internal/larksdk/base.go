@@ -912,6 +912,12 @@ func (c *Client) SearchBaseRecords(ctx context.Context, token, appToken, tableID
 	}
 	apiReq.PathParams.Set("app_token", appToken)
 	apiReq.PathParams.Set("table_id", tableID)
+	if req.PageSize > 0 {
+		apiReq.QueryParams.Set("page_size", strconv.Itoa(req.PageSize))
+	}
+	if req.PageToken != "" {
+		apiReq.QueryParams.Set("page_token", req.PageToken)
+	}
 
 	apiResp, err := larkcore.Request(ctx, apiReq, c.coreConfig, larkcore.WithTenantAccessToken(tenantToken))
 	if err != nil {
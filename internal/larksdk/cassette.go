@@ -0,0 +1,386 @@
+package larksdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Redacted replaces secrets in recorded cassettes.
+const Redacted = "REDACTED"
+
+// redactedKeys are JSON fields and query parameters whose values are scrubbed
+// from cassettes. Resource tokens (page_token, file_token, ...) are kept so
+// replays still match.
+var redactedKeys = map[string]bool{
+	"access_token":        true,
+	"app_access_token":    true,
+	"app_secret":          true,
+	"client_secret":       true,
+	"code_verifier":       true,
+	"encrypt_key":         true,
+	"password":            true,
+	"refresh_token":       true,
+	"tenant_access_token": true,
+	"user_access_token":   true,
+	"verification_token":  true,
+}
+
+// redactedFormKeys are scrubbed from form-encoded bodies on top of
+// redactedKeys. OAuth endpoints post the authorization code as "code", which
+// in JSON bodies is the API status instead.
+var redactedFormKeys = map[string]bool{
+	"code": true,
+}
+
+// omittedBody stands in for request bodies that cannot be redacted field by
+// field, such as multipart uploads.
+var omittedBody = json.RawMessage(`"[omitted: not JSON or form-encoded]"`)
+
+var redactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization"}
+
+var cassetteNameUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Interaction is one recorded HTTP exchange, stored as a JSON file.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the redacted request half of an Interaction. URL holds
+// the path and query only, so cassettes replay against any base URL.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	RecordedBody
+}
+
+// RecordedResponse is the redacted response half of an Interaction.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	RecordedBody
+}
+
+// RecordedBody keeps JSON bodies readable; anything else is base64-encoded.
+type RecordedBody struct {
+	Body       json.RawMessage `json:"body,omitempty"`
+	BodyBase64 []byte          `json:"body_base64,omitempty"`
+}
+
+func (b RecordedBody) bytes() []byte {
+	if len(b.Body) > 0 {
+		return b.Body
+	}
+	return b.BodyBase64
+}
+
+// newRecordedBody redacts JSON and form-encoded bodies. Other request bodies
+// are replaced by a placeholder, since replay never matches on them; other
+// response bodies (file downloads) are kept so they can be replayed.
+func newRecordedBody(header http.Header, data []byte, request bool) RecordedBody {
+	if len(data) == 0 {
+		return RecordedBody{}
+	}
+	if json.Valid(data) {
+		return RecordedBody{Body: redactJSON(data)}
+	}
+	if mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type")); mediaType == "application/x-www-form-urlencoded" {
+		values, err := url.ParseQuery(string(data))
+		if err != nil {
+			return RecordedBody{Body: omittedBody}
+		}
+		return RecordedBody{BodyBase64: []byte(redactForm(values).Encode())}
+	}
+	if request {
+		return RecordedBody{Body: omittedBody}
+	}
+	return RecordedBody{BodyBase64: data}
+}
+
+// RecordingTransport writes every exchange that passes through it to dir as a
+// numbered, redacted cassette file. The caller still sees the real response.
+type RecordingTransport struct {
+	base http.RoundTripper
+	dir  string
+	mu   sync.Mutex
+	seq  int
+}
+
+// NewRecordingTransport creates dir if needed and continues numbering after
+// any cassettes already in it, so several commands can share one directory.
+func NewRecordingTransport(base http.RoundTripper, dir string) (*RecordingTransport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create record dir: %w", err)
+	}
+	names, err := cassetteFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	seq := 0
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(filepath.Base(name), "-")
+		if n, err := strconv.Atoi(prefix); err == nil && n > seq {
+			seq = n
+		}
+	}
+	return &RecordingTransport{base: base, dir: dir, seq: seq}, nil
+}
+
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		data, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = data
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(data))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		}
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method:       req.Method,
+			URL:          cassetteURL(req.URL),
+			Header:       redactHeader(req.Header),
+			RecordedBody: newRecordedBody(req.Header, reqBody, true),
+		},
+		Response: RecordedResponse{
+			StatusCode:   resp.StatusCode,
+			Header:       redactHeader(resp.Header),
+			RecordedBody: newRecordedBody(resp.Header, respBody, false),
+		},
+	}
+	if err := t.write(interaction); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (t *RecordingTransport) write(interaction Interaction) error {
+	data, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seq++
+	path, _, _ := strings.Cut(interaction.Request.URL, "?")
+	slug := strings.Trim(cassetteNameUnsafe.ReplaceAllString(strings.ToLower(path), "-"), "-")
+	if len(slug) > 80 {
+		slug = slug[:80]
+	}
+	name := fmt.Sprintf("%04d-%s-%s.json", t.seq, strings.ToLower(interaction.Request.Method), slug)
+	if err := os.WriteFile(filepath.Join(t.dir, name), append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write cassette: %w", err)
+	}
+	return nil
+}
+
+// ReplayTransport serves recorded cassettes instead of calling the network.
+// Requests match on method, path, and query; repeated requests are answered
+// by successive recordings in file order.
+type ReplayTransport struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayTransport loads every cassette in dir.
+func NewReplayTransport(dir string) (*ReplayTransport, error) {
+	names, err := cassetteFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no cassettes found in %s", dir)
+	}
+	t := &ReplayTransport{}
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		var interaction Interaction
+		if err := json.Unmarshal(data, &interaction); err != nil {
+			return nil, fmt.Errorf("parse cassette %s: %w", filepath.Base(name), err)
+		}
+		t.interactions = append(t.interactions, interaction)
+	}
+	t.used = make([]bool, len(t.interactions))
+	return t, nil
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	target := cassetteURL(req.URL)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, interaction := range t.interactions {
+		if t.used[i] || interaction.Request.Method != req.Method || interaction.Request.URL != target {
+			continue
+		}
+		t.used[i] = true
+		header := interaction.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		// Redaction can change the body length.
+		header.Del("Content-Length")
+		body := interaction.Response.bytes()
+		return &http.Response{
+			StatusCode:    interaction.Response.StatusCode,
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	if isTokenEndpoint(req.URL.Path) {
+		// The recording may have used a cached token, so token fetches are
+		// answered even when they were never recorded.
+		body := fmt.Sprintf(`{"code":0,"msg":"ok","tenant_access_token":%q,"app_access_token":%q,"expire":7200}`, Redacted, Redacted)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "200 OK",
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	}
+	return nil, fmt.Errorf("replay: no recorded response for %s %s", req.Method, target)
+}
+
+func isTokenEndpoint(path string) bool {
+	return strings.HasSuffix(path, "/auth/v3/tenant_access_token/internal") || strings.HasSuffix(path, "/auth/v3/app_access_token/internal")
+}
+
+func cassetteFiles(dir string) ([]string, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("cassette dir: %w", err)
+	}
+	names, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// cassetteURL is the path plus redacted query, with parameters sorted so
+// matching does not depend on encoding order.
+func cassetteURL(u *url.URL) string {
+	query := u.Query()
+	for key := range query {
+		if redactedKeys[strings.ToLower(key)] {
+			query[key] = []string{Redacted}
+		}
+	}
+	path := u.EscapedPath()
+	if encoded := query.Encode(); encoded != "" {
+		return path + "?" + encoded
+	}
+	return path
+}
+
+func redactHeader(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	out := header.Clone()
+	for _, name := range redactedHeaders {
+		if out.Get(name) == "" {
+			continue
+		}
+		if name == "Authorization" || name == "Proxy-Authorization" {
+			scheme, _, _ := strings.Cut(out.Get(name), " ")
+			out.Set(name, scheme+" "+Redacted)
+			continue
+		}
+		out.Set(name, Redacted)
+	}
+	return out
+}
+
+func redactForm(values url.Values) url.Values {
+	for key := range values {
+		name := strings.ToLower(key)
+		if redactedKeys[name] || redactedFormKeys[name] {
+			values[key] = []string{Redacted}
+		}
+	}
+	return values
+}
+
+func redactJSON(data []byte) json.RawMessage {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return json.RawMessage(data)
+	}
+	out, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return json.RawMessage(data)
+	}
+	return out
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if redactedKeys[strings.ToLower(key)] {
+				if item != nil {
+					v[key] = Redacted
+				}
+				continue
+			}
+			v[key] = redactValue(item)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
@@ -0,0 +1,181 @@
+package larksdk
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordingTransportRedactsAndReplays(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/open-apis/auth/v3/tenant_access_token/internal":
+			_, _ = io.WriteString(w, `{"code":0,"tenant_access_token":"t-secret","expire":7200}`)
+		case "/open-apis/im/v1/chats":
+			_, _ = io.WriteString(w, `{"code":0,"data":{"items":[{"chat_id":"oc_1"}],"page_token":"p2"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder, err := NewRecordingTransport(nil, dir)
+	if err != nil {
+		t.Fatalf("recorder: %v", err)
+	}
+	client := &http.Client{Transport: recorder}
+
+	resp, err := client.Post(server.URL+"/open-apis/auth/v3/tenant_access_token/internal", "application/json", strings.NewReader(`{"app_id":"cli_1","app_secret":"s3cret"}`))
+	if err != nil {
+		t.Fatalf("token request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "t-secret") {
+		t.Fatalf("caller should see the real response, got %s", body)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/open-apis/im/v1/chats?page_size=20&user_id_type=open_id", nil)
+	req.Header.Set("Authorization", "Bearer t-secret")
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("chats request: %v", err)
+	}
+	resp.Body.Close()
+
+	names, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(names) != 2 || !strings.HasSuffix(names[1], "0002-get-open-apis-im-v1-chats.json") {
+		t.Fatalf("unexpected cassettes: %v", names)
+	}
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("read cassette: %v", err)
+		}
+		if strings.Contains(string(data), "s3cret") || strings.Contains(string(data), "t-secret") {
+			t.Fatalf("cassette %s leaks a secret:\n%s", filepath.Base(name), data)
+		}
+	}
+
+	replay, err := NewReplayTransport(dir)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	client = &http.Client{Transport: replay}
+	resp, err = client.Get("http://offline.test/open-apis/im/v1/chats?user_id_type=open_id&page_size=20")
+	if err != nil {
+		t.Fatalf("replayed request: %v", err)
+	}
+	var payload struct {
+		Data struct {
+			Items []map[string]any `json:"items"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil || len(payload.Data.Items) != 1 {
+		t.Fatalf("unexpected replayed body: %+v %v", payload, err)
+	}
+	resp.Body.Close()
+
+	if _, err := client.Get("http://offline.test/open-apis/im/v1/chats?user_id_type=open_id&page_size=20"); err == nil || !strings.Contains(err.Error(), "no recorded response") {
+		t.Fatalf("expected each recording to be served once, got %v", err)
+	}
+}
+
+func TestRecordingTransportRedactsFormAndOmitsOpaqueBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/open-apis/authen/v2/oauth/token":
+			w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+			_, _ = io.WriteString(w, "access_token=u-secret&expires_in=7200&refresh_token=r-secret")
+		case "/open-apis/drive/v1/medias/upload_all":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"code":0,"data":{"file_token":"box_1"}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder, err := NewRecordingTransport(nil, dir)
+	if err != nil {
+		t.Fatalf("recorder: %v", err)
+	}
+	client := &http.Client{Transport: recorder}
+
+	form := "grant_type=authorization_code&client_id=cli_1&client_secret=c-secret&code=a-secret"
+	resp, err := client.Post(server.URL+"/open-apis/authen/v2/oauth/token", "application/x-www-form-urlencoded", strings.NewReader(form))
+	if err != nil {
+		t.Fatalf("token request: %v", err)
+	}
+	resp.Body.Close()
+	resp, err = client.Post(server.URL+"/open-apis/drive/v1/medias/upload_all", "multipart/form-data; boundary=x", strings.NewReader("--x\r\nContent-Disposition: form-data; name=\"extra\"\r\n\r\nm-secret\r\n--x--\r\n"))
+	if err != nil {
+		t.Fatalf("upload request: %v", err)
+	}
+	resp.Body.Close()
+
+	names, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(names) != 2 {
+		t.Fatalf("unexpected cassettes: %v", names)
+	}
+	var interactions []Interaction
+	for _, name := range names {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("read cassette: %v", err)
+		}
+		var interaction Interaction
+		if err := json.Unmarshal(data, &interaction); err != nil {
+			t.Fatalf("parse cassette: %v", err)
+		}
+		for _, body := range [][]byte{interaction.Request.bytes(), interaction.Response.bytes()} {
+			for _, secret := range []string{"c-secret", "a-secret", "u-secret", "r-secret", "m-secret"} {
+				if strings.Contains(string(body), secret) {
+					t.Fatalf("cassette %s leaks %s:\n%s", filepath.Base(name), secret, data)
+				}
+			}
+		}
+		interactions = append(interactions, interaction)
+	}
+	if got := string(interactions[0].Request.bytes()); !strings.Contains(got, "client_id=cli_1") || !strings.Contains(got, "code="+Redacted) {
+		t.Fatalf("form fields should be redacted individually, got %q", got)
+	}
+	if got := string(interactions[1].Request.bytes()); !strings.Contains(got, "omitted") {
+		t.Fatalf("multipart body should be omitted, got %q", got)
+	}
+}
+
+func TestReplayTransportAnswersUnrecordedTokenFetch(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "0001-get-x.json"), []byte(`{"request":{"method":"GET","url":"/x"},"response":{"status_code":204}}`), 0o600); err != nil {
+		t.Fatalf("write cassette: %v", err)
+	}
+	replay, err := NewReplayTransport(dir)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	client := &http.Client{Transport: replay}
+	resp, err := client.Post("http://offline.test/open-apis/auth/v3/tenant_access_token/internal", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("token request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), Redacted) {
+		t.Fatalf("unexpected token body: %s", body)
+	}
+}
+
+func TestNewReplayTransportRequiresCassettes(t *testing.T) {
+	if _, err := NewReplayTransport(t.TempDir()); err == nil {
+		t.Fatalf("expected error for empty dir")
+	}
+}
@@ -85,3 +85,192 @@ func mapChatMember(member *im.ListMember) ChatMember {
 	}
 	return out
 }
+
+// AddChatMembers adds users or bots to a chat. Members that could not be added
+// are reported in the result rather than failing the whole request (when
+// SucceedType is 0 or 1).
+func (c *Client) AddChatMembers(ctx context.Context, token string, tokenType AccessTokenType, req ChatMembersRequest) (ChatMembersResult, error) {
+	if !c.available() {
+		return ChatMembersResult{}, ErrUnavailable
+	}
+	chatID, err := validateChatMembersRequest(req)
+	if err != nil {
+		return ChatMembersResult{}, err
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return ChatMembersResult{}, err
+	}
+	builder := im.NewCreateChatMembersReqBuilder().
+		ChatId(chatID).
+		SucceedType(req.SucceedType).
+		Body(im.NewCreateChatMembersReqBodyBuilder().IdList(req.IDs).Build())
+	if strings.TrimSpace(req.MemberIDType) != "" {
+		builder.MemberIdType(strings.TrimSpace(req.MemberIDType))
+	}
+	resp, err := c.sdk.Im.V1.ChatMembers.Create(ctx, builder.Build(), option)
+	if err != nil {
+		return ChatMembersResult{}, err
+	}
+	if resp == nil {
+		return ChatMembersResult{}, errors.New("add chat members failed: empty response")
+	}
+	if !resp.Success() {
+		return ChatMembersResult{}, formatCodeError("add chat members failed", resp.CodeError, resp.ApiResp)
+	}
+	result := ChatMembersResult{}
+	if resp.Data != nil {
+		result.InvalidIDList = resp.Data.InvalidIdList
+		result.NotExistedIDList = resp.Data.NotExistedIdList
+		result.PendingApprovalIDList = resp.Data.PendingApprovalIdList
+	}
+	return result, nil
+}
+
+// RemoveChatMembers removes users or bots from a chat.
+func (c *Client) RemoveChatMembers(ctx context.Context, token string, tokenType AccessTokenType, req ChatMembersRequest) (ChatMembersResult, error) {
+	if !c.available() {
+		return ChatMembersResult{}, ErrUnavailable
+	}
+	chatID, err := validateChatMembersRequest(req)
+	if err != nil {
+		return ChatMembersResult{}, err
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return ChatMembersResult{}, err
+	}
+	builder := im.NewDeleteChatMembersReqBuilder().
+		ChatId(chatID).
+		Body(im.NewDeleteChatMembersReqBodyBuilder().IdList(req.IDs).Build())
+	if strings.TrimSpace(req.MemberIDType) != "" {
+		builder.MemberIdType(strings.TrimSpace(req.MemberIDType))
+	}
+	resp, err := c.sdk.Im.V1.ChatMembers.Delete(ctx, builder.Build(), option)
+	if err != nil {
+		return ChatMembersResult{}, err
+	}
+	if resp == nil {
+		return ChatMembersResult{}, errors.New("remove chat members failed: empty response")
+	}
+	if !resp.Success() {
+		return ChatMembersResult{}, formatCodeError("remove chat members failed", resp.CodeError, resp.ApiResp)
+	}
+	result := ChatMembersResult{}
+	if resp.Data != nil {
+		result.InvalidIDList = resp.Data.InvalidIdList
+	}
+	return result, nil
+}
+
+// AddChatManagers promotes chat members to managers.
+func (c *Client) AddChatManagers(ctx context.Context, token string, tokenType AccessTokenType, req ChatMembersRequest) (ChatManagersResult, error) {
+	if !c.available() {
+		return ChatManagersResult{}, ErrUnavailable
+	}
+	chatID, err := validateChatMembersRequest(req)
+	if err != nil {
+		return ChatManagersResult{}, err
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return ChatManagersResult{}, err
+	}
+	builder := im.NewAddManagersChatManagersReqBuilder().
+		ChatId(chatID).
+		Body(im.NewAddManagersChatManagersReqBodyBuilder().ManagerIds(req.IDs).Build())
+	if strings.TrimSpace(req.MemberIDType) != "" {
+		builder.MemberIdType(strings.TrimSpace(req.MemberIDType))
+	}
+	resp, err := c.sdk.Im.V1.ChatManagers.AddManagers(ctx, builder.Build(), option)
+	if err != nil {
+		return ChatManagersResult{}, err
+	}
+	if resp == nil {
+		return ChatManagersResult{}, errors.New("add chat managers failed: empty response")
+	}
+	if !resp.Success() {
+		return ChatManagersResult{}, formatCodeError("add chat managers failed", resp.CodeError, resp.ApiResp)
+	}
+	result := ChatManagersResult{}
+	if resp.Data != nil {
+		result.ChatManagers = resp.Data.ChatManagers
+		result.ChatBotManagers = resp.Data.ChatBotManagers
+	}
+	return result, nil
+}
+
+// RemoveChatManagers demotes chat managers to regular members.
+func (c *Client) RemoveChatManagers(ctx context.Context, token string, tokenType AccessTokenType, req ChatMembersRequest) (ChatManagersResult, error) {
+	if !c.available() {
+		return ChatManagersResult{}, ErrUnavailable
+	}
+	chatID, err := validateChatMembersRequest(req)
+	if err != nil {
+		return ChatManagersResult{}, err
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return ChatManagersResult{}, err
+	}
+	builder := im.NewDeleteManagersChatManagersReqBuilder().
+		ChatId(chatID).
+		Body(im.NewDeleteManagersChatManagersReqBodyBuilder().ManagerIds(req.IDs).Build())
+	if strings.TrimSpace(req.MemberIDType) != "" {
+		builder.MemberIdType(strings.TrimSpace(req.MemberIDType))
+	}
+	resp, err := c.sdk.Im.V1.ChatManagers.DeleteManagers(ctx, builder.Build(), option)
+	if err != nil {
+		return ChatManagersResult{}, err
+	}
+	if resp == nil {
+		return ChatManagersResult{}, errors.New("remove chat managers failed: empty response")
+	}
+	if !resp.Success() {
+		return ChatManagersResult{}, formatCodeError("remove chat managers failed", resp.CodeError, resp.ApiResp)
+	}
+	result := ChatManagersResult{}
+	if resp.Data != nil {
+		result.ChatManagers = resp.Data.ChatManagers
+		result.ChatBotManagers = resp.Data.ChatBotManagers
+	}
+	return result, nil
+}
+
+// DisbandChat deletes a chat. Only the owner (or the bot that created it) can
+// disband a group.
+func (c *Client) DisbandChat(ctx context.Context, token string, tokenType AccessTokenType, chatID string) error {
+	if !c.available() {
+		return ErrUnavailable
+	}
+	chatID = strings.TrimSpace(chatID)
+	if chatID == "" {
+		return errors.New("chat id is required")
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return err
+	}
+	resp, err := c.sdk.Im.V1.Chat.Delete(ctx, im.NewDeleteChatReqBuilder().ChatId(chatID).Build(), option)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return errors.New("disband chat failed: empty response")
+	}
+	if !resp.Success() {
+		return formatCodeError("disband chat failed", resp.CodeError, resp.ApiResp)
+	}
+	return nil
+}
+
+func validateChatMembersRequest(req ChatMembersRequest) (string, error) {
+	chatID := strings.TrimSpace(req.ChatID)
+	if chatID == "" {
+		return "", errors.New("chat id is required")
+	}
+	if len(req.IDs) == 0 {
+		return "", errors.New("at least one member id is required")
+	}
+	return chatID, nil
+}
@@ -18,6 +18,7 @@ type Option func(*options)
 type options struct {
 	httpClient        *http.Client
 	tenantAccessToken string
+	retry             *RetryPolicy
 }
 
 // WithHTTPClient overrides the HTTP client used by the SDK.
@@ -66,6 +67,9 @@ func New(cfg *config.Config, opts ...Option) (*Client, error) {
 		clientOptions = append(clientOptions, lark.WithOpenBaseUrl(cfg.BaseURL))
 		coreConfig.BaseUrl = cfg.BaseURL
 	}
+	if settings.retry != nil && settings.retry.MaxRetries > 0 {
+		settings.httpClient = wrapHTTPClientWithRetry(settings.httpClient, *settings.retry)
+	}
 	if settings.httpClient != nil {
 		clientOptions = append(clientOptions, lark.WithHttpClient(settings.httpClient))
 		coreConfig.HttpClient = settings.httpClient
@@ -238,19 +238,34 @@ func (c *Client) DeleteDriveFile(ctx context.Context, token, fileToken, fileType
 	if !c.available() {
 		return DeleteDriveFileResult{}, ErrUnavailable
 	}
+	tenantToken := c.tenantToken(token)
+	if tenantToken == "" {
+		return DeleteDriveFileResult{}, errors.New("tenant access token is required")
+	}
+	return c.deleteDriveFile(ctx, fileToken, fileType, larkcore.WithTenantAccessToken(tenantToken))
+}
+
+func (c *Client) DeleteDriveFileWithUserToken(ctx context.Context, userAccessToken, fileToken, fileType string) (DeleteDriveFileResult, error) {
+	if !c.available() {
+		return DeleteDriveFileResult{}, ErrUnavailable
+	}
+	userAccessToken = strings.TrimSpace(userAccessToken)
+	if userAccessToken == "" {
+		return DeleteDriveFileResult{}, errors.New("user access token is required")
+	}
+	return c.deleteDriveFile(ctx, fileToken, fileType, larkcore.WithUserAccessToken(userAccessToken))
+}
+
+func (c *Client) deleteDriveFile(ctx context.Context, fileToken, fileType string, option larkcore.RequestOptionFunc) (DeleteDriveFileResult, error) {
 	if fileToken == "" {
 		return DeleteDriveFileResult{}, errors.New("file token is required")
 	}
 	if fileType == "" {
 		return DeleteDriveFileResult{}, errors.New("file type is required")
 	}
-	tenantToken := c.tenantToken(token)
-	if tenantToken == "" {
-		return DeleteDriveFileResult{}, errors.New("tenant access token is required")
-	}
 
 	builder := larkdrive.NewDeleteFileReqBuilder().FileToken(fileToken).Type(fileType)
-	resp, err := c.sdk.Drive.V1.File.Delete(ctx, builder.Build(), larkcore.WithTenantAccessToken(tenantToken))
+	resp, err := c.sdk.Drive.V1.File.Delete(ctx, builder.Build(), option)
 	if err != nil {
 		return DeleteDriveFileResult{}, err
 	}
@@ -267,6 +282,49 @@ func (c *Client) DeleteDriveFile(ctx context.Context, token, fileToken, fileType
 	return result, nil
 }
 
+type CopyDriveFileRequest struct {
+	FileToken   string
+	FileType    string
+	Name        string
+	FolderToken string
+}
+
+func (c *Client) CopyDriveFile(ctx context.Context, token string, tokenType AccessTokenType, req CopyDriveFileRequest) (DriveFile, error) {
+	if !c.available() {
+		return DriveFile{}, ErrUnavailable
+	}
+	if req.FileToken == "" {
+		return DriveFile{}, errors.New("file token is required")
+	}
+	if req.FileType == "" {
+		return DriveFile{}, errors.New("file type is required")
+	}
+	if req.FolderToken == "" {
+		return DriveFile{}, errors.New("folder token is required")
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return DriveFile{}, err
+	}
+
+	body := larkdrive.NewCopyFileReqBodyBuilder().Name(req.Name).Type(req.FileType).FolderToken(req.FolderToken).Build()
+	builder := larkdrive.NewCopyFileReqBuilder().FileToken(req.FileToken).Body(body)
+	resp, err := c.sdk.Drive.V1.File.Copy(ctx, builder.Build(), option)
+	if err != nil {
+		return DriveFile{}, err
+	}
+	if resp == nil {
+		return DriveFile{}, errors.New("copy drive file failed: empty response")
+	}
+	if !resp.Success() {
+		return DriveFile{}, formatCodeError("copy drive file failed", resp.CodeError, resp.ApiResp)
+	}
+	if resp.Data == nil || resp.Data.File == nil {
+		return DriveFile{}, errors.New("copy drive file failed: missing file")
+	}
+	return mapDriveFile(resp.Data.File), nil
+}
+
 type DrivePermissionPublic struct {
 	ExternalAccess  bool   `json:"external_access"`
 	SecurityEntity  string `json:"security_entity"`
@@ -480,5 +538,11 @@ func mapDriveFile(file *larkdrive.File) DriveFile {
 	if file.OwnerId != nil {
 		result.OwnerID = *file.OwnerId
 	}
+	if file.CreatedTime != nil {
+		result.CreatedTime = *file.CreatedTime
+	}
+	if file.ModifiedTime != nil {
+		result.ModifiedTime = *file.ModifiedTime
+	}
 	return result
 }
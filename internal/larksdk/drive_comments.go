@@ -0,0 +1,393 @@
+package larksdk
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	larkdrive "github.com/larksuite/oapi-sdk-go/v3/service/drive/v1"
+)
+
+// DriveComment is a comment thread on a Drive file. Quote is the anchored
+// text for a partial comment; whole-document comments have none. Times are
+// unix seconds.
+type DriveComment struct {
+	CommentID    string              `json:"comment_id"`
+	UserID       string              `json:"user_id,omitempty"`
+	CreateTime   int64               `json:"create_time,omitempty"`
+	UpdateTime   int64               `json:"update_time,omitempty"`
+	IsSolved     bool                `json:"is_solved"`
+	SolvedTime   int64               `json:"solved_time,omitempty"`
+	SolverUserID string              `json:"solver_user_id,omitempty"`
+	IsWhole      bool                `json:"is_whole"`
+	Quote        string              `json:"quote,omitempty"`
+	Replies      []DriveCommentReply `json:"replies"`
+	// HasMoreReplies is set when Replies is only the first page of the thread.
+	HasMoreReplies bool   `json:"-"`
+	ReplyPageToken string `json:"-"`
+}
+
+// DriveCommentReply is one message in a comment thread; the first reply is the
+// comment body itself. Text flattens the reply's text runs, links and mentions.
+type DriveCommentReply struct {
+	ReplyID    string `json:"reply_id"`
+	UserID     string `json:"user_id,omitempty"`
+	CreateTime int64  `json:"create_time,omitempty"`
+	UpdateTime int64  `json:"update_time,omitempty"`
+	Text       string `json:"text"`
+}
+
+type ListDriveCommentsRequest struct {
+	FileToken string
+	FileType  string
+	// Solved filters by resolution state when set.
+	Solved     *bool
+	UserIDType string
+	PageSize   int
+	PageToken  string
+}
+
+type ListDriveCommentsResult struct {
+	Items     []DriveComment
+	PageToken string
+	HasMore   bool
+}
+
+type ListDriveCommentRepliesRequest struct {
+	FileToken  string
+	FileType   string
+	CommentID  string
+	UserIDType string
+	PageSize   int
+	PageToken  string
+}
+
+type ListDriveCommentRepliesResult struct {
+	Items     []DriveCommentReply
+	PageToken string
+	HasMore   bool
+}
+
+// CreateDriveCommentRequest adds a whole-document comment, or a reply to an
+// existing thread when CommentID is set.
+type CreateDriveCommentRequest struct {
+	FileToken  string
+	FileType   string
+	CommentID  string
+	Text       string
+	UserIDType string
+}
+
+func (c *Client) ListDriveComments(ctx context.Context, token string, tokenType AccessTokenType, req ListDriveCommentsRequest) (ListDriveCommentsResult, error) {
+	if !c.available() {
+		return ListDriveCommentsResult{}, ErrUnavailable
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return ListDriveCommentsResult{}, err
+	}
+	fileToken, fileType, err := driveCommentFile(req.FileToken, req.FileType)
+	if err != nil {
+		return ListDriveCommentsResult{}, err
+	}
+
+	builder := larkdrive.NewListFileCommentReqBuilder().FileToken(fileToken).FileType(fileType)
+	if req.Solved != nil {
+		builder.IsSolved(*req.Solved)
+	}
+	if req.UserIDType != "" {
+		builder.UserIdType(req.UserIDType)
+	}
+	if req.PageSize > 0 {
+		builder.PageSize(req.PageSize)
+	}
+	if req.PageToken != "" {
+		builder.PageToken(req.PageToken)
+	}
+	resp, err := c.sdk.Drive.V1.FileComment.List(ctx, builder.Build(), option)
+	if err != nil {
+		return ListDriveCommentsResult{}, err
+	}
+	if resp == nil {
+		return ListDriveCommentsResult{}, errors.New("list comments failed: empty response")
+	}
+	if !resp.Success() {
+		return ListDriveCommentsResult{}, formatCodeError("list comments failed", resp.CodeError, resp.ApiResp)
+	}
+
+	result := ListDriveCommentsResult{}
+	if resp.Data != nil {
+		result.Items = make([]DriveComment, 0, len(resp.Data.Items))
+		for _, item := range resp.Data.Items {
+			if item == nil {
+				continue
+			}
+			result.Items = append(result.Items, mapDriveComment(item))
+		}
+		if resp.Data.PageToken != nil {
+			result.PageToken = *resp.Data.PageToken
+		}
+		if resp.Data.HasMore != nil {
+			result.HasMore = *resp.Data.HasMore
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) GetDriveComment(ctx context.Context, token string, tokenType AccessTokenType, fileToken, fileType, commentID, userIDType string) (DriveComment, error) {
+	if !c.available() {
+		return DriveComment{}, ErrUnavailable
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return DriveComment{}, err
+	}
+	fileToken, fileType, err = driveCommentFile(fileToken, fileType)
+	if err != nil {
+		return DriveComment{}, err
+	}
+	commentID = strings.TrimSpace(commentID)
+	if commentID == "" {
+		return DriveComment{}, errors.New("comment id is required")
+	}
+
+	builder := larkdrive.NewGetFileCommentReqBuilder().FileToken(fileToken).FileType(fileType).CommentId(commentID)
+	if userIDType != "" {
+		builder.UserIdType(userIDType)
+	}
+	resp, err := c.sdk.Drive.V1.FileComment.Get(ctx, builder.Build(), option)
+	if err != nil {
+		return DriveComment{}, err
+	}
+	if resp == nil {
+		return DriveComment{}, errors.New("get comment failed: empty response")
+	}
+	if !resp.Success() {
+		return DriveComment{}, formatCodeError("get comment failed", resp.CodeError, resp.ApiResp)
+	}
+	if resp.Data == nil {
+		return DriveComment{}, errors.New("get comment failed: missing comment")
+	}
+	return mapDriveComment((*larkdrive.FileComment)(resp.Data)), nil
+}
+
+func (c *Client) CreateDriveComment(ctx context.Context, token string, tokenType AccessTokenType, req CreateDriveCommentRequest) (DriveComment, error) {
+	if !c.available() {
+		return DriveComment{}, ErrUnavailable
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return DriveComment{}, err
+	}
+	fileToken, fileType, err := driveCommentFile(req.FileToken, req.FileType)
+	if err != nil {
+		return DriveComment{}, err
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		return DriveComment{}, errors.New("comment text is required")
+	}
+
+	element := larkdrive.NewReplyElementBuilder().
+		Type("text_run").
+		TextRun(larkdrive.NewTextRunBuilder().Text(req.Text).Build()).
+		Build()
+	reply := larkdrive.NewFileCommentReplyBuilder().
+		Content(larkdrive.NewReplyContentBuilder().Elements([]*larkdrive.ReplyElement{element}).Build()).
+		Build()
+	comment := larkdrive.NewFileCommentBuilder().
+		ReplyList(larkdrive.NewReplyListBuilder().Replies([]*larkdrive.FileCommentReply{reply}).Build())
+	if commentID := strings.TrimSpace(req.CommentID); commentID != "" {
+		comment.CommentId(commentID)
+	}
+	builder := larkdrive.NewCreateFileCommentReqBuilder().FileToken(fileToken).FileType(fileType).FileComment(comment.Build())
+	if req.UserIDType != "" {
+		builder.UserIdType(req.UserIDType)
+	}
+	resp, err := c.sdk.Drive.V1.FileComment.Create(ctx, builder.Build(), option)
+	if err != nil {
+		return DriveComment{}, err
+	}
+	if resp == nil {
+		return DriveComment{}, errors.New("create comment failed: empty response")
+	}
+	if !resp.Success() {
+		return DriveComment{}, formatCodeError("create comment failed", resp.CodeError, resp.ApiResp)
+	}
+	if resp.Data == nil {
+		return DriveComment{}, errors.New("create comment failed: missing comment")
+	}
+	return mapDriveComment((*larkdrive.FileComment)(resp.Data)), nil
+}
+
+// SetDriveCommentSolved resolves or reopens a comment thread.
+func (c *Client) SetDriveCommentSolved(ctx context.Context, token string, tokenType AccessTokenType, fileToken, fileType, commentID string, solved bool) error {
+	if !c.available() {
+		return ErrUnavailable
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return err
+	}
+	fileToken, fileType, err = driveCommentFile(fileToken, fileType)
+	if err != nil {
+		return err
+	}
+	commentID = strings.TrimSpace(commentID)
+	if commentID == "" {
+		return errors.New("comment id is required")
+	}
+
+	req := larkdrive.NewPatchFileCommentReqBuilder().
+		FileToken(fileToken).
+		FileType(fileType).
+		CommentId(commentID).
+		Body(larkdrive.NewPatchFileCommentReqBodyBuilder().IsSolved(solved).Build()).
+		Build()
+	resp, err := c.sdk.Drive.V1.FileComment.Patch(ctx, req, option)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return errors.New("update comment failed: empty response")
+	}
+	if !resp.Success() {
+		return formatCodeError("update comment failed", resp.CodeError, resp.ApiResp)
+	}
+	return nil
+}
+
+func (c *Client) ListDriveCommentReplies(ctx context.Context, token string, tokenType AccessTokenType, req ListDriveCommentRepliesRequest) (ListDriveCommentRepliesResult, error) {
+	if !c.available() {
+		return ListDriveCommentRepliesResult{}, ErrUnavailable
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return ListDriveCommentRepliesResult{}, err
+	}
+	fileToken, fileType, err := driveCommentFile(req.FileToken, req.FileType)
+	if err != nil {
+		return ListDriveCommentRepliesResult{}, err
+	}
+	commentID := strings.TrimSpace(req.CommentID)
+	if commentID == "" {
+		return ListDriveCommentRepliesResult{}, errors.New("comment id is required")
+	}
+
+	builder := larkdrive.NewListFileCommentReplyReqBuilder().FileToken(fileToken).FileType(fileType).CommentId(commentID)
+	if req.UserIDType != "" {
+		builder.UserIdType(req.UserIDType)
+	}
+	if req.PageSize > 0 {
+		builder.PageSize(req.PageSize)
+	}
+	if req.PageToken != "" {
+		builder.PageToken(req.PageToken)
+	}
+	resp, err := c.sdk.Drive.V1.FileCommentReply.List(ctx, builder.Build(), option)
+	if err != nil {
+		return ListDriveCommentRepliesResult{}, err
+	}
+	if resp == nil {
+		return ListDriveCommentRepliesResult{}, errors.New("list comment replies failed: empty response")
+	}
+	if !resp.Success() {
+		return ListDriveCommentRepliesResult{}, formatCodeError("list comment replies failed", resp.CodeError, resp.ApiResp)
+	}
+
+	result := ListDriveCommentRepliesResult{}
+	if resp.Data != nil {
+		result.Items = make([]DriveCommentReply, 0, len(resp.Data.Items))
+		for _, item := range resp.Data.Items {
+			if item == nil {
+				continue
+			}
+			result.Items = append(result.Items, mapDriveCommentReply(item))
+		}
+		if resp.Data.PageToken != nil {
+			result.PageToken = *resp.Data.PageToken
+		}
+		if resp.Data.HasMore != nil {
+			result.HasMore = *resp.Data.HasMore
+		}
+	}
+	return result, nil
+}
+
+func driveCommentFile(fileToken, fileType string) (string, string, error) {
+	fileToken = strings.TrimSpace(fileToken)
+	if fileToken == "" {
+		return "", "", errors.New("file token is required")
+	}
+	fileType = strings.TrimSpace(fileType)
+	if fileType == "" {
+		return "", "", errors.New("file type is required")
+	}
+	return fileToken, fileType, nil
+}
+
+func mapDriveComment(comment *larkdrive.FileComment) DriveComment {
+	result := DriveComment{
+		CommentID:      derefString(comment.CommentId),
+		UserID:         derefString(comment.UserId),
+		CreateTime:     derefIntAsInt64(comment.CreateTime),
+		UpdateTime:     derefIntAsInt64(comment.UpdateTime),
+		SolvedTime:     derefIntAsInt64(comment.SolvedTime),
+		SolverUserID:   derefString(comment.SolverUserId),
+		Quote:          derefString(comment.Quote),
+		ReplyPageToken: derefString(comment.PageToken),
+		Replies:        []DriveCommentReply{},
+	}
+	if comment.IsSolved != nil {
+		result.IsSolved = *comment.IsSolved
+	}
+	if comment.IsWhole != nil {
+		result.IsWhole = *comment.IsWhole
+	}
+	if comment.HasMore != nil {
+		result.HasMoreReplies = *comment.HasMore
+	}
+	if comment.ReplyList != nil {
+		for _, reply := range comment.ReplyList.Replies {
+			if reply == nil {
+				continue
+			}
+			result.Replies = append(result.Replies, mapDriveCommentReply(reply))
+		}
+	}
+	return result
+}
+
+func mapDriveCommentReply(reply *larkdrive.FileCommentReply) DriveCommentReply {
+	result := DriveCommentReply{
+		ReplyID:    derefString(reply.ReplyId),
+		UserID:     derefString(reply.UserId),
+		CreateTime: derefIntAsInt64(reply.CreateTime),
+		UpdateTime: derefIntAsInt64(reply.UpdateTime),
+	}
+	if reply.Content == nil {
+		return result
+	}
+	var text strings.Builder
+	for _, element := range reply.Content.Elements {
+		if element == nil {
+			continue
+		}
+		switch {
+		case element.TextRun != nil:
+			text.WriteString(derefString(element.TextRun.Text))
+		case element.DocsLink != nil:
+			text.WriteString(derefString(element.DocsLink.Url))
+		case element.Person != nil:
+			text.WriteString("@" + derefString(element.Person.UserId))
+		}
+	}
+	result.Text = text.String()
+	return result
+}
+
+func derefIntAsInt64(value *int) int64 {
+	if value == nil {
+		return 0
+	}
+	return int64(*value)
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math"
+	"strings"
 
 	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
 	larkdrive "github.com/larksuite/oapi-sdk-go/v3/service/drive/v1"
@@ -70,3 +72,35 @@ func (c *Client) UploadDriveMedia(ctx context.Context, token string, req UploadD
 	}
 	return result, nil
 }
+
+// DownloadDriveMedia downloads a media asset (docx image or file block) by its
+// media token.
+func (c *Client) DownloadDriveMedia(ctx context.Context, token string, tokenType AccessTokenType, fileToken string) (DriveDownload, error) {
+	if !c.available() {
+		return DriveDownload{}, ErrUnavailable
+	}
+	if fileToken == "" {
+		return DriveDownload{}, fmt.Errorf("media token is required")
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return DriveDownload{}, err
+	}
+	resp, err := c.sdk.Drive.V1.Media.Download(ctx, larkdrive.NewDownloadMediaReqBuilder().FileToken(fileToken).Build(), option)
+	if err != nil {
+		return DriveDownload{}, err
+	}
+	if resp == nil {
+		return DriveDownload{}, errors.New("drive media download failed: empty response")
+	}
+	if resp.File != nil {
+		return DriveDownload{
+			Reader:   io.NopCloser(resp.File),
+			FileName: strings.TrimSpace(resp.FileName),
+		}, nil
+	}
+	if !resp.Success() {
+		return DriveDownload{}, formatCodeError("drive media download failed", resp.CodeError, resp.ApiResp)
+	}
+	return DriveDownload{}, errors.New("drive media download failed: empty file")
+}
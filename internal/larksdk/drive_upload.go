@@ -0,0 +1,175 @@
+package larksdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	larkdrive "github.com/larksuite/oapi-sdk-go/v3/service/drive/v1"
+)
+
+// DriveUploadAllMaxSize is the largest file accepted by upload_all; larger
+// files must use the upload_prepare/upload_part/upload_finish flow.
+const DriveUploadAllMaxSize int64 = 20 << 20
+
+type PrepareDriveUploadRequest struct {
+	FileName    string
+	FolderToken string
+	Size        int64
+}
+
+// DriveUploadSession describes a multipart upload transaction returned by
+// upload_prepare. Parts are numbered from 0 to BlockNum-1 and every part except
+// the last is exactly BlockSize bytes.
+type DriveUploadSession struct {
+	UploadID  string `json:"upload_id"`
+	BlockSize int64  `json:"block_size"`
+	BlockNum  int    `json:"block_num"`
+}
+
+type UploadDrivePartRequest struct {
+	UploadID string
+	Seq      int
+	Size     int64
+	Checksum string
+	Data     io.Reader
+}
+
+func (c *Client) PrepareDriveUpload(ctx context.Context, token string, tokenType AccessTokenType, req PrepareDriveUploadRequest) (DriveUploadSession, error) {
+	if !c.available() {
+		return DriveUploadSession{}, ErrUnavailable
+	}
+	if req.FileName == "" {
+		return DriveUploadSession{}, fmt.Errorf("file name is required")
+	}
+	if req.Size <= 0 {
+		return DriveUploadSession{}, fmt.Errorf("file size must be positive")
+	}
+	if req.Size > math.MaxInt {
+		return DriveUploadSession{}, fmt.Errorf("file size exceeds platform limits")
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return DriveUploadSession{}, err
+	}
+
+	info := larkdrive.NewFileUploadInfoBuilder().
+		FileName(req.FileName).
+		ParentType("explorer").
+		ParentNode(driveUploadParentNode(req.FolderToken)).
+		Size(int(req.Size)).
+		Build()
+	resp, err := c.sdk.Drive.V1.File.UploadPrepare(ctx, larkdrive.NewUploadPrepareFileReqBuilder().FileUploadInfo(info).Build(), option)
+	if err != nil {
+		return DriveUploadSession{}, err
+	}
+	if resp == nil {
+		return DriveUploadSession{}, errors.New("drive upload prepare failed: empty response")
+	}
+	if !resp.Success() {
+		return DriveUploadSession{}, formatCodeError("drive upload prepare failed", resp.CodeError, resp.ApiResp)
+	}
+	session := DriveUploadSession{}
+	if resp.Data != nil {
+		if resp.Data.UploadId != nil {
+			session.UploadID = *resp.Data.UploadId
+		}
+		if resp.Data.BlockSize != nil {
+			session.BlockSize = int64(*resp.Data.BlockSize)
+		}
+		if resp.Data.BlockNum != nil {
+			session.BlockNum = *resp.Data.BlockNum
+		}
+	}
+	if session.UploadID == "" {
+		return DriveUploadSession{}, errors.New("drive upload prepare response missing upload_id")
+	}
+	if session.BlockSize <= 0 || session.BlockNum <= 0 {
+		return DriveUploadSession{}, errors.New("drive upload prepare response missing block layout")
+	}
+	return session, nil
+}
+
+func (c *Client) UploadDrivePart(ctx context.Context, token string, tokenType AccessTokenType, req UploadDrivePartRequest) error {
+	if !c.available() {
+		return ErrUnavailable
+	}
+	if req.UploadID == "" {
+		return fmt.Errorf("upload id is required")
+	}
+	if req.Data == nil {
+		return fmt.Errorf("part data is required")
+	}
+	if req.Seq < 0 {
+		return fmt.Errorf("part seq must be non-negative")
+	}
+	if req.Size <= 0 || req.Size > math.MaxInt {
+		return fmt.Errorf("invalid part size %d", req.Size)
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return err
+	}
+
+	builder := larkdrive.NewUploadPartFileReqBodyBuilder().
+		UploadId(req.UploadID).
+		Seq(req.Seq).
+		Size(int(req.Size)).
+		File(req.Data)
+	if req.Checksum != "" {
+		builder.Checksum(req.Checksum)
+	}
+	resp, err := c.sdk.Drive.V1.File.UploadPart(ctx, larkdrive.NewUploadPartFileReqBuilder().Body(builder.Build()).Build(), option)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return errors.New("drive upload part failed: empty response")
+	}
+	if !resp.Success() {
+		return formatCodeError(fmt.Sprintf("drive upload part %d failed", req.Seq), resp.CodeError, resp.ApiResp)
+	}
+	return nil
+}
+
+func (c *Client) FinishDriveUpload(ctx context.Context, token string, tokenType AccessTokenType, uploadID string, blockNum int) (string, error) {
+	if !c.available() {
+		return "", ErrUnavailable
+	}
+	if uploadID == "" {
+		return "", fmt.Errorf("upload id is required")
+	}
+	if blockNum <= 0 {
+		return "", fmt.Errorf("block num must be positive")
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return "", err
+	}
+
+	body := larkdrive.NewUploadFinishFileReqBodyBuilder().UploadId(uploadID).BlockNum(blockNum).Build()
+	resp, err := c.sdk.Drive.V1.File.UploadFinish(ctx, larkdrive.NewUploadFinishFileReqBuilder().Body(body).Build(), option)
+	if err != nil {
+		return "", err
+	}
+	if resp == nil {
+		return "", errors.New("drive upload finish failed: empty response")
+	}
+	if !resp.Success() {
+		return "", formatCodeError("drive upload finish failed", resp.CodeError, resp.ApiResp)
+	}
+	if resp.Data == nil || resp.Data.FileToken == nil || *resp.Data.FileToken == "" {
+		return "", errors.New("drive upload finish response missing file token")
+	}
+	return *resp.Data.FileToken, nil
+}
+
+func driveUploadParentNode(folderToken string) string {
+	if folderToken == "" || folderToken == "root" {
+		// Lark/Feishu Drive upload expects root folder token to be "0".
+		return "0"
+	}
+	return folderToken
+}
@@ -162,6 +162,28 @@ type ListChatMembersRequest struct {
 	PageToken    string
 }
 
+// MessageReadUser is one user who has read a message. Timestamp is in
+// milliseconds.
+type MessageReadUser struct {
+	UserIDType string `json:"user_id_type,omitempty"`
+	UserID     string `json:"user_id,omitempty"`
+	Timestamp  string `json:"timestamp,omitempty"`
+	TenantKey  string `json:"tenant_key,omitempty"`
+}
+
+type ListMessageReadUsersRequest struct {
+	MessageID  string
+	UserIDType string
+	PageSize   int
+	PageToken  string
+}
+
+type ListMessageReadUsersResult struct {
+	Items     []MessageReadUser
+	PageToken string
+	HasMore   bool
+}
+
 type ListChatMembersResult struct {
 	Items       []ChatMember
 	PageToken   string
@@ -169,6 +191,29 @@ type ListChatMembersResult struct {
 	MemberTotal int
 }
 
+// ChatMembersRequest targets chat members or managers by ID. MemberIDType is
+// open_id, user_id, union_id, or app_id (for bots).
+type ChatMembersRequest struct {
+	ChatID       string
+	MemberIDType string
+	IDs          []string
+	// SucceedType controls partial success when adding members: 0 adds valid
+	// members and skips departed users, 1 also skips invisible or inactive
+	// members and reports them, 2 fails the whole request.
+	SucceedType int
+}
+
+type ChatMembersResult struct {
+	InvalidIDList         []string `json:"invalid_id_list,omitempty"`
+	NotExistedIDList      []string `json:"not_existed_id_list,omitempty"`
+	PendingApprovalIDList []string `json:"pending_approval_id_list,omitempty"`
+}
+
+type ChatManagersResult struct {
+	ChatManagers    []string `json:"chat_managers,omitempty"`
+	ChatBotManagers []string `json:"chat_bot_managers,omitempty"`
+}
+
 type CreateChatRequest struct {
 	UserIDType             string
 	SetBotManager          *bool
@@ -11,6 +11,17 @@ import (
 	im "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
 )
 
+// SendMessageError is returned when the API rejects a message. Code is the
+// Lark error code, so callers can tell permanent failures from transient ones.
+type SendMessageError struct {
+	Code int
+	Msg  string
+}
+
+func (e *SendMessageError) Error() string {
+	return fmt.Sprintf("send message failed: %s", e.Msg)
+}
+
 func (c *Client) SendMessage(ctx context.Context, token string, req MessageRequest) (string, error) {
 	if !c.available() {
 		return "", ErrUnavailable
@@ -52,7 +63,7 @@ func (c *Client) SendMessage(ctx context.Context, token string, req MessageReque
 		return "", errors.New("send message failed: empty response")
 	}
 	if !resp.Success() {
-		return "", fmt.Errorf("send message failed: %s", resp.Msg)
+		return "", &SendMessageError{Code: resp.Code, Msg: resp.Msg}
 	}
 	if resp.Data != nil && resp.Data.MessageId != nil {
 		return *resp.Data.MessageId, nil
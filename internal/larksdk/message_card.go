@@ -0,0 +1,46 @@
+package larksdk
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	im "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+// PatchMessageCard replaces the content of an interactive (card) message via
+// PATCH im/v1/messages/:message_id. Only cards sent by the same app with
+// update_multi enabled can be patched.
+func (c *Client) PatchMessageCard(ctx context.Context, token string, messageID string, content string) error {
+	if !c.available() {
+		return ErrUnavailable
+	}
+	tenantToken := c.tenantToken(token)
+	if tenantToken == "" {
+		return errors.New("tenant access token is required")
+	}
+	messageID = strings.TrimSpace(messageID)
+	if messageID == "" {
+		return errors.New("message id is required")
+	}
+	if strings.TrimSpace(content) == "" {
+		return errors.New("card content is required")
+	}
+
+	req := im.NewPatchMessageReqBuilder().
+		MessageId(messageID).
+		Body(im.NewPatchMessageReqBodyBuilder().Content(content).Build()).
+		Build()
+	resp, err := c.sdk.Im.V1.Message.Patch(ctx, req, larkcore.WithTenantAccessToken(tenantToken))
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return errors.New("update message card failed: empty response")
+	}
+	if !resp.Success() {
+		return formatCodeError("update message card failed", resp.CodeError, resp.ApiResp)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package larksdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	im "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+// RecallMessage recalls (deletes) a message via DELETE im/v1/messages/:message_id.
+// Bots can recall their own messages, and group owners or admins can recall
+// other members' messages.
+func (c *Client) RecallMessage(ctx context.Context, token string, tokenType AccessTokenType, messageID string) error {
+	if !c.available() {
+		return ErrUnavailable
+	}
+	messageID = strings.TrimSpace(messageID)
+	if messageID == "" {
+		return errors.New("message id is required")
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return err
+	}
+	req := im.NewDeleteMessageReqBuilder().MessageId(messageID).Build()
+	resp, err := c.sdk.Im.V1.Message.Delete(ctx, req, option)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return errors.New("recall message failed: empty response")
+	}
+	if !resp.Success() {
+		return formatCodeError("recall message failed", resp.CodeError, resp.ApiResp)
+	}
+	return nil
+}
+
+// EditMessage replaces the content of a sent text or post message via
+// PUT im/v1/messages/:message_id. Cards are updated with PatchMessageCard.
+func (c *Client) EditMessage(ctx context.Context, token string, tokenType AccessTokenType, messageID, msgType, content string) error {
+	if !c.available() {
+		return ErrUnavailable
+	}
+	messageID = strings.TrimSpace(messageID)
+	if messageID == "" {
+		return errors.New("message id is required")
+	}
+	switch msgType {
+	case "text", "post":
+	default:
+		return fmt.Errorf("only text and post messages can be edited (got %q)", msgType)
+	}
+	if strings.TrimSpace(content) == "" {
+		return errors.New("content is required")
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return err
+	}
+	req := im.NewUpdateMessageReqBuilder().
+		MessageId(messageID).
+		Body(im.NewUpdateMessageReqBodyBuilder().MsgType(msgType).Content(content).Build()).
+		Build()
+	resp, err := c.sdk.Im.V1.Message.Update(ctx, req, option)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return errors.New("edit message failed: empty response")
+	}
+	if !resp.Success() {
+		return formatCodeError("edit message failed", resp.CodeError, resp.ApiResp)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package larksdk
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	im "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+type ForwardMessageRequest struct {
+	MessageID     string
+	ReceiveID     string
+	ReceiveIDType string
+	UUID          string
+}
+
+type MergeForwardMessageRequest struct {
+	MessageIDs    []string
+	ReceiveID     string
+	ReceiveIDType string
+	UUID          string
+}
+
+type MergeForwardResult struct {
+	Message           Message  `json:"message"`
+	InvalidMessageIDs []string `json:"invalid_message_id_list,omitempty"`
+}
+
+// ForwardMessage forwards one message to a chat or user and returns the new
+// message ID.
+func (c *Client) ForwardMessage(ctx context.Context, token string, req ForwardMessageRequest) (string, error) {
+	if !c.available() {
+		return "", ErrUnavailable
+	}
+	tenantToken := c.tenantToken(token)
+	if tenantToken == "" {
+		return "", errors.New("tenant access token is required")
+	}
+	messageID := strings.TrimSpace(req.MessageID)
+	if messageID == "" {
+		return "", errors.New("message id is required")
+	}
+	if strings.TrimSpace(req.ReceiveID) == "" {
+		return "", errors.New("receive id is required")
+	}
+	receiveIDType := req.ReceiveIDType
+	if receiveIDType == "" {
+		receiveIDType = "chat_id"
+	}
+
+	builder := im.NewForwardMessageReqBuilder().
+		MessageId(messageID).
+		ReceiveIdType(receiveIDType).
+		Body(im.NewForwardMessageReqBodyBuilder().ReceiveId(strings.TrimSpace(req.ReceiveID)).Build())
+	if strings.TrimSpace(req.UUID) != "" {
+		builder.Uuid(strings.TrimSpace(req.UUID))
+	}
+	resp, err := c.sdk.Im.V1.Message.Forward(ctx, builder.Build(), larkcore.WithTenantAccessToken(tenantToken))
+	if err != nil {
+		return "", err
+	}
+	if resp == nil {
+		return "", errors.New("forward message failed: empty response")
+	}
+	if !resp.Success() {
+		return "", formatCodeError("forward message failed", resp.CodeError, resp.ApiResp)
+	}
+	if resp.Data != nil && resp.Data.MessageId != nil {
+		return *resp.Data.MessageId, nil
+	}
+	return "", nil
+}
+
+// MergeForwardMessage forwards several messages from one chat as a single
+// merged (chat history) message.
+func (c *Client) MergeForwardMessage(ctx context.Context, token string, req MergeForwardMessageRequest) (MergeForwardResult, error) {
+	if !c.available() {
+		return MergeForwardResult{}, ErrUnavailable
+	}
+	tenantToken := c.tenantToken(token)
+	if tenantToken == "" {
+		return MergeForwardResult{}, errors.New("tenant access token is required")
+	}
+	if len(req.MessageIDs) == 0 {
+		return MergeForwardResult{}, errors.New("message ids are required")
+	}
+	if strings.TrimSpace(req.ReceiveID) == "" {
+		return MergeForwardResult{}, errors.New("receive id is required")
+	}
+	receiveIDType := req.ReceiveIDType
+	if receiveIDType == "" {
+		receiveIDType = "chat_id"
+	}
+
+	body := im.NewMergeForwardMessageReqBodyBuilder().
+		ReceiveId(strings.TrimSpace(req.ReceiveID)).
+		MessageIdList(req.MessageIDs).
+		Build()
+	builder := im.NewMergeForwardMessageReqBuilder().
+		ReceiveIdType(receiveIDType).
+		Body(body)
+	if strings.TrimSpace(req.UUID) != "" {
+		builder.Uuid(strings.TrimSpace(req.UUID))
+	}
+	resp, err := c.sdk.Im.V1.Message.MergeForward(ctx, builder.Build(), larkcore.WithTenantAccessToken(tenantToken))
+	if err != nil {
+		return MergeForwardResult{}, err
+	}
+	if resp == nil {
+		return MergeForwardResult{}, errors.New("merge forward messages failed: empty response")
+	}
+	if !resp.Success() {
+		return MergeForwardResult{}, formatCodeError("merge forward messages failed", resp.CodeError, resp.ApiResp)
+	}
+	result := MergeForwardResult{}
+	if resp.Data != nil {
+		if resp.Data.Message != nil {
+			result.Message = mapMessage(resp.Data.Message)
+		}
+		result.InvalidMessageIDs = resp.Data.InvalidMessageIdList
+	}
+	return result, nil
+}
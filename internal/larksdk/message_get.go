@@ -6,29 +6,33 @@ import (
 	"fmt"
 	"strings"
 
-	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
 	im "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
 )
 
 func (c *Client) GetMessage(ctx context.Context, userAccessToken, messageID, userIDType string) (Message, error) {
+	return c.GetMessageWithToken(ctx, userAccessToken, AccessTokenUser, messageID, userIDType)
+}
+
+// GetMessageWithToken fetches a message with either a tenant or user token.
+func (c *Client) GetMessageWithToken(ctx context.Context, token string, tokenType AccessTokenType, messageID, userIDType string) (Message, error) {
 	if !c.available() {
 		return Message{}, ErrUnavailable
 	}
-	userAccessToken = strings.TrimSpace(userAccessToken)
-	if userAccessToken == "" {
-		return Message{}, errors.New("user access token is required")
-	}
 	messageID = strings.TrimSpace(messageID)
 	if messageID == "" {
 		return Message{}, errors.New("message id is required")
 	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return Message{}, err
+	}
 
 	builder := im.NewGetMessageReqBuilder().MessageId(messageID)
 	if strings.TrimSpace(userIDType) != "" {
 		builder.UserIdType(userIDType)
 	}
 
-	resp, err := c.sdk.Im.V1.Message.Get(ctx, builder.Build(), larkcore.WithUserAccessToken(userAccessToken))
+	resp, err := c.sdk.Im.V1.Message.Get(ctx, builder.Build(), option)
 	if err != nil {
 		return Message{}, err
 	}
@@ -0,0 +1,72 @@
+package larksdk
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	im "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+// ListMessageReadUsers lists users who have read a message via
+// im/v1/messages/:message_id/read_users. Only messages the app sent in the
+// last 7 days can be queried.
+func (c *Client) ListMessageReadUsers(ctx context.Context, token string, req ListMessageReadUsersRequest) (ListMessageReadUsersResult, error) {
+	if !c.available() {
+		return ListMessageReadUsersResult{}, ErrUnavailable
+	}
+	tenantToken := c.tenantToken(token)
+	if tenantToken == "" {
+		return ListMessageReadUsersResult{}, errors.New("tenant access token is required")
+	}
+	messageID := strings.TrimSpace(req.MessageID)
+	if messageID == "" {
+		return ListMessageReadUsersResult{}, errors.New("message id is required")
+	}
+	userIDType := strings.TrimSpace(req.UserIDType)
+	if userIDType == "" {
+		userIDType = "open_id"
+	}
+
+	builder := im.NewReadUsersMessageReqBuilder().MessageId(messageID).UserIdType(userIDType)
+	if req.PageSize > 0 {
+		builder.PageSize(req.PageSize)
+	}
+	if strings.TrimSpace(req.PageToken) != "" {
+		builder.PageToken(req.PageToken)
+	}
+	resp, err := c.sdk.Im.V1.Message.ReadUsers(ctx, builder.Build(), larkcore.WithTenantAccessToken(tenantToken))
+	if err != nil {
+		return ListMessageReadUsersResult{}, err
+	}
+	if resp == nil {
+		return ListMessageReadUsersResult{}, errors.New("list message read users failed: empty response")
+	}
+	if !resp.Success() {
+		return ListMessageReadUsersResult{}, formatCodeError("list message read users failed", resp.CodeError, resp.ApiResp)
+	}
+
+	result := ListMessageReadUsersResult{}
+	if resp.Data != nil {
+		result.Items = make([]MessageReadUser, 0, len(resp.Data.Items))
+		for _, item := range resp.Data.Items {
+			if item == nil {
+				continue
+			}
+			result.Items = append(result.Items, MessageReadUser{
+				UserIDType: derefString(item.UserIdType),
+				UserID:     derefString(item.UserId),
+				Timestamp:  derefString(item.Timestamp),
+				TenantKey:  derefString(item.TenantKey),
+			})
+		}
+		if resp.Data.PageToken != nil {
+			result.PageToken = *resp.Data.PageToken
+		}
+		if resp.Data.HasMore != nil {
+			result.HasMore = *resp.Data.HasMore
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package larksdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	im "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+// DownloadMessageResource downloads an image or file attached to a message via
+// im/v1/messages/:message_id/resources/:file_key. resourceType is "image" for
+// image_key values and "file" for file_key values (files, audio, and video).
+func (c *Client) DownloadMessageResource(ctx context.Context, token string, tokenType AccessTokenType, messageID, fileKey, resourceType string) (DriveDownload, error) {
+	if !c.available() {
+		return DriveDownload{}, ErrUnavailable
+	}
+	messageID = strings.TrimSpace(messageID)
+	if messageID == "" {
+		return DriveDownload{}, errors.New("message id is required")
+	}
+	fileKey = strings.TrimSpace(fileKey)
+	if fileKey == "" {
+		return DriveDownload{}, errors.New("file key is required")
+	}
+	switch resourceType {
+	case "image", "file":
+	default:
+		return DriveDownload{}, fmt.Errorf("resource type must be image or file (got %q)", resourceType)
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return DriveDownload{}, err
+	}
+	req := im.NewGetMessageResourceReqBuilder().
+		MessageId(messageID).
+		FileKey(fileKey).
+		Type(resourceType).
+		Build()
+	resp, err := c.sdk.Im.V1.MessageResource.Get(ctx, req, option)
+	if err != nil {
+		return DriveDownload{}, err
+	}
+	if resp == nil {
+		return DriveDownload{}, errors.New("message resource download failed: empty response")
+	}
+	if resp.File != nil {
+		return DriveDownload{
+			Reader:   io.NopCloser(resp.File),
+			FileName: strings.TrimSpace(resp.FileName),
+		}, nil
+	}
+	if !resp.Success() {
+		return DriveDownload{}, formatCodeError("message resource download failed", resp.CodeError, resp.ApiResp)
+	}
+	return DriveDownload{}, errors.New("message resource download failed: empty file")
+}
@@ -0,0 +1,102 @@
+package larksdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	im "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+// Upload limits enforced by im/v1/images and im/v1/files.
+const (
+	MessageImageMaxSize int64 = 10 << 20
+	MessageFileMaxSize  int64 = 30 << 20
+)
+
+type UploadMessageFileRequest struct {
+	// FileType is one of opus, mp4, pdf, doc, xls, ppt, stream.
+	FileType string
+	FileName string
+	// Duration is the audio/video length in milliseconds (optional).
+	Duration int
+	File     io.Reader
+}
+
+// UploadMessageImage uploads an image via im/v1/images and returns the
+// image_key to use in image messages.
+func (c *Client) UploadMessageImage(ctx context.Context, token string, image io.Reader) (string, error) {
+	if !c.available() {
+		return "", ErrUnavailable
+	}
+	tenantToken := c.tenantToken(token)
+	if tenantToken == "" {
+		return "", errors.New("tenant access token is required")
+	}
+	if image == nil {
+		return "", errors.New("image is required")
+	}
+	body := im.NewCreateImageReqBodyBuilder().
+		ImageType(im.ImageTypeMessage).
+		Image(image).
+		Build()
+	resp, err := c.sdk.Im.V1.Image.Create(ctx, im.NewCreateImageReqBuilder().Body(body).Build(), larkcore.WithTenantAccessToken(tenantToken))
+	if err != nil {
+		return "", err
+	}
+	if resp == nil {
+		return "", errors.New("upload image failed: empty response")
+	}
+	if !resp.Success() {
+		return "", formatCodeError("upload image failed", resp.CodeError, resp.ApiResp)
+	}
+	if resp.Data == nil || resp.Data.ImageKey == nil || *resp.Data.ImageKey == "" {
+		return "", errors.New("upload image response missing image_key")
+	}
+	return *resp.Data.ImageKey, nil
+}
+
+// UploadMessageFile uploads a file via im/v1/files and returns the file_key
+// to use in file, audio, and media messages.
+func (c *Client) UploadMessageFile(ctx context.Context, token string, req UploadMessageFileRequest) (string, error) {
+	if !c.available() {
+		return "", ErrUnavailable
+	}
+	tenantToken := c.tenantToken(token)
+	if tenantToken == "" {
+		return "", errors.New("tenant access token is required")
+	}
+	if req.File == nil {
+		return "", errors.New("file is required")
+	}
+	if strings.TrimSpace(req.FileName) == "" {
+		return "", errors.New("file name is required")
+	}
+	fileType := strings.TrimSpace(req.FileType)
+	if fileType == "" {
+		fileType = im.FileTypeStream
+	}
+	builder := im.NewCreateFileReqBodyBuilder().
+		FileType(fileType).
+		FileName(req.FileName).
+		File(req.File)
+	if req.Duration > 0 {
+		builder.Duration(req.Duration)
+	}
+	resp, err := c.sdk.Im.V1.File.Create(ctx, im.NewCreateFileReqBuilder().Body(builder.Build()).Build(), larkcore.WithTenantAccessToken(tenantToken))
+	if err != nil {
+		return "", err
+	}
+	if resp == nil {
+		return "", errors.New("upload file failed: empty response")
+	}
+	if !resp.Success() {
+		return "", formatCodeError("upload file failed", resp.CodeError, resp.ApiResp)
+	}
+	if resp.Data == nil || resp.Data.FileKey == nil || *resp.Data.FileKey == "" {
+		return "", errors.New("upload file response missing file_key")
+	}
+	return *resp.Data.FileKey, nil
+}
@@ -0,0 +1,98 @@
+package larksdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+	im "github.com/larksuite/oapi-sdk-go/v3/service/im/v1"
+)
+
+type UrgentMessageRequest struct {
+	MessageID  string
+	Mode       string
+	UserIDType string
+	UserIDs    []string
+}
+
+// UrgentMessage buzzes the given users about a message the app sent, via
+// PATCH im/v1/messages/:message_id/urgent_app|urgent_sms|urgent_phone. It
+// returns the user IDs the API rejected.
+func (c *Client) UrgentMessage(ctx context.Context, token string, req UrgentMessageRequest) ([]string, error) {
+	if !c.available() {
+		return nil, ErrUnavailable
+	}
+	tenantToken := c.tenantToken(token)
+	if tenantToken == "" {
+		return nil, errors.New("tenant access token is required")
+	}
+	messageID := strings.TrimSpace(req.MessageID)
+	if messageID == "" {
+		return nil, errors.New("message id is required")
+	}
+	if len(req.UserIDs) == 0 {
+		return nil, errors.New("user ids are required")
+	}
+	userIDType := req.UserIDType
+	if userIDType == "" {
+		userIDType = "open_id"
+	}
+	receivers := im.NewUrgentReceiversBuilder().UserIdList(req.UserIDs).Build()
+	option := larkcore.WithTenantAccessToken(tenantToken)
+
+	var (
+		codeError larkcore.CodeError
+		apiResp   *larkcore.ApiResp
+		invalid   []string
+		ok        bool
+	)
+	switch req.Mode {
+	case "app":
+		resp, err := c.sdk.Im.V1.Message.UrgentApp(ctx, im.NewUrgentAppMessageReqBuilder().
+			MessageId(messageID).UserIdType(userIDType).UrgentReceivers(receivers).Build(), option)
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			return nil, errors.New("urgent message failed: empty response")
+		}
+		codeError, apiResp, ok = resp.CodeError, resp.ApiResp, resp.Success()
+		if resp.Data != nil {
+			invalid = resp.Data.InvalidUserIdList
+		}
+	case "sms":
+		resp, err := c.sdk.Im.V1.Message.UrgentSms(ctx, im.NewUrgentSmsMessageReqBuilder().
+			MessageId(messageID).UserIdType(userIDType).UrgentReceivers(receivers).Build(), option)
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			return nil, errors.New("urgent message failed: empty response")
+		}
+		codeError, apiResp, ok = resp.CodeError, resp.ApiResp, resp.Success()
+		if resp.Data != nil {
+			invalid = resp.Data.InvalidUserIdList
+		}
+	case "phone":
+		resp, err := c.sdk.Im.V1.Message.UrgentPhone(ctx, im.NewUrgentPhoneMessageReqBuilder().
+			MessageId(messageID).UserIdType(userIDType).UrgentReceivers(receivers).Build(), option)
+		if err != nil {
+			return nil, err
+		}
+		if resp == nil {
+			return nil, errors.New("urgent message failed: empty response")
+		}
+		codeError, apiResp, ok = resp.CodeError, resp.ApiResp, resp.Success()
+		if resp.Data != nil {
+			invalid = resp.Data.InvalidUserIdList
+		}
+	default:
+		return nil, fmt.Errorf("urgent mode must be app, sms, or phone (got %q)", req.Mode)
+	}
+	if !ok {
+		return nil, formatCodeError("urgent message failed", codeError, apiResp)
+	}
+	return invalid, nil
+}
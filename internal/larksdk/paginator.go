@@ -0,0 +1,69 @@
+package larksdk
+
+import "context"
+
+// Page is one page of a page_token/has_more list API.
+type Page[T any] struct {
+	Items     []T
+	PageToken string
+	HasMore   bool
+}
+
+// PageFunc fetches the page that starts at pageToken ("" for the first page).
+type PageFunc[T any] func(ctx context.Context, pageToken string) (Page[T], error)
+
+// Paginator iterates a list API page by page. It stops when the server
+// reports has_more=false, returns an empty page token, or repeats a token.
+type Paginator[T any] struct {
+	fetch     PageFunc[T]
+	pageToken string
+	hasMore   bool
+	done      bool
+	pages     int
+}
+
+func NewPaginator[T any](fetch PageFunc[T]) *Paginator[T] {
+	return &Paginator[T]{fetch: fetch}
+}
+
+// Next fetches the next page. It returns ctx.Err() without a request when the
+// context is already canceled.
+func (p *Paginator[T]) Next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	page, err := p.fetch(ctx, p.pageToken)
+	if err != nil {
+		return nil, err
+	}
+	p.pages++
+	p.hasMore = page.HasMore
+	if !page.HasMore || page.PageToken == "" || page.PageToken == p.pageToken {
+		p.done = true
+	}
+	p.pageToken = page.PageToken
+	return page.Items, nil
+}
+
+// Done reports whether the last page has been fetched.
+func (p *Paginator[T]) Done() bool {
+	return p.done
+}
+
+// Pages returns the number of pages fetched so far.
+func (p *Paginator[T]) Pages() int {
+	return p.pages
+}
+
+// HasMore reports the has_more flag of the last page.
+func (p *Paginator[T]) HasMore() bool {
+	return p.hasMore
+}
+
+// PageToken returns the token for the page after the last one fetched.
+func (p *Paginator[T]) PageToken() string {
+	return p.pageToken
+}
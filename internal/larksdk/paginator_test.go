@@ -0,0 +1,75 @@
+package larksdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPaginatorFollowsPageTokens(t *testing.T) {
+	var tokens []string
+	pager := NewPaginator(func(ctx context.Context, pageToken string) (Page[int], error) {
+		tokens = append(tokens, pageToken)
+		switch pageToken {
+		case "":
+			return Page[int]{Items: []int{1, 2}, PageToken: "p2", HasMore: true}, nil
+		case "p2":
+			return Page[int]{Items: []int{3}, HasMore: false}, nil
+		}
+		t.Fatalf("unexpected page token %q", pageToken)
+		return Page[int]{}, nil
+	})
+
+	var items []int
+	for !pager.Done() {
+		page, err := pager.Next(context.Background())
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		items = append(items, page...)
+	}
+	if len(items) != 3 || pager.Pages() != 2 || pager.HasMore() {
+		t.Fatalf("unexpected result: items=%v pages=%d has_more=%t", items, pager.Pages(), pager.HasMore())
+	}
+	if len(tokens) != 2 || tokens[1] != "p2" {
+		t.Fatalf("unexpected tokens: %v", tokens)
+	}
+}
+
+func TestPaginatorStopsOnRepeatedOrEmptyToken(t *testing.T) {
+	calls := 0
+	pager := NewPaginator(func(ctx context.Context, pageToken string) (Page[int], error) {
+		calls++
+		return Page[int]{Items: []int{calls}, PageToken: "same", HasMore: true}, nil
+	})
+	for i := 0; i < 5 && !pager.Done(); i++ {
+		if _, err := pager.Next(context.Background()); err != nil {
+			t.Fatalf("next: %v", err)
+		}
+	}
+	if calls != 2 {
+		t.Fatalf("expected repeated token to stop after 2 calls, got %d", calls)
+	}
+
+	pager = NewPaginator(func(ctx context.Context, pageToken string) (Page[int], error) {
+		return Page[int]{HasMore: true}, nil
+	})
+	if _, err := pager.Next(context.Background()); err != nil || !pager.Done() {
+		t.Fatalf("expected empty token to finish, err=%v done=%t", err, pager.Done())
+	}
+}
+
+func TestPaginatorCanceledContext(t *testing.T) {
+	pager := NewPaginator(func(ctx context.Context, pageToken string) (Page[int], error) {
+		t.Fatal("fetch should not run with a canceled context")
+		return Page[int]{}, nil
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := pager.Next(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if pager.Pages() != 0 {
+		t.Fatalf("expected no pages, got %d", pager.Pages())
+	}
+}
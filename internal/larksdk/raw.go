@@ -0,0 +1,70 @@
+package larksdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	larkcore "github.com/larksuite/oapi-sdk-go/v3/core"
+)
+
+// RawRequest is an arbitrary OpenAPI call. Path must start with /open-apis/;
+// Body is serialized as JSON when non-nil.
+type RawRequest struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   any
+}
+
+type RawResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// RawRequest sends req with the given access token and returns the response
+// without interpreting the body, so callers see API errors verbatim.
+func (c *Client) RawRequest(ctx context.Context, token string, tokenType AccessTokenType, req RawRequest) (RawResponse, error) {
+	if !c.available() || c.coreConfig == nil {
+		return RawResponse{}, ErrUnavailable
+	}
+	method := strings.ToUpper(strings.TrimSpace(req.Method))
+	if method == "" {
+		return RawResponse{}, errors.New("method is required")
+	}
+	if !strings.HasPrefix(req.Path, "/open-apis/") {
+		return RawResponse{}, fmt.Errorf("path must start with /open-apis/: %s", req.Path)
+	}
+	option, _, err := c.accessTokenOption(token, tokenType)
+	if err != nil {
+		return RawResponse{}, err
+	}
+	query := larkcore.QueryParams{}
+	for key, values := range req.Query {
+		query[key] = values
+	}
+	apiReq := &larkcore.ApiReq{
+		ApiPath:                   req.Path,
+		HttpMethod:                method,
+		PathParams:                larkcore.PathParams{},
+		QueryParams:               query,
+		Body:                      req.Body,
+		SupportedAccessTokenTypes: []larkcore.AccessTokenType{larkcore.AccessTokenTypeTenant, larkcore.AccessTokenTypeUser},
+	}
+	apiResp, err := larkcore.Request(ctx, apiReq, c.coreConfig, option)
+	if err != nil {
+		return RawResponse{}, err
+	}
+	if apiResp == nil {
+		return RawResponse{}, errors.New("api request failed: empty response")
+	}
+	return RawResponse{
+		StatusCode: apiResp.StatusCode,
+		Header:     apiResp.Header,
+		Body:       apiResp.RawBody,
+	}, nil
+}
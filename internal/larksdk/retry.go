@@ -0,0 +1,255 @@
+package larksdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxRetries is the retry budget used when a caller enables retries
+// without choosing an explicit limit.
+const DefaultMaxRetries = 3
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 30 * time.Second
+
+	// Rate-limit error bodies are tiny, so only this much of a response is
+	// buffered to look for a business error code; larger bodies pass through.
+	maxRetryInspectBody = 4 << 10
+)
+
+// rateLimitCodes are business error codes returned (often with HTTP 200/400)
+// when the OpenAPI gateway or a product backend throttles a request.
+var rateLimitCodes = map[int]bool{
+	99991400: true, // request trigger frequency limit (gateway)
+	1061045:  true, // drive: frequency limit, retry later
+	1254290:  true, // bitable: too many requests
+	1254291:  true, // bitable: write conflict, retry later
+	230020:   true, // im: frequency limit
+}
+
+// RetryPolicy controls how failed requests are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of additional attempts after the first one.
+	// Zero disables retries.
+	MaxRetries int
+	// BaseDelay is the initial backoff delay (default: 500ms).
+	BaseDelay time.Duration
+	// MaxDelay caps a single backoff or server-requested wait (default: 30s).
+	MaxDelay time.Duration
+	// RetryNonIdempotent allows POST/PATCH requests to be retried on 5xx
+	// responses. Rate-limited requests are always safe to retry because the
+	// gateway rejects them before they reach the backend.
+	RetryNonIdempotent bool
+}
+
+// WithRetry enables automatic retries for HTTP 429, 5xx, and rate-limit
+// business codes using jittered exponential backoff.
+func WithRetry(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retry = &policy
+	}
+}
+
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+	sleep  func(ctx context.Context, d time.Duration) error
+}
+
+func newRetryTransport(base http.RoundTripper, policy RetryPolicy) *retryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if policy.BaseDelay <= 0 {
+		policy.BaseDelay = defaultRetryBaseDelay
+	}
+	if policy.MaxDelay <= 0 {
+		policy.MaxDelay = defaultRetryMaxDelay
+	}
+	return &retryTransport{base: base, policy: policy, sleep: sleepContext}
+}
+
+// wrapHTTPClientWithRetry returns a shallow copy of httpClient whose transport
+// retries according to policy. The caller's client is never mutated.
+func wrapHTTPClientWithRetry(httpClient *http.Client, policy RetryPolicy) *http.Client {
+	if policy.MaxRetries <= 0 {
+		return httpClient
+	}
+	wrapped := &http.Client{}
+	if httpClient != nil {
+		*wrapped = *httpClient
+	}
+	wrapped.Transport = newRetryTransport(wrapped.Transport, policy)
+	return wrapped
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.policy.MaxRetries || !t.canReplay(req) {
+			return resp, err
+		}
+		wait, retry := t.shouldRetry(req, resp, err)
+		if !retry {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxRetryInspectBody))
+			resp.Body.Close()
+		}
+		if wait <= 0 {
+			wait = t.backoff(attempt)
+		}
+		if wait > t.policy.MaxDelay {
+			wait = t.policy.MaxDelay
+		}
+		if err := t.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *retryTransport) canReplay(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return true
+	}
+	return req.GetBody != nil
+}
+
+// shouldRetry reports whether the attempt should be retried and, when the
+// server said so, how long to wait before the next attempt.
+func (t *retryTransport) shouldRetry(req *http.Request, resp *http.Response, err error) (time.Duration, bool) {
+	if err != nil {
+		if req.Context().Err() != nil {
+			return 0, false
+		}
+		return 0, isIdempotentMethod(req.Method) || t.policy.RetryNonIdempotent
+	}
+	if resp == nil {
+		return 0, false
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return retryAfter(resp.Header), true
+	}
+	if resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented {
+		if !isIdempotentMethod(req.Method) && !t.policy.RetryNonIdempotent {
+			return 0, false
+		}
+		return retryAfter(resp.Header), true
+	}
+	if isRateLimitedBody(resp) {
+		return retryAfter(resp.Header), true
+	}
+	return 0, false
+}
+
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	delay := t.policy.BaseDelay << attempt
+	if delay <= 0 || delay > t.policy.MaxDelay {
+		delay = t.policy.MaxDelay
+	}
+	// Equal jitter: keep half the delay and randomize the rest.
+	half := delay / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+// isRateLimitedBody peeks at a JSON response for rate-limit business codes and
+// restores the body so callers can read it again. Only a short prefix is
+// read; the rest of the body and its Close stay with the original reader.
+func isRateLimitedBody(resp *http.Response) bool {
+	if resp.Body == nil || !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return false
+	}
+	if resp.ContentLength > maxRetryInspectBody {
+		return false
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxRetryInspectBody+1))
+	rest := resp.Body
+	if err != nil {
+		// Surface the read error to the caller instead of a cut-short body.
+		rest = readCloser{Reader: errReader{err}, Closer: resp.Body}
+	}
+	resp.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(data), rest), Closer: resp.Body}
+	if err != nil || len(data) > maxRetryInspectBody {
+		return false
+	}
+	var payload struct {
+		Code int `json:"code"`
+	}
+	if json.Unmarshal(data, &payload) != nil {
+		return false
+	}
+	return rateLimitCodes[payload.Code]
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
+
+// retryAfter reads the server-provided wait hint. Feishu sends
+// x-ogw-ratelimit-reset (seconds until the window resets); generic gateways
+// send Retry-After as seconds or an HTTP date.
+func retryAfter(header http.Header) time.Duration {
+	if value := strings.TrimSpace(header.Get("x-ogw-ratelimit-reset")); value != "" {
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+			return time.Duration(seconds * float64(time.Second))
+		}
+	}
+	value := strings.TrimSpace(header.Get("Retry-After"))
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+func isIdempotentMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
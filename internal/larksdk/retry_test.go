@@ -0,0 +1,225 @@
+package larksdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"lark/internal/config"
+)
+
+func newRetryTestClient(t *testing.T, handler http.Handler, policy RetryPolicy) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	if policy.MaxDelay == 0 {
+		policy.MaxDelay = 5 * time.Millisecond
+	}
+	if policy.BaseDelay == 0 {
+		policy.BaseDelay = time.Millisecond
+	}
+	client, err := New(&config.Config{AppID: "app", AppSecret: "secret", BaseURL: server.URL}, WithRetry(policy))
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	return client
+}
+
+func writeDriveFile(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"code": 0,
+		"msg":  "ok",
+		"data": map[string]any{"file": map[string]any{"token": "f1", "name": "Doc"}},
+	})
+}
+
+func TestRetryOn429HonoursRateLimitReset(t *testing.T) {
+	calls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.Header().Set("x-ogw-ratelimit-reset", "0.001")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		writeDriveFile(w)
+	})
+	client := newRetryTestClient(t, handler, RetryPolicy{MaxRetries: 2})
+
+	file, err := client.GetDriveFileMetadata(context.Background(), "token", GetDriveFileRequest{FileToken: "f1"})
+	if err != nil {
+		t.Fatalf("get drive file: %v", err)
+	}
+	if file.Token != "f1" {
+		t.Fatalf("unexpected file: %+v", file)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+}
+
+func TestRetryOnFrequencyLimitCode(t *testing.T) {
+	calls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"code": 99991400, "msg": "request trigger frequency limit"})
+			return
+		}
+		writeDriveFile(w)
+	})
+	client := newRetryTestClient(t, handler, RetryPolicy{MaxRetries: 3})
+
+	if _, err := client.GetDriveFileMetadata(context.Background(), "token", GetDriveFileRequest{FileToken: "f1"}); err != nil {
+		t.Fatalf("get drive file: %v", err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetryGivesUpAfterMaxRetries(t *testing.T) {
+	calls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	client := newRetryTestClient(t, handler, RetryPolicy{MaxRetries: 2})
+
+	if _, err := client.GetDriveFileMetadata(context.Background(), "token", GetDriveFileRequest{FileToken: "f1"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetrySkipsNonIdempotentServerErrors(t *testing.T) {
+	calls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	client := newRetryTestClient(t, handler, RetryPolicy{MaxRetries: 3})
+
+	if _, err := client.SearchDriveFiles(context.Background(), "token", SearchDriveFilesRequest{Query: "q"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetryReplaysBodyForRateLimitedPost(t *testing.T) {
+	var bodies []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		if len(bodies) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"code": 0,
+			"data": map[string]any{"files": []map[string]any{{"token": "f1"}}},
+		})
+	})
+	client := newRetryTestClient(t, handler, RetryPolicy{MaxRetries: 1})
+
+	result, err := client.SearchDriveFiles(context.Background(), "token", SearchDriveFilesRequest{Query: "report"})
+	if err != nil {
+		t.Fatalf("search drive files: %v", err)
+	}
+	if len(result.Files) != 1 {
+		t.Fatalf("unexpected files: %+v", result.Files)
+	}
+	if len(bodies) != 2 || bodies[0] != bodies[1] || !strings.Contains(bodies[1], "report") {
+		t.Fatalf("expected identical replayed bodies, got %q", bodies)
+	}
+}
+
+func TestRetryStopsOnContextCancel(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	client := newRetryTestClient(t, handler, RetryPolicy{MaxRetries: 3, MaxDelay: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.GetDriveFileMetadata(ctx, "token", GetDriveFileRequest{FileToken: "f1"}); err == nil {
+		t.Fatal("expected error")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("retry did not respect context cancellation")
+	}
+}
+
+func TestRetryAfterParsing(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		want   time.Duration
+	}{
+		{name: "none", header: http.Header{}, want: 0},
+		{name: "ogw reset", header: http.Header{"X-Ogw-Ratelimit-Reset": {"2"}}, want: 2 * time.Second},
+		{name: "retry-after seconds", header: http.Header{"Retry-After": {"3"}}, want: 3 * time.Second},
+		{name: "ogw wins", header: http.Header{"X-Ogw-Ratelimit-Reset": {"1"}, "Retry-After": {"9"}}, want: time.Second},
+		{name: "invalid", header: http.Header{"Retry-After": {"soon"}}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryAfter(tt.header); got != tt.want {
+				t.Fatalf("retryAfter()=%s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPassesLargeChunkedBodyThrough(t *testing.T) {
+	name := strings.Repeat("x", 2<<20)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		body := `{"code":0,"msg":"ok","data":{"file":{"token":"f1","name":"` + name + `"}}}`
+		// Flushing between chunks forces chunked encoding, so ContentLength is unknown.
+		for start := 0; start < len(body); start += 64 << 10 {
+			_, _ = io.WriteString(w, body[start:min(start+64<<10, len(body))])
+			w.(http.Flusher).Flush()
+		}
+	})
+	client := newRetryTestClient(t, handler, RetryPolicy{MaxRetries: 2})
+
+	file, err := client.GetDriveFileMetadata(context.Background(), "token", GetDriveFileRequest{FileToken: "f1"})
+	if err != nil {
+		t.Fatalf("get drive file: %v", err)
+	}
+	if len(file.Name) != len(name) {
+		t.Fatalf("body was cut short: got name of %d bytes", len(file.Name))
+	}
+}
+
+func TestIsRateLimitedBodyKeepsReadErrors(t *testing.T) {
+	readErr := errors.New("connection reset")
+	resp := &http.Response{
+		Header: http.Header{"Content-Type": []string{"application/json"}},
+		Body:   io.NopCloser(io.MultiReader(strings.NewReader(`{"code":0,"data":`), errReader{readErr})),
+	}
+	if isRateLimitedBody(resp) {
+		t.Fatalf("partial body should not count as rate limited")
+	}
+	data, err := io.ReadAll(resp.Body)
+	if !errors.Is(err, readErr) || string(data) != `{"code":0,"data":` {
+		t.Fatalf("expected prefix then read error, got %q %v", data, err)
+	}
+}
@@ -45,6 +45,9 @@ type DriveFile struct {
 	ParentID  string `json:"parent_token"`
 	OwnerID   string `json:"owner_id"`
 	OwnerType string `json:"owner_id_type"`
+	// CreatedTime and ModifiedTime are unix seconds as returned by Drive.
+	CreatedTime  string `json:"created_time,omitempty"`
+	ModifiedTime string `json:"modified_time,omitempty"`
 }
 
 type GetDriveFileRequest struct {
@@ -185,6 +188,7 @@ type SearchBaseRecordsRequest struct {
 	Sort            json.RawMessage `json:"sort,omitempty"`
 	AutomaticFields *bool           `json:"automatic_fields,omitempty"`
 	PageSize        int             `json:"page_size,omitempty"`
+	PageToken       string          `json:"-"`
 }
 
 type SearchBaseRecordsResult struct {
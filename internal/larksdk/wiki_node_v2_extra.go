@@ -57,8 +57,9 @@ func (c *Client) createWikiNodeV2(ctx context.Context, req CreateWikiNodeRequest
 	}
 	objToken := strings.TrimSpace(req.ObjToken)
 	originNodeToken := strings.TrimSpace(req.OriginNodeToken)
-	if objToken == "" && originNodeToken == "" {
-		return WikiNode{}, errors.New("obj token or origin node token is required")
+	// An origin node without an obj token creates a new, empty object.
+	if strings.TrimSpace(req.NodeType) == "shortcut" && originNodeToken == "" {
+		return WikiNode{}, errors.New("origin node token is required for shortcut nodes")
 	}
 
 	node := &larkwiki.Node{}
@@ -171,6 +172,83 @@ func (c *Client) moveWikiNodeV2(ctx context.Context, req MoveWikiNodeRequest, op
 	return convertWikiNode(resp.Data.Node), nil
 }
 
+type CopyWikiNodeRequest struct {
+	SpaceID               string
+	NodeToken             string
+	TargetParentNodeToken string
+	TargetSpaceID         string
+	// Title is optional; empty keeps the source title.
+	Title string
+}
+
+func (c *Client) CopyWikiNodeV2(ctx context.Context, token string, req CopyWikiNodeRequest) (WikiNode, error) {
+	if !c.available() {
+		return WikiNode{}, ErrUnavailable
+	}
+	tenantToken := c.tenantToken(token)
+	if tenantToken == "" {
+		return WikiNode{}, errors.New("tenant access token is required")
+	}
+	return c.copyWikiNodeV2(ctx, req, larkcore.WithTenantAccessToken(tenantToken))
+}
+
+func (c *Client) CopyWikiNodeV2WithUserToken(ctx context.Context, userAccessToken string, req CopyWikiNodeRequest) (WikiNode, error) {
+	if !c.available() {
+		return WikiNode{}, ErrUnavailable
+	}
+	userAccessToken = strings.TrimSpace(userAccessToken)
+	if userAccessToken == "" {
+		return WikiNode{}, errors.New("user access token is required")
+	}
+	return c.copyWikiNodeV2(ctx, req, larkcore.WithUserAccessToken(userAccessToken))
+}
+
+func (c *Client) copyWikiNodeV2(ctx context.Context, req CopyWikiNodeRequest, option larkcore.RequestOptionFunc) (WikiNode, error) {
+	if !c.available() {
+		return WikiNode{}, ErrUnavailable
+	}
+	spaceID := strings.TrimSpace(req.SpaceID)
+	if spaceID == "" {
+		return WikiNode{}, errors.New("space id is required")
+	}
+	nodeToken := strings.TrimSpace(req.NodeToken)
+	if nodeToken == "" {
+		return WikiNode{}, errors.New("node token is required")
+	}
+	targetParentToken := strings.TrimSpace(req.TargetParentNodeToken)
+	targetSpaceID := strings.TrimSpace(req.TargetSpaceID)
+	if targetParentToken == "" && targetSpaceID == "" {
+		return WikiNode{}, errors.New("target parent node token or target space id is required")
+	}
+
+	body := &larkwiki.CopySpaceNodeReqBody{}
+	if targetParentToken != "" {
+		body.TargetParentToken = &targetParentToken
+	}
+	if targetSpaceID != "" {
+		body.TargetSpaceId = &targetSpaceID
+	}
+	if title := strings.TrimSpace(req.Title); title != "" {
+		body.Title = &title
+	}
+
+	builder := larkwiki.NewCopySpaceNodeReqBuilder().SpaceId(spaceID).NodeToken(nodeToken).Body(body)
+	resp, err := c.sdk.Wiki.V2.SpaceNode.Copy(ctx, builder.Build(), option)
+	if err != nil {
+		return WikiNode{}, err
+	}
+	if resp == nil {
+		return WikiNode{}, errors.New("wiki node copy failed: empty response")
+	}
+	if !resp.Success() {
+		return WikiNode{}, fmt.Errorf("wiki node copy failed: %s", resp.Msg)
+	}
+	if resp.Data == nil || resp.Data.Node == nil {
+		return WikiNode{}, errors.New("wiki node copy failed: missing node")
+	}
+	return convertWikiNode(resp.Data.Node), nil
+}
+
 type UpdateWikiNodeTitleRequest struct {
 	SpaceID   string
 	NodeToken string
@@ -0,0 +1,264 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/itchyny/gojq"
+)
+
+// Filter reshapes a command payload before it is written. Fields selection
+// runs first, then the jq expression, then the template (or JSON encoding).
+type Filter struct {
+	Fields   [][]string
+	code     *gojq.Code
+	template *template.Template
+}
+
+// NewFilter compiles the --fields, --jq, and --template options. It returns a
+// nil Filter when no option is set.
+func NewFilter(fields []string, jq, tmpl string) (*Filter, error) {
+	f := &Filter{}
+	for _, field := range fields {
+		for _, part := range strings.Split(field, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			f.Fields = append(f.Fields, strings.Split(part, "."))
+		}
+	}
+	if jq = strings.TrimSpace(jq); jq != "" {
+		query, err := gojq.Parse(jq)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --jq expression: %w", err)
+		}
+		code, err := gojq.Compile(query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --jq expression: %w", err)
+		}
+		f.code = code
+	}
+	if tmpl != "" {
+		parsed, err := template.New("output").Funcs(templateFuncs).Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --template: %w", err)
+		}
+		f.template = parsed
+	}
+	if len(f.Fields) == 0 && f.code == nil && f.template == nil {
+		return nil, nil
+	}
+	return f, nil
+}
+
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"join": func(sep string, v any) string {
+		items, ok := v.([]any)
+		if !ok {
+			return fmt.Sprint(v)
+		}
+		parts := make([]string, len(items))
+		for i, item := range items {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, sep)
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// Apply returns the filtered results for v. A jq expression may yield zero or
+// more results; otherwise there is exactly one.
+func (f *Filter) Apply(ctx context.Context, v any) ([]any, error) {
+	value, err := normalizeJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Fields) > 0 {
+		value = selectFields(value, f.Fields)
+	}
+	if f.code == nil {
+		return []any{value}, nil
+	}
+	results := make([]any, 0, 1)
+	iter := f.code.RunWithContext(ctx, value)
+	for {
+		result, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := result.(error); ok {
+			var halt *gojq.HaltError
+			if errors.As(err, &halt) && halt.Value() == nil {
+				break
+			}
+			return nil, fmt.Errorf("jq: %w", err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Write applies the filter and writes each result: through the template when
+// set, otherwise strings raw and other values as indented JSON.
+func (f *Filter) Write(w io.Writer, v any) error {
+	results, err := f.Apply(context.Background(), v)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if f.template != nil {
+			var buf bytes.Buffer
+			if err := f.template.Execute(&buf, result); err != nil {
+				return fmt.Errorf("template: %w", err)
+			}
+			if buf.Len() == 0 || buf.Bytes()[buf.Len()-1] != '\n' {
+				buf.WriteByte('\n')
+			}
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return err
+			}
+			continue
+		}
+		if s, ok := result.(string); ok && f.code != nil {
+			if _, err := fmt.Fprintln(w, s); err != nil {
+				return err
+			}
+			continue
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// normalizeJSON round-trips v through encoding/json so struct payloads become
+// the maps and slices jq operates on. Integers stay exact.
+func normalizeJSON(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return convertNumbers(out), nil
+}
+
+func convertNumbers(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for key, item := range value {
+			value[key] = convertNumbers(item)
+		}
+		return value
+	case []any:
+		for i, item := range value {
+			value[i] = convertNumbers(item)
+		}
+		return value
+	case json.Number:
+		if n, err := value.Int64(); err == nil && int64(int(n)) == n {
+			return int(n)
+		}
+		f, _ := value.Float64()
+		return f
+	default:
+		return v
+	}
+}
+
+// selectFields keeps only the given dotted paths. Arrays are filtered element
+// by element, and wrapper objects that hold none of the requested top-level
+// keys (for example {"chats": [...]}) are descended into, so --fields works on
+// list payloads without naming the wrapper.
+func selectFields(v any, paths [][]string) any {
+	switch value := v.(type) {
+	case []any:
+		out := make([]any, len(value))
+		for i, item := range value {
+			out[i] = selectFields(item, paths)
+		}
+		return out
+	case map[string]any:
+		matched := false
+		for _, path := range paths {
+			if _, ok := value[path[0]]; ok {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			out := make(map[string]any, len(value))
+			for key, item := range value {
+				switch item.(type) {
+				case []any, map[string]any:
+					out[key] = selectFields(item, paths)
+				}
+			}
+			return out
+		}
+		out := map[string]any{}
+		for _, path := range paths {
+			selectPath(value, out, path)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func selectPath(src, dst map[string]any, path []string) {
+	item, ok := src[path[0]]
+	if !ok {
+		return
+	}
+	if len(path) == 1 {
+		dst[path[0]] = item
+		return
+	}
+	switch nested := item.(type) {
+	case map[string]any:
+		child, _ := dst[path[0]].(map[string]any)
+		if child == nil {
+			child = map[string]any{}
+			dst[path[0]] = child
+		}
+		selectPath(nested, child, path[1:])
+	case []any:
+		existing, _ := dst[path[0]].([]any)
+		if len(existing) != len(nested) {
+			existing = make([]any, len(nested))
+			dst[path[0]] = existing
+		}
+		for i, elem := range nested {
+			elemMap, ok := elem.(map[string]any)
+			if !ok {
+				continue
+			}
+			child, _ := existing[i].(map[string]any)
+			if child == nil {
+				child = map[string]any{}
+				existing[i] = child
+			}
+			selectPath(elemMap, child, path[1:])
+		}
+	}
+}
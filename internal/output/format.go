@@ -0,0 +1,265 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by --output.
+const (
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+	FormatTSV    = "tsv"
+	FormatTable  = "table"
+	FormatYAML   = "yaml"
+)
+
+var formats = []string{FormatJSON, FormatNDJSON, FormatCSV, FormatTSV, FormatTable, FormatYAML}
+
+var tsvCellReplacer = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+
+// ParseFormat validates an --output value. An empty value is returned as-is
+// so callers can fall back to --json/--plain.
+func ParseFormat(value string) (string, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return "", nil
+	}
+	for _, format := range formats {
+		if value == format {
+			return value, nil
+		}
+	}
+	return "", fmt.Errorf("invalid output format %q (expected %s)", value, strings.Join(formats, "|"))
+}
+
+// Streaming reports whether list commands should emit items as each page
+// arrives (via PrintItems) instead of printing one document at the end.
+func (p Printer) Streaming() bool {
+	return p.Format == FormatNDJSON && p.Filter == nil
+}
+
+// PrintItems writes every element of items (a slice) as one NDJSON line.
+func (p Printer) PrintItems(items any) error {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return fmt.Errorf("ndjson items must be a list: %w", err)
+	}
+	return writeNDJSON(p.Writer, raws)
+}
+
+func (p Printer) printNDJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	items, ok := listItems(data)
+	if !ok {
+		items = []json.RawMessage{data}
+	}
+	return writeNDJSON(p.Writer, items)
+}
+
+func writeNDJSON(w io.Writer, items []json.RawMessage) error {
+	var buf bytes.Buffer
+	for _, item := range items {
+		buf.Reset()
+		if err := json.Compact(&buf, item); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p Printer) printYAML(v any) error {
+	value, err := normalizeJSON(v)
+	if err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(p.Writer)
+	enc.SetIndent(2)
+	if err := enc.Encode(value); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// printDelimited renders the payload's list as CSV or TSV. Columns follow the
+// JSON field order of the items; nested objects are flattened to dotted
+// names and arrays are written as JSON.
+func (p Printer) printDelimited(v any, comma rune) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	items, ok := listItems(data)
+	if !ok {
+		items = []json.RawMessage{data}
+	}
+	columns := make([]string, 0)
+	seen := map[string]bool{}
+	rows := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		row := map[string]string{}
+		if err := flattenJSON(item, "", row, func(column string) {
+			if !seen[column] {
+				seen[column] = true
+				columns = append(columns, column)
+			}
+		}); err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+	if comma == '\t' {
+		lines := make([][]string, 0, len(rows))
+		for _, row := range rows {
+			line := make([]string, len(columns))
+			for i, column := range columns {
+				line[i] = tsvCellReplacer.Replace(row[column])
+			}
+			lines = append(lines, line)
+		}
+		if len(columns) == 0 {
+			return nil
+		}
+		_, err := fmt.Fprintln(p.Writer, TableTSV(columns, lines))
+		return err
+	}
+	w := csv.NewWriter(p.Writer)
+	if len(columns) > 0 {
+		if err := w.Write(columns); err != nil {
+			return err
+		}
+	}
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = row[column]
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// listItems finds the record list in a payload: the payload itself when it is
+// an array, otherwise its "items" field or its only array-valued field.
+func listItems(data []byte) ([]json.RawMessage, bool) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, false
+	}
+	var items []json.RawMessage
+	if trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, false
+		}
+		return items, true
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &fields); err != nil {
+		return nil, false
+	}
+	key := ""
+	if raw, ok := fields["items"]; ok && isJSONArray(raw) {
+		key = "items"
+	} else {
+		names := make([]string, 0, len(fields))
+		for name, raw := range fields {
+			if isJSONArray(raw) {
+				names = append(names, name)
+			}
+		}
+		if len(names) != 1 {
+			return nil, false
+		}
+		key = names[0]
+	}
+	if err := json.Unmarshal(fields[key], &items); err != nil {
+		return nil, false
+	}
+	return items, true
+}
+
+func isJSONArray(raw json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(raw)
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// flattenJSON walks one JSON value in document order so column order matches
+// the struct field order of the encoded payload.
+func flattenJSON(raw json.RawMessage, prefix string, row map[string]string, addColumn func(string)) error {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return nil
+	}
+	switch trimmed[0] {
+	case '{':
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		dec.UseNumber()
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := tok.(string)
+			var value json.RawMessage
+			if err := dec.Decode(&value); err != nil {
+				return err
+			}
+			name := key
+			if prefix != "" {
+				name = prefix + "." + key
+			}
+			if err := flattenJSON(value, name, row, addColumn); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	column := prefix
+	if column == "" {
+		column = "value"
+	}
+	addColumn(column)
+	switch trimmed[0] {
+	case '"':
+		var s string
+		if err := json.Unmarshal(trimmed, &s); err != nil {
+			return err
+		}
+		row[column] = s
+	case 'n':
+		row[column] = ""
+	case '[':
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, trimmed); err != nil {
+			return err
+		}
+		row[column] = buf.String()
+	default:
+		row[column] = string(trimmed)
+	}
+	return nil
+}
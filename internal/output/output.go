@@ -16,9 +16,28 @@ type Printer struct {
 	Writer io.Writer
 	JSON   bool
 	Styled bool
+	// Format is the --output format; empty means the default text/JSON
+	// rendering selected by JSON.
+	Format string
+	// Filter, when set, replaces both text and JSON rendering with the
+	// --fields/--jq/--template pipeline.
+	Filter *Filter
 }
 
 func (p Printer) Print(v any, text string) error {
+	if p.Filter != nil {
+		return p.Filter.Write(p.Writer, v)
+	}
+	switch p.Format {
+	case FormatNDJSON:
+		return p.printNDJSON(v)
+	case FormatYAML:
+		return p.printYAML(v)
+	case FormatCSV:
+		return p.printDelimited(v, ',')
+	case FormatTSV:
+		return p.printDelimited(v, '\t')
+	}
 	if p.JSON {
 		enc := json.NewEncoder(p.Writer)
 		enc.SetIndent("", "  ")